@@ -0,0 +1,128 @@
+// Package apperrors defines the typed errors returned by services and
+// repositories. Each error has a kind, which decides the HTTP status, and a
+// stable code that clients can switch on.
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an error by how the client should react to it.
+type Kind int
+
+// Error kinds, each mapping to one HTTP status.
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnsupportedMediaType
+	KindTooManyRequests
+)
+
+// Status returns the HTTP status code for the kind.
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is an error with a kind and a stable, machine-readable code such as
+// "todo_not_found". Its message is safe to show to clients.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Err is the underlying cause, if any. It is never shown to clients.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Validation returns an error for invalid client input (400).
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// Unauthorized returns an error for missing or invalid credentials (401).
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden returns an error for an operation the client may not perform (403).
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// NotFound returns an error for a resource that does not exist or is not
+// visible to the client (404).
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict returns an error for a request that conflicts with the current
+// state of a resource (409).
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// UnsupportedMediaType returns an error for a request body in a format the
+// endpoint does not accept (415).
+func UnsupportedMediaType(code, message string) *Error {
+	return &Error{Kind: KindUnsupportedMediaType, Code: code, Message: message}
+}
+
+// TooManyRequests returns an error for a client that made too many attempts
+// and has to wait before trying again (429).
+func TooManyRequests(code, message string) *Error {
+	return &Error{Kind: KindTooManyRequests, Code: code, Message: message}
+}
+
+// Internal wraps an unexpected error. Its cause is logged but not shown to clients.
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "an unexpected error occurred", Err: err}
+}
+
+// As returns the first *Error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// IsKind reports whether err's chain contains an *Error of the given kind.
+func IsKind(err error, kind Kind) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Kind == kind
+}
+
+// IsNotFound reports whether err is a not found error.
+func IsNotFound(err error) bool {
+	return IsKind(err, KindNotFound)
+}
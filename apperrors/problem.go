@@ -0,0 +1,20 @@
+package apperrors
+
+// ProblemContentType is the media type of problem details responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Code is stable across
+// releases; Detail is meant for humans and may change.
+type Problem struct {
+	Type     string `json:"type" example:"about:blank"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"todo not found"`
+	Instance string `json:"instance,omitempty" example:"/todos/60d21b4667d0d8992e610c85"`
+	Code     string `json:"code" example:"todo_not_found"`
+	// Errors holds a message per invalid field for validation_failed problems.
+	Errors map[string]string `json:"errors,omitempty"`
+	// Position and Token locate the offending token for invalid_filter problems.
+	Position *int   `json:"position,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
@@ -6,10 +6,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 	"todo-list-api/config"
+	"todo-list-api/models"
+	"todo-list-api/notifier"
+	"todo-list-api/repository"
 	"todo-list-api/routes"
+	"todo-list-api/scheduler"
 
 	"github.com/gin-gonic/gin"
 
@@ -21,6 +33,26 @@ func main() {
 	// Load configuration and connect to MongoDB
 	config.LoadConfig()
 
+	// Make sure the indexes used by the repositories exist
+	if err := repository.EnsureIndexes(); err != nil {
+		log.Fatal("Failed to create MongoDB indexes:", err)
+	}
+
+	// Give the accounts listed in ADMIN_EMAILS the admin role
+	if emails := os.Getenv("ADMIN_EMAILS"); emails != "" {
+		promoteAdmins(strings.Split(emails, ","))
+	}
+
+	// Stop on SIGINT/SIGTERM so background jobs can release their leases
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start the reminder scheduler unless it is disabled for this instance
+	var jobs sync.WaitGroup
+	if os.Getenv("REMINDER_SCHEDULER") != "off" {
+		startReminderScheduler(ctx, &jobs)
+	}
+
 	// Initialize Gin router
 	router := gin.Default()
 
@@ -33,6 +65,71 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Server starting on port %s...", port)
-	log.Fatal(router.Run(":" + port))
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		log.Printf("Server starting on port %s...", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// Wait for a shutdown signal, then finish in-flight requests and let the
+	// scheduler release its lease so another replica can take over at once
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown: %v", err)
+	}
+	jobs.Wait()
+}
+
+// promoteAdmins gives the users with the given emails the admin role, so that
+// a deployment has an admin to manage the others. Unknown emails are logged;
+// such users are promoted on the next start after they register.
+func promoteAdmins(emails []string) {
+	userRepo := repository.NewUserRepository()
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		err := userRepo.SetRoleByEmail(email, models.RoleAdmin)
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Printf("ADMIN_EMAILS: no user with email %s", email)
+			continue
+		}
+		if err != nil {
+			log.Fatal("Failed to promote admins:", err)
+		}
+	}
+}
+
+// startReminderScheduler runs the reminder scheduler in the background using
+// the notifier configured through the environment, until ctx is cancelled.
+func startReminderScheduler(ctx context.Context, jobs *sync.WaitGroup) {
+	n, err := notifier.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure reminder notifier:", err)
+	}
+	interval := 30 * time.Second
+	if v := os.Getenv("REMINDER_INTERVAL"); v != "" {
+		if interval, err = time.ParseDuration(v); err != nil || interval <= 0 {
+			log.Fatal("Invalid REMINDER_INTERVAL:", v)
+		}
+	}
+	s := scheduler.NewReminderScheduler(
+		repository.NewTodoRepository(),
+		repository.NewUserRepository(),
+		repository.NewLockRepository(),
+		n,
+		interval,
+	)
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		s.Start(ctx)
+	}()
 }
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"net/http"
+	"todo-list-api/dto"
+	"todo-list-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessTokenController handles endpoints for managing personal access tokens.
+type AccessTokenController struct {
+	tokenService services.PersonalAccessTokenService
+}
+
+// NewAccessTokenController creates a new AccessTokenController instance.
+func NewAccessTokenController(tokenService services.PersonalAccessTokenService) *AccessTokenController {
+	return &AccessTokenController{tokenService}
+}
+
+// CreateToken handles creating a personal access token.
+//
+// @Summary Create a personal access token
+// @Description Create a named token for scripts and CI, sent as a bearer token like an access token. The token is only shown in this response; store it safely. Tokens without scopes have the default todos:read, todos:write and profile scopes, and tokens without expires_at do not expire. A token cannot be given scopes the current token lacks.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Param token body dto.PersonalAccessTokenRequest true "Token"
+// @Success 201 {object} dto.CreatedPersonalAccessTokenResponse
+// @Failure 400 {object} apperrors.Problem "Invalid token request or unknown scope"
+// @Failure 403 {object} apperrors.Problem "Request authenticated with a personal access token, or scope not held by the current token"
+// @Router /tokens [post]
+func (ac *AccessTokenController) CreateToken(c *gin.Context) {
+	if c.GetString("accessTokenID") != "" {
+		c.Error(services.ErrCreatedWithAccessToken)
+		return
+	}
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req dto.PersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	token := req.NewPersonalAccessToken(userObjID)
+	raw, err := ac.tokenService.CreateToken(token, c.GetStringSlice("scopes"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.CreatedPersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: dto.NewPersonalAccessTokenResponse(token),
+		Token:                       raw,
+	})
+}
+
+// GetTokens handles listing the user's personal access tokens.
+//
+// @Summary List personal access tokens
+// @Description Get the personal access tokens of the authenticated user, oldest first, without the tokens themselves
+// @Tags tokens
+// @Produce json
+// @Success 200 {array} dto.PersonalAccessTokenResponse
+// @Router /tokens [get]
+func (ac *AccessTokenController) GetTokens(c *gin.Context) {
+	tokens, err := ac.tokenService.GetTokens(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPersonalAccessTokenResponses(tokens))
+}
+
+// GetToken handles retrieving a single personal access token.
+//
+// @Summary Get a personal access token
+// @Description Get a personal access token of the authenticated user, without the token itself
+// @Tags tokens
+// @Produce json
+// @Param id path string true "Token ID"
+// @Success 200 {object} dto.PersonalAccessTokenResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /tokens/{id} [get]
+func (ac *AccessTokenController) GetToken(c *gin.Context) {
+	token, err := ac.tokenService.GetToken(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewPersonalAccessTokenResponse(token))
+}
+
+// RevokeToken handles revoking a personal access token.
+//
+// @Summary Revoke a personal access token
+// @Description Delete a personal access token; requests using it fail from then on
+// @Tags tokens
+// @Param id path string true "Token ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /tokens/{id} [delete]
+func (ac *AccessTokenController) RevokeToken(c *gin.Context) {
+	if err := ac.tokenService.RevokeToken(c.Param("id"), c.GetString("userID")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
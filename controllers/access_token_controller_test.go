@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-list-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateTokenRefusesAccessTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/tokens", strings.NewReader(`{"name": "CI"}`))
+	c.Set("userID", "60d21b4667d0d8992e610c85")
+	c.Set("accessTokenID", "60d21b4667d0d8992e610c90")
+
+	// A nil service shows that the request is refused before reaching it.
+	NewAccessTokenController(nil).CreateToken(c)
+	if err := c.Errors.Last(); err == nil || !errors.Is(err.Err, services.ErrCreatedWithAccessToken) {
+		t.Errorf("CreateToken() with a personal access token error = %v, want ErrCreatedWithAccessToken", err)
+	}
+}
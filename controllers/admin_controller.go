@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"net/http"
+	"todo-list-api/dto"
+	"todo-list-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController handles the user administration endpoints. Every request
+// is recorded in the audit log.
+type AdminController struct {
+	adminService services.AdminService
+}
+
+// NewAdminController creates a new AdminController instance.
+func NewAdminController(adminService services.AdminService) *AdminController {
+	return &AdminController{adminService}
+}
+
+// ListUsers handles listing and searching users.
+//
+// @Summary List users
+// @Description Get a page of users in registration order, optionally only those whose name or email contains q (case-insensitive). Admins only.
+// @Tags admin
+// @Produce json
+// @Param q query string false "Text to search in names and emails"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page limit" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} apperrors.Problem "Not an admin"
+// @Router /admin/users [get]
+func (ac *AdminController) ListUsers(c *gin.Context) {
+	page, limit, err := pagination(c)
+	if err != nil {
+		bindError(c, err)
+		return
+	}
+	users, total, err := ac.adminService.ListUsers(c.GetString("userID"), c.Query("q"), page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondPage(c, dto.NewAdminUserResponses(users), page, limit, total)
+}
+
+// GetUser handles retrieving a single user.
+//
+// @Summary Get a user
+// @Description Get any user's account details. Admins only.
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 403 {object} apperrors.Problem "Not an admin"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /admin/users/{id} [get]
+func (ac *AdminController) GetUser(c *gin.Context) {
+	user, err := ac.adminService.GetUser(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewAdminUserResponse(user))
+}
+
+// GetTodoCounts handles summarising a user's to-do items.
+//
+// @Summary Get a user's to-do counts
+// @Description Count any user's to-do items in total, per status and overdue. Admins only.
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.TodoCountsResponse
+// @Failure 403 {object} apperrors.Problem "Not an admin"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /admin/users/{id}/todo-counts [get]
+func (ac *AdminController) GetTodoCounts(c *gin.Context) {
+	userObjID, err := services.ParseID(c.Param("id"), services.ErrUserNotFound)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	counts, err := ac.adminService.GetTodoCounts(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTodoCountsResponse(userObjID, counts))
+}
+
+// SetRole handles changing a user's role.
+//
+// @Summary Change a user's role
+// @Description Make a user an admin or a regular user. Admins cannot change their own role. Admins only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role body dto.SetRoleRequest true "Role"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 400 {object} apperrors.Problem "Invalid role"
+// @Failure 403 {object} apperrors.Problem "Not an admin, or the admin's own account"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /admin/users/{id}/role [put]
+func (ac *AdminController) SetRole(c *gin.Context) {
+	var req dto.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	user, err := ac.adminService.SetRole(c.GetString("userID"), c.Param("id"), req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewAdminUserResponse(user))
+}
+
+// DisableUser handles disabling an account.
+//
+// @Summary Disable a user
+// @Description Lock a user out: their tokens, including personal access tokens, are rejected immediately and they cannot log in. Admins cannot disable their own account. Admins only.
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 403 {object} apperrors.Problem "Not an admin, or the admin's own account"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /admin/users/{id}/disable [post]
+func (ac *AdminController) DisableUser(c *gin.Context) {
+	user, err := ac.adminService.DisableUser(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewAdminUserResponse(user))
+}
+
+// EnableUser handles enabling a disabled account.
+//
+// @Summary Enable a user
+// @Description Let a disabled user log in again. Tokens issued before the account was disabled stay revoked. Admins only.
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 403 {object} apperrors.Problem "Not an admin"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /admin/users/{id}/enable [post]
+func (ac *AdminController) EnableUser(c *gin.Context) {
+	user, err := ac.adminService.EnableUser(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewAdminUserResponse(user))
+}
+
+// ForcePasswordReset handles forcing a user to reset their password.
+//
+// @Summary Force a password reset
+// @Description Revoke the user's access and refresh tokens and email them a password reset link. They cannot log in until they use it. Admins only.
+// @Tags admin
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} apperrors.Problem "Not an admin"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /admin/users/{id}/force-password-reset [post]
+func (ac *AdminController) ForcePasswordReset(c *gin.Context) {
+	if err := ac.adminService.ForcePasswordReset(c.GetString("userID"), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteUser handles deleting an account.
+//
+// @Summary Delete a user
+// @Description Delete a user with all their to-do items, projects, labels, smart lists, tokens, password resets and token revocations. The account is disabled first and removed last, so a failed deletion can be repeated. Admins cannot delete their own account. Admins only.
+// @Tags admin
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} apperrors.Problem "Not an admin, or the admin's own account"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /admin/users/{id} [delete]
+func (ac *AdminController) DeleteUser(c *gin.Context) {
+	if err := ac.adminService.DeleteUser(c.GetString("userID"), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetAuditLog handles listing the audit log.
+//
+// @Summary List the audit log
+// @Description Get a page of admin actions, newest first, optionally only those about one user. Admins only.
+// @Tags admin
+// @Produce json
+// @Param user_id query string false "Only actions about this user"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page limit" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} apperrors.Problem "Not an admin"
+// @Router /admin/audit-log [get]
+func (ac *AdminController) GetAuditLog(c *gin.Context) {
+	page, limit, err := pagination(c)
+	if err != nil {
+		bindError(c, err)
+		return
+	}
+	entries, total, err := ac.adminService.GetAuditLog(c.GetString("userID"), c.Query("user_id"), page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondPage(c, dto.NewAuditLogEntryResponses(entries), page, limit, total)
+}
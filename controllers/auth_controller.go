@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"net/http"
-	"todo-list-api/models"
+	"todo-list-api/dto"
 	"todo-list-api/services"
 
 	"github.com/gin-gonic/gin"
@@ -21,50 +23,336 @@ func NewAuthController(authService services.AuthService) *AuthController {
 // Register handles user registration.
 //
 // @Summary Register a new user
-// @Description Register a new user and return a JWT token
+// @Description Register a new user and return an access token and a refresh token
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param user body models.User true "User details"
-// @Success 200 {object} map[string]string "token"
+// @Param user body dto.RegisterRequest true "User details"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} apperrors.Problem "Invalid user details"
+// @Failure 409 {object} apperrors.Problem "User already exists"
 // @Router /register [post]
 func (ac *AuthController) Register(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
 		return
 	}
-	token, err := ac.authService.Register(&user)
+	tokens, err := ac.authService.Register(req.NewUser())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, dto.NewTokenResponse(tokens))
 }
 
 // Login handles user authentication.
 //
 // @Summary Login user
-// @Description Authenticate a user and return a JWT token
+// @Description Authenticate a user and return an access token and a refresh token. For users with MFA enabled, a challenge token for POST /login/mfa is returned instead.
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param credentials body map[string]string true "User credentials"
-// @Success 200 {object} map[string]string "token"
+// @Param credentials body dto.LoginRequest true "User credentials"
+// @Success 200 {object} dto.TokenResponse
+// @Success 202 {object} dto.MFAChallengeResponse "MFA code required"
+// @Failure 400 {object} apperrors.Problem "Invalid credentials format"
+// @Failure 401 {object} apperrors.Problem "Invalid email or password"
+// @Failure 429 {object} apperrors.Problem "MFA logins locked after too many wrong codes"
 // @Router /login [post]
 func (ac *AuthController) Login(c *gin.Context) {
-	var creds struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	result, err := ac.authService.Login(req.Email, req.Password)
+	if err != nil {
+		c.Error(err)
+		return
 	}
-	if err := c.ShouldBindJSON(&creds); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if result.MFAToken != "" {
+		c.JSON(http.StatusAccepted, dto.NewMFAChallengeResponse(result))
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTokenResponse(result.Tokens))
+}
+
+// LoginMFA completes a login with a second factor.
+//
+// @Summary Complete MFA login
+// @Description Exchange the challenge token returned by POST /login and a code from the authenticator app, or an unused recovery code, for an access token and a refresh token. Each code is accepted once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body dto.MFALoginRequest true "Challenge token and code"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} apperrors.Problem "Invalid body"
+// @Failure 401 {object} apperrors.Problem "Invalid or expired challenge, or invalid code"
+// @Failure 429 {object} apperrors.Problem "Too many wrong codes"
+// @Router /login/mfa [post]
+func (ac *AuthController) LoginMFA(c *gin.Context) {
+	var req dto.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
 		return
 	}
-	token, err := ac.authService.Login(creds.Email, creds.Password)
+	tokens, err := ac.authService.LoginMFA(req.MFAToken, req.Code)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTokenResponse(tokens))
+}
+
+// Refresh exchanges a refresh token for a new token pair.
+//
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token can be used once; reusing one revokes every token issued from the same login.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body dto.RefreshRequest true "Refresh token"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} apperrors.Problem "Missing refresh token"
+// @Failure 401 {object} apperrors.Problem "Invalid, expired or reused refresh token"
+// @Router /token/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	tokens, err := ac.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTokenResponse(tokens))
+}
+
+// Logout revokes the access token used for the request.
+//
+// @Summary Log out
+// @Description Revoke the access token used for this request. If a refresh token is given, every refresh token issued from the same login is revoked too.
+// @Tags auth
+// @Accept json
+// @Param body body dto.LogoutRequest false "Refresh token to revoke"
+// @Success 204 "Logged out"
+// @Failure 400 {object} apperrors.Problem "Invalid body"
+// @Failure 401 {object} apperrors.Problem "Missing, invalid or revoked token"
+// @Router /logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	// The body is optional.
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		bindError(c, err)
+		return
+	}
+	if err := ac.authService.Logout(c.GetString("userID"), c.GetString("tokenID"), c.GetTime("tokenExpiresAt"), req.RefreshToken); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll revokes every token of the current user.
+//
+// @Summary Log out everywhere
+// @Description Revoke every access token and refresh token of the current user, including the one used for this request, and delete their personal access tokens.
+// @Tags auth
+// @Success 204 "Logged out everywhere"
+// @Failure 401 {object} apperrors.Problem "Missing, invalid or revoked token"
+// @Router /logout-all [post]
+func (ac *AuthController) LogoutAll(c *gin.Context) {
+	if err := ac.authService.LogoutAll(c.GetString("userID")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword replaces the current user's password.
+//
+// @Summary Change password
+// @Description Change the current user's password. Every existing access token and refresh token of the user is revoked and their personal access tokens are deleted; log in again with the new password.
+// @Tags auth
+// @Accept json
+// @Param body body dto.ChangePasswordRequest true "Current and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} apperrors.Problem "Invalid body"
+// @Failure 401 {object} apperrors.Problem "Missing, invalid or revoked token"
+// @Failure 403 {object} apperrors.Problem "Current password is incorrect"
+// @Router /password [put]
+func (ac *AuthController) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if err := ac.authService.ChangePassword(c.GetString("userID"), req.CurrentPassword, req.NewPassword); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword emails a password reset link.
+//
+// @Summary Request a password reset
+// @Description Email a single-use password reset link to the address if it belongs to a user. The response is the same whether or not the address is registered.
+// @Tags auth
+// @Accept json
+// @Param body body dto.ForgotPasswordRequest true "Email address"
+// @Success 202 "Reset link sent if the email is registered"
+// @Failure 400 {object} apperrors.Problem "Invalid email"
+// @Router /password/forgot [post]
+func (ac *AuthController) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if err := ac.authService.ForgotPassword(req.Email); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword sets a new password using a reset token.
+//
+// @Summary Reset password
+// @Description Set a new password using the token from a password reset email. The token can be used once; every existing access token and refresh token of the user is revoked and their personal access tokens are deleted.
+// @Tags auth
+// @Accept json
+// @Param body body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 204 "Password reset"
+// @Failure 400 {object} apperrors.Problem "Invalid body, or invalid or expired reset token"
+// @Router /password/reset [post]
+func (ac *AuthController) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if err := ac.authService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// VerifyEmail marks the user's email address as verified.
+//
+// @Summary Verify email address
+// @Description Verify the email address using the token from the link in a verification email. Verifying twice succeeds.
+// @Tags auth
+// @Accept json
+// @Param body body dto.VerifyEmailRequest true "Verification token"
+// @Success 204 "Email verified"
+// @Failure 400 {object} apperrors.Problem "Invalid body, or invalid or expired verification token"
+// @Router /verify-email [post]
+func (ac *AuthController) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if err := ac.authService.VerifyEmail(req.Token); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification sends a new verification email.
+//
+// @Summary Resend verification email
+// @Description Send a new email verification link to the current user's address.
+// @Tags auth
+// @Success 202 "Verification email sent"
+// @Failure 401 {object} apperrors.Problem "Missing, invalid or revoked token"
+// @Failure 409 {object} apperrors.Problem "Email already verified"
+// @Router /verify-email/resend [post]
+func (ac *AuthController) ResendVerification(c *gin.Context) {
+	if err := ac.authService.ResendVerification(c.GetString("userID")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// EnrollMFA starts enabling MFA.
+//
+// @Summary Start MFA enrollment
+// @Description Create a TOTP secret for the current user and return it as an otpauth:// URI and a QR code. MFA is enabled once POST /mfa/confirm succeeds; enrolling again replaces an unconfirmed secret.
+// @Tags mfa
+// @Produce json
+// @Success 200 {object} dto.MFAEnrollmentResponse
+// @Failure 401 {object} apperrors.Problem "Missing, invalid or revoked token"
+// @Failure 409 {object} apperrors.Problem "MFA already enabled"
+// @Router /mfa/enroll [post]
+func (ac *AuthController) EnrollMFA(c *gin.Context) {
+	enrollment, err := ac.authService.EnrollMFA(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewMFAEnrollmentResponse(enrollment))
+}
+
+// ConfirmMFA enables MFA.
+//
+// @Summary Confirm MFA enrollment
+// @Description Enable MFA with a first code from the authenticator app. Returns recovery codes, each usable once in place of a code; they are not shown again.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param body body dto.MFACodeRequest true "Code from the authenticator app"
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} apperrors.Problem "Invalid body"
+// @Failure 401 {object} apperrors.Problem "Missing, invalid or revoked token"
+// @Failure 403 {object} apperrors.Problem "Incorrect code"
+// @Failure 409 {object} apperrors.Problem "MFA already enabled or no enrollment started"
+// @Router /mfa/confirm [post]
+func (ac *AuthController) ConfirmMFA(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	codes, err := ac.authService.ConfirmMFA(c.GetString("userID"), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA turns MFA off.
+//
+// @Summary Disable MFA
+// @Description Disable MFA for the current user. Requires a current code (or recovery code) or the password.
+// @Tags mfa
+// @Accept json
+// @Param body body dto.DisableMFARequest true "Code or password"
+// @Success 204 "MFA disabled"
+// @Failure 400 {object} apperrors.Problem "Neither code nor password given"
+// @Failure 401 {object} apperrors.Problem "Missing, invalid or revoked token"
+// @Failure 403 {object} apperrors.Problem "Incorrect code or password"
+// @Failure 409 {object} apperrors.Problem "MFA not enabled"
+// @Failure 429 {object} apperrors.Problem "Too many wrong codes or passwords"
+// @Router /mfa/disable [post]
+func (ac *AuthController) DisableMFA(c *gin.Context) {
+	var req dto.DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if err := ac.authService.DisableMFA(c.GetString("userID"), req.Code, req.Password); err != nil {
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"net/http"
+	"todo-list-api/dto"
+	"todo-list-api/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LabelController handles endpoints for managing a user's label catalogue.
+type LabelController struct {
+	labelService services.LabelService
+}
+
+// NewLabelController creates a new LabelController instance.
+func NewLabelController(labelService services.LabelService) *LabelController {
+	return &LabelController{labelService}
+}
+
+// CreateLabel handles creating a new label.
+//
+// @Summary Create a label
+// @Description Add a label to the authenticated user's catalogue
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param label body dto.LabelRequest true "Label"
+// @Success 201 {object} dto.LabelResponse
+// @Failure 400 {object} apperrors.Problem "Invalid label"
+// @Failure 409 {object} apperrors.Problem "Label already exists"
+// @Router /labels [post]
+func (lc *LabelController) CreateLabel(c *gin.Context) {
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req dto.LabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	label := req.NewLabel(primitive.NilObjectID, userObjID)
+	if err := lc.labelService.CreateLabel(label); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewLabelResponse(label))
+}
+
+// GetLabels handles listing the user's labels.
+//
+// @Summary List labels
+// @Description Get all labels of the authenticated user, sorted by name
+// @Tags labels
+// @Produce json
+// @Success 200 {array} dto.LabelResponse
+// @Router /labels [get]
+func (lc *LabelController) GetLabels(c *gin.Context) {
+	labels, err := lc.labelService.GetLabels(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewLabelResponses(labels))
+}
+
+// GetLabel handles retrieving a single label.
+//
+// @Summary Get a label
+// @Description Get a label of the authenticated user
+// @Tags labels
+// @Produce json
+// @Param id path string true "Label ID"
+// @Success 200 {object} dto.LabelResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /labels/{id} [get]
+func (lc *LabelController) GetLabel(c *gin.Context) {
+	label, err := lc.labelService.GetLabelByID(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewLabelResponse(label))
+}
+
+// UpdateLabel handles renaming or recoloring a label.
+//
+// @Summary Update a label
+// @Description Update a label's name and color; a rename is applied to every to-do item using the label
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param id path string true "Label ID"
+// @Param label body dto.LabelRequest true "Updated label"
+// @Success 200 {object} dto.LabelResponse
+// @Failure 400 {object} apperrors.Problem "Invalid label"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Failure 409 {object} apperrors.Problem "Label already exists"
+// @Router /labels/{id} [put]
+func (lc *LabelController) UpdateLabel(c *gin.Context) {
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	labelID, err := services.ParseID(c.Param("id"), services.ErrLabelNotFound)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req dto.LabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	label := req.NewLabel(labelID, userObjID)
+	if err := lc.labelService.UpdateLabel(label); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewLabelResponse(label))
+}
+
+// DeleteLabel handles deleting a label.
+//
+// @Summary Delete a label
+// @Description Delete a label and remove it from every to-do item using it
+// @Tags labels
+// @Param id path string true "Label ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /labels/{id} [delete]
+func (lc *LabelController) DeleteLabel(c *gin.Context) {
+	if err := lc.labelService.DeleteLabel(c.Param("id"), c.GetString("userID")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
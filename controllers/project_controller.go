@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"net/http"
+	"todo-list-api/dto"
+	"todo-list-api/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectController handles endpoints for managing projects (to-do lists).
+type ProjectController struct {
+	projectService services.ProjectService
+	todoService    services.TodoService
+}
+
+// NewProjectController creates a new ProjectController instance.
+func NewProjectController(projectService services.ProjectService, todoService services.TodoService) *ProjectController {
+	return &ProjectController{projectService, todoService}
+}
+
+// CreateProject handles creating a new project.
+//
+// @Summary Create a project
+// @Description Create a new project for the authenticated user
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param project body dto.ProjectRequest true "Project"
+// @Success 201 {object} dto.ProjectResponse
+// @Failure 400 {object} apperrors.Problem "Invalid project"
+// @Router /projects [post]
+func (pc *ProjectController) CreateProject(c *gin.Context) {
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req dto.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	project := req.NewProject(primitive.NilObjectID, userObjID)
+	if err := pc.projectService.CreateProject(project); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewProjectResponse(project))
+}
+
+// GetProjects handles listing the user's projects.
+//
+// @Summary List projects
+// @Description Get all projects of the authenticated user, inbox first
+// @Tags projects
+// @Produce json
+// @Success 200 {array} dto.ProjectResponse
+// @Router /projects [get]
+func (pc *ProjectController) GetProjects(c *gin.Context) {
+	projects, err := pc.projectService.GetProjects(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewProjectResponses(projects))
+}
+
+// GetProject handles retrieving a single project.
+//
+// @Summary Get a project
+// @Description Get a project of the authenticated user
+// @Tags projects
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} dto.ProjectResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /projects/{id} [get]
+func (pc *ProjectController) GetProject(c *gin.Context) {
+	project, err := pc.projectService.GetProjectByID(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewProjectResponse(project))
+}
+
+// UpdateProject handles renaming a project.
+//
+// @Summary Update a project
+// @Description Rename a project; the inbox cannot be renamed
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param project body dto.ProjectRequest true "Updated project"
+// @Success 200 {object} dto.ProjectResponse
+// @Failure 400 {object} apperrors.Problem "Invalid project"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Failure 403 {object} apperrors.Problem "The inbox cannot be renamed"
+// @Router /projects/{id} [put]
+func (pc *ProjectController) UpdateProject(c *gin.Context) {
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	projectID, err := services.ParseID(c.Param("id"), services.ErrProjectNotFound)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req dto.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	project := req.NewProject(projectID, userObjID)
+	if err := pc.projectService.UpdateProject(project); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewProjectResponse(project))
+}
+
+// DeleteProject handles deleting a project.
+//
+// @Summary Delete a project
+// @Description Delete a project, moving its to-do items to the inbox (mode=move, default) or deleting them (mode=cascade). The inbox cannot be deleted.
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param mode query string false "What to do with the project's to-do items" Enums(move, cascade) default(move)
+// @Success 204 "No Content"
+// @Failure 400 {object} apperrors.Problem "Invalid mode"
+// @Failure 403 {object} apperrors.Problem "The inbox cannot be deleted"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /projects/{id} [delete]
+func (pc *ProjectController) DeleteProject(c *gin.Context) {
+	if err := pc.projectService.DeleteProject(c.Param("id"), c.GetString("userID"), c.Query("mode")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetProjectTodos handles listing the to-do items in a project.
+//
+// @Summary Get a project's to-do items
+// @Description Get paginated to-do items in a project; accepts the same filters as GET /todos
+// @Tags projects
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page limit" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} apperrors.Problem "Invalid filter"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /projects/{id}/todos [get]
+func (pc *ProjectController) GetProjectTodos(c *gin.Context) {
+	project, err := pc.projectService.GetProjectByID(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter.ProjectID = project.ID
+	listTodos(c, pc.todoService, filter)
+}
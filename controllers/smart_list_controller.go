@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"net/http"
+	"todo-list-api/dto"
+	"todo-list-api/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SmartListController handles endpoints for managing saved filters (smart lists).
+type SmartListController struct {
+	smartListService services.SmartListService
+	todoService      services.TodoService
+}
+
+// NewSmartListController creates a new SmartListController instance.
+func NewSmartListController(smartListService services.SmartListService, todoService services.TodoService) *SmartListController {
+	return &SmartListController{smartListService, todoService}
+}
+
+// CreateSmartList handles saving a new smart list.
+//
+// @Summary Create a smart list
+// @Description Save a named to-do filter for the authenticated user
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param list body dto.SmartListRequest true "Smart list"
+// @Success 201 {object} dto.SmartListResponse
+// @Failure 400 {object} apperrors.Problem "Invalid smart list"
+// @Router /smart-lists [post]
+func (sc *SmartListController) CreateSmartList(c *gin.Context) {
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req dto.SmartListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	list := req.NewSmartList(primitive.NilObjectID, userObjID)
+	if err := sc.smartListService.CreateSmartList(list); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewSmartListResponse(list))
+}
+
+// GetSmartLists handles listing the user's smart lists.
+//
+// @Summary List smart lists
+// @Description Get the built-in smart lists (today, upcoming, overdue) followed by the authenticated user's own lists
+// @Tags smart-lists
+// @Produce json
+// @Success 200 {array} dto.SmartListResponse
+// @Router /smart-lists [get]
+func (sc *SmartListController) GetSmartLists(c *gin.Context) {
+	lists, err := sc.smartListService.GetSmartLists(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewSmartListResponses(lists))
+}
+
+// GetSmartList handles retrieving a single smart list.
+//
+// @Summary Get a smart list
+// @Description Get a smart list of the authenticated user by id, or a built-in list by key
+// @Tags smart-lists
+// @Produce json
+// @Param id path string true "Smart list ID or built-in key (today, upcoming, overdue)"
+// @Success 200 {object} dto.SmartListResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /smart-lists/{id} [get]
+func (sc *SmartListController) GetSmartList(c *gin.Context) {
+	list, err := sc.smartListService.GetSmartListByID(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewSmartListResponse(list))
+}
+
+// UpdateSmartList handles changing a smart list's name or filter.
+//
+// @Summary Update a smart list
+// @Description Replace the name and filter of a smart list; built-in lists cannot be changed
+// @Tags smart-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Smart list ID"
+// @Param list body dto.SmartListRequest true "Updated smart list"
+// @Success 200 {object} dto.SmartListResponse
+// @Failure 400 {object} apperrors.Problem "Invalid smart list"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Failure 403 {object} apperrors.Problem "Built-in list"
+// @Router /smart-lists/{id} [put]
+func (sc *SmartListController) UpdateSmartList(c *gin.Context) {
+	if services.IsBuiltInSmartList(c.Param("id")) {
+		c.Error(services.ErrBuiltInSmartList)
+		return
+	}
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	listID, err := services.ParseID(c.Param("id"), services.ErrSmartListNotFound)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req dto.SmartListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	list := req.NewSmartList(listID, userObjID)
+	if err := sc.smartListService.UpdateSmartList(list); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewSmartListResponse(list))
+}
+
+// DeleteSmartList handles deleting a smart list.
+//
+// @Summary Delete a smart list
+// @Description Delete a smart list; the to-do items it shows are not affected. Built-in lists cannot be deleted.
+// @Tags smart-lists
+// @Param id path string true "Smart list ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} apperrors.Problem "Built-in list"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /smart-lists/{id} [delete]
+func (sc *SmartListController) DeleteSmartList(c *gin.Context) {
+	if err := sc.smartListService.DeleteSmartList(c.Param("id"), c.GetString("userID")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSmartListTodos handles listing the to-do items matched by a smart list.
+//
+// @Summary Get a smart list's to-do items
+// @Description Run a smart list's saved filter through the same query path as GET /todos. Lists with search text return search results with scores and highlights.
+// @Tags smart-lists
+// @Produce json
+// @Param id path string true "Smart list ID or built-in key (today, upcoming, overdue)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page limit" default(10)
+// @Param tz query string false "IANA timezone overriding the list's saved timezone"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /smart-lists/{id}/todos [get]
+func (sc *SmartListController) GetSmartListTodos(c *gin.Context) {
+	list, err := sc.smartListService.GetSmartListByID(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter := list.Filter.TodoFilter()
+	if tz := c.Query("tz"); tz != "" {
+		filter.Timezone = tz
+	}
+	if list.Filter.Search == "" {
+		listTodos(c, sc.todoService, filter)
+		return
+	}
+	page, limit, err := pagination(c)
+	if err != nil {
+		bindError(c, err)
+		return
+	}
+	results, total, err := sc.todoService.SearchTodos(c.GetString("userID"), list.Filter.Search, filter, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondPage(c, dto.NewTodoSearchResultResponses(results), page, limit, total)
+}
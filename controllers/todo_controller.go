@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"todo-list-api/dto"
 	"todo-list-api/models"
 	"todo-list-api/services"
+	"todo-list-api/validation"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -27,100 +31,123 @@ func NewTodoController(todoService services.TodoService) *TodoController {
 // @Tags todos
 // @Accept json
 // @Produce json
-// @Param todo body models.Todo true "Todo item"
-// @Success 200 {object} models.Todo
-// @Failure 401 {object} map[string]string "Unauthorized"
+// @Param todo body dto.TodoRequest true "Todo item"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 401 {object} apperrors.Problem "Unauthorized"
 // @Router /todos [post]
 func (tc *TodoController) CreateTodo(c *gin.Context) {
-	userIDStr := c.GetString("userID")
-	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
+	userObjID, err := services.ParseUserID(c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		c.Error(err)
 		return
 	}
-	var todo models.Todo
-	if err := c.ShouldBindJSON(&todo); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.TodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
 		return
 	}
-	todo.UserID = userObjID
-	if err := tc.todoService.CreateTodo(&todo); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	todo := req.NewTodo(userObjID)
+	if err := tc.todoService.CreateTodo(todo); err != nil {
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, todo)
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
 }
 
 // UpdateTodo handles updating an existing to-do item.
 //
 // @Summary Update an existing to-do item
-// @Description Update a to-do item if the user is authorized (must be the creator)
+// @Description Replace a to-do item of the authenticated user. Fields left out of the request are cleared or take their defaults; use PATCH to change only some fields. Items of other users are reported as not found.
 // @Tags todos
 // @Accept json
 // @Produce json
 // @Param id path string true "Todo ID"
-// @Param todo body models.Todo true "Updated Todo item"
-// @Success 200 {object} models.Todo
-// @Failure 403 {object} map[string]string "Forbidden"
+// @Param todo body dto.TodoRequest true "Updated Todo item"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 400 {object} apperrors.Problem "Invalid to-do item"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Failure 409 {object} apperrors.Problem "The item kept changing concurrently"
 // @Router /todos/{id} [put]
 func (tc *TodoController) UpdateTodo(c *gin.Context) {
-	userIDStr := c.GetString("userID")
-	id := c.Param("id")
-
-	// Fetch the existing todo to verify the creator.
-	existing, err := tc.todoService.GetTodoByID(id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+	var req dto.TodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
 		return
 	}
-	if existing.UserID.Hex() != userIDStr {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Forbidden"})
+	todo, err := tc.todoService.UpdateTodo(c.Param("id"), c.GetString("userID"), &req)
+	if err != nil {
+		c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
+}
 
-	var todo models.Todo
-	if err := c.ShouldBindJSON(&todo); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// PatchTodo handles partially updating an existing to-do item.
+//
+// @Summary Partially update a to-do item
+// @Description Apply a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to a to-do item of the authenticated user, selected by Content-Type. Only the fields the patch changes are written; the result is validated like a full update. id, user_id, created_at, updated_at, completed_at, next_occurrence_id and progress are read-only.
+// @Tags todos
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 400 {object} apperrors.Problem "Invalid patch or resulting to-do item"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Failure 409 {object} apperrors.Problem "JSON Patch test operation failed, or the item kept changing concurrently"
+// @Failure 415 {object} apperrors.Problem "Unsupported patch type"
+// @Router /todos/{id} [patch]
+func (tc *TodoController) PatchTodo(c *gin.Context) {
+	patch, err := c.GetRawData()
+	if err != nil {
+		bindError(c, err)
 		return
 	}
-	todo.ID = existing.ID
-	todo.UserID = existing.UserID
-	if err := tc.todoService.UpdateTodo(&todo); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	todo, err := tc.todoService.PatchTodo(c.Param("id"), c.GetString("userID"), c.ContentType(), patch)
+	if err != nil {
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, todo)
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
 }
 
 // DeleteTodo handles deleting an existing to-do item.
 //
 // @Summary Delete a to-do item
-// @Description Delete a to-do item if the user is authorized (must be the creator)
+// @Description Delete a to-do item of the authenticated user. Items of other users are reported as not found.
 // @Tags todos
 // @Accept json
 // @Produce json
 // @Param id path string true "Todo ID"
 // @Success 204 "No Content"
-// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} apperrors.Problem "Not Found"
 // @Router /todos/{id} [delete]
 func (tc *TodoController) DeleteTodo(c *gin.Context) {
-	userIDStr := c.GetString("userID")
-	id := c.Param("id")
-
-	existing, err := tc.todoService.GetTodoByID(id)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Todo not found"})
+	if err := tc.todoService.DeleteTodo(c.Param("id"), c.GetString("userID")); err != nil {
+		c.Error(err)
 		return
 	}
-	if existing.UserID.Hex() != userIDStr {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Forbidden"})
-		return
-	}
-	if err := tc.todoService.DeleteTodo(id, userIDStr); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	c.Status(http.StatusNoContent)
+}
+
+// GetTodo handles retrieving a single to-do item.
+//
+// @Summary Get a to-do item
+// @Description Get a to-do item of the authenticated user. Items of other users are reported as not found.
+// @Tags todos
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id} [get]
+func (tc *TodoController) GetTodo(c *gin.Context) {
+	todo, err := tc.todoService.GetTodoByID(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
 		return
 	}
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
 }
 
 // GetTodos handles retrieving a paginated list of the authenticated user’s to-do items.
@@ -130,27 +157,415 @@ func (tc *TodoController) DeleteTodo(c *gin.Context) {
 // @Tags todos
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number" default(1)
+// @Param page query int false "Page number (offset pagination)" default(1)
 // @Param limit query int false "Page limit" default(10)
+// @Param pagination query string false "Pagination mode; cursor returns next_cursor and prev_cursor" Enums(offset, cursor) default(offset)
+// @Param cursor query string false "Cursor from a previous response's next_cursor or prev_cursor; implies pagination=cursor"
+// @Param count query bool false "Include the total count (default true in offset mode, false in cursor mode)"
+// @Param status query []string false "Filter by status (comma separated)" collectionFormat(csv)
+// @Param due query string false "Due date window" Enums(overdue, today, week, none)
+// @Param due_after query string false "Due on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param due_before query string false "Due before (RFC 3339 or YYYY-MM-DD)"
+// @Param tz query string false "IANA timezone for day boundaries and plain dates" default(UTC)
+// @Param labels query []string false "Filter by label names (comma separated)" collectionFormat(csv)
+// @Param label_match query string false "Match any or all of the labels" Enums(any, all) default(any)
+// @Param project_id query string false "Filter by project"
+// @Param filter query string false "Filter expression, e.g. status:open AND (label:work OR priority>=high) AND due<2026-11-01"
+// @Param sort query string false "Sort field, prefix with - for descending" Enums(priority, due_at, created_at, updated_at, title)
+// @Param order query string false "Sort direction" Enums(asc, desc)
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} apperrors.Problem "Invalid filter; filter expression errors include the position and token"
 // @Router /todos [get]
 func (tc *TodoController) GetTodos(c *gin.Context) {
-	userIDStr := c.GetString("userID")
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "10")
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	listTodos(c, tc.todoService, filter)
+}
 
-	page, _ := strconv.ParseInt(pageStr, 10, 64)
-	limit, _ := strconv.ParseInt(limitStr, 10, 64)
+// SearchTodos handles full-text search across the authenticated user's to-do items.
+//
+// @Summary Search to-do items
+// @Description Search the title and description of the authenticated user's to-do items, ranked by relevance (title matches weigh more). Supports "quoted phrases", -negation and title:/description: prefixes, and the same filters as the list endpoint. Highlights hold HTML-escaped snippets with matches wrapped in <mark>.
+// @Tags todos
+// @Produce json
+// @Param q query string true "Search query, e.g. milk \"whole grain\" -eggs title:shop"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Page limit" default(10)
+// @Param status query []string false "Filter by status (comma separated)" collectionFormat(csv)
+// @Param due query string false "Due date window" Enums(overdue, today, week, none)
+// @Param labels query []string false "Filter by label names (comma separated)" collectionFormat(csv)
+// @Param project_id query string false "Filter by project"
+// @Param filter query string false "Filter expression, as for GET /todos"
+// @Param sort query string false "Sort field instead of relevance, prefix with - for descending" Enums(priority, due_at, created_at, updated_at, title)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} apperrors.Problem "Invalid query or filter"
+// @Router /todos/search [get]
+func (tc *TodoController) SearchTodos(c *gin.Context) {
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	page, limit, err := pagination(c)
+	if err != nil {
+		bindError(c, err)
+		return
+	}
+	results, total, err := tc.todoService.SearchTodos(c.GetString("userID"), c.Query("q"), filter, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	respondPage(c, dto.NewTodoSearchResultResponses(results), page, limit, total)
+}
 
-	todos, total, err := tc.todoService.GetTodos(userIDStr, page, limit)
+// listTodos responds with a page of the authenticated user's to-do items matching filter.
+// It is shared by every endpoint that lists to-do items so they use the same response shape.
+func listTodos(c *gin.Context, todoService services.TodoService, filter models.TodoFilter) {
+	req, err := parsePageRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		bindError(c, err)
 		return
 	}
+	page, err := todoService.GetTodos(c.GetString("userID"), filter, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	body := gin.H{
+		"data":  dto.NewTodoResponses(page.Todos),
+		"limit": req.Limit,
+	}
+	if req.Keyset {
+		body["next_cursor"] = page.NextCursor
+		body["prev_cursor"] = page.PrevCursor
+	} else {
+		body["page"] = req.Page
+	}
+	if page.Total != nil {
+		body["total"] = *page.Total
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// parsePageRequest reads the pagination parameters of a listing. Passing a
+// cursor, or pagination=cursor for the first page, selects keyset pagination.
+// The total is counted by default in offset mode only; count=true|false overrides it.
+func parsePageRequest(c *gin.Context) (models.PageRequest, error) {
+	page, limit, err := pagination(c)
+	if err != nil {
+		return models.PageRequest{}, err
+	}
+	req := models.PageRequest{Page: page, Limit: limit, Cursor: c.Query("cursor")}
+	switch c.Query("pagination") {
+	case "", "offset":
+		req.Keyset = req.Cursor != ""
+	case "cursor":
+		req.Keyset = true
+	default:
+		return req, validation.Errors{"pagination": "must be one of offset, cursor"}
+	}
+	req.Count = !req.Keyset
+	if v := c.Query("count"); v != "" {
+		count, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, validation.Errors{"count": "must be a boolean"}
+		}
+		req.Count = count
+	}
+	return req, nil
+}
+
+// maxPageSize is the largest page a listing returns.
+const maxPageSize = 100
+
+// maxPage is the deepest page number accepted. It keeps the offset far from
+// overflowing; deeper listings should use cursor pagination.
+const maxPage = 100000
+
+// pagination reads the page and limit query parameters. page must be between
+// 1 and maxPage and limit between 1 and maxPageSize.
+func pagination(c *gin.Context) (int64, int64, error) {
+	fields := validation.Errors{}
+	page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	if err != nil {
+		fields["page"] = "must be an integer"
+	} else if page < 1 || page > maxPage {
+		fields["page"] = fmt.Sprintf("must be between 1 and %d", maxPage)
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+	if err != nil {
+		fields["limit"] = "must be an integer"
+	} else if limit < 1 || limit > maxPageSize {
+		fields["limit"] = fmt.Sprintf("must be between 1 and %d", maxPageSize)
+	}
+	if len(fields) > 0 {
+		return 0, 0, fields
+	}
+	return page, limit, nil
+}
+
+// respondPage writes the pagination envelope shared by every listing endpoint.
+func respondPage(c *gin.Context, data interface{}, page, limit, total int64) {
 	c.JSON(http.StatusOK, gin.H{
-		"data":  todos,
+		"data":  data,
 		"page":  page,
 		"limit": limit,
 		"total": total,
 	})
 }
+
+// parseTodoFilter reads the to-do list filters and sort order from the query string.
+func parseTodoFilter(c *gin.Context) (models.TodoFilter, error) {
+	filter := models.TodoFilter{
+		Statuses:   queryList(c, "status"),
+		Due:        c.Query("due"),
+		Timezone:   c.Query("tz"),
+		Labels:     queryList(c, "labels"),
+		LabelMatch: c.Query("label_match"),
+		Query:      c.Query("filter"),
+	}
+	if v := c.Query("project_id"); v != "" {
+		projectID, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			return filter, validation.Errors{"project_id": "must be a valid id"}
+		}
+		filter.ProjectID = projectID
+	}
+	if err := parseDueBounds(c, &filter); err != nil {
+		return filter, err
+	}
+	sort, err := parseSort(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.Sort = sort
+	return filter, nil
+}
+
+// CompleteTodo handles marking a to-do item as done.
+//
+// @Summary Complete a to-do item
+// @Description Mark a to-do item as done and record its completion time. Completing a recurring item creates its next occurrence, referenced by next_occurrence_id.
+// @Tags todos
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id}/complete [post]
+func (tc *TodoController) CompleteTodo(c *gin.Context) {
+	tc.changeStatus(c, tc.todoService.CompleteTodo)
+}
+
+// ReopenTodo handles moving a to-do item back to the open state.
+//
+// @Summary Reopen a to-do item
+// @Description Reopen a to-do item and clear its completion time
+// @Tags todos
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id}/reopen [post]
+func (tc *TodoController) ReopenTodo(c *gin.Context) {
+	tc.changeStatus(c, tc.todoService.ReopenTodo)
+}
+
+func (tc *TodoController) changeStatus(c *gin.Context, change func(id string, userID string) (*models.Todo, error)) {
+	todo, err := change(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
+}
+
+// AddChecklistItem handles adding an item to a to-do item's checklist.
+//
+// @Summary Add a checklist item
+// @Description Add an item to a to-do item's checklist, at the given position or at the end
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param item body dto.AddChecklistItemRequest true "Checklist item"
+// @Success 201 {object} dto.TodoResponse
+// @Failure 400 {object} apperrors.Problem "Invalid item"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id}/checklist [post]
+func (tc *TodoController) AddChecklistItem(c *gin.Context) {
+	var req dto.AddChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	item := models.ChecklistItem{Text: req.Text, Done: req.Done, Position: -1}
+	if req.Position != nil {
+		item.Position = *req.Position
+	}
+	todo, err := tc.todoService.AddChecklistItem(c.Param("id"), c.GetString("userID"), item)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto.NewTodoResponse(todo))
+}
+
+// UpdateChecklistItem handles editing or checking off a checklist item.
+//
+// @Summary Update a checklist item
+// @Description Change the text and/or done flag of a checklist item; omitted fields are left unchanged
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param itemId path string true "Checklist item ID"
+// @Param item body dto.UpdateChecklistItemRequest true "Changes"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 400 {object} apperrors.Problem "Invalid item"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id}/checklist/{itemId} [patch]
+func (tc *TodoController) UpdateChecklistItem(c *gin.Context) {
+	var req dto.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	update := services.ChecklistItemUpdate{Text: req.Text, Done: req.Done}
+	todo, err := tc.todoService.UpdateChecklistItem(c.Param("id"), c.GetString("userID"), c.Param("itemId"), update)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
+}
+
+// ToggleChecklistItem handles flipping a checklist item between done and not done.
+//
+// @Summary Toggle a checklist item
+// @Description Flip the done flag of a checklist item
+// @Tags checklist
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param itemId path string true "Checklist item ID"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id}/checklist/{itemId}/toggle [post]
+func (tc *TodoController) ToggleChecklistItem(c *gin.Context) {
+	todo, err := tc.todoService.ToggleChecklistItem(c.Param("id"), c.GetString("userID"), c.Param("itemId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
+}
+
+// ReorderChecklist handles changing the order of a checklist.
+//
+// @Summary Reorder a checklist
+// @Description Set the order of a to-do item's checklist; item_ids must list every item exactly once
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param order body dto.ReorderChecklistRequest true "New order"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 400 {object} apperrors.Problem "Invalid order"
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id}/checklist/order [put]
+func (tc *TodoController) ReorderChecklist(c *gin.Context) {
+	var req dto.ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindError(c, err)
+		return
+	}
+	todo, err := tc.todoService.ReorderChecklist(c.Param("id"), c.GetString("userID"), req.ItemIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
+}
+
+// RemoveChecklistItem handles deleting a checklist item.
+//
+// @Summary Remove a checklist item
+// @Description Delete an item from a to-do item's checklist
+// @Tags checklist
+// @Produce json
+// @Param id path string true "Todo ID"
+// @Param itemId path string true "Checklist item ID"
+// @Success 200 {object} dto.TodoResponse
+// @Failure 404 {object} apperrors.Problem "Not Found"
+// @Router /todos/{id}/checklist/{itemId} [delete]
+func (tc *TodoController) RemoveChecklistItem(c *gin.Context) {
+	todo, err := tc.todoService.RemoveChecklistItem(c.Param("id"), c.GetString("userID"), c.Param("itemId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewTodoResponse(todo))
+}
+
+// queryList collects a list-valued query parameter, accepting both repeated
+// parameters (?status=a&status=b) and comma separated values (?status=a,b).
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// parseDueBounds reads the due_after and due_before query parameters,
+// interpreting plain dates in the requested timezone.
+func parseDueBounds(c *gin.Context, filter *models.TodoFilter) error {
+	loc, err := services.LoadLocation(filter.Timezone)
+	if err != nil {
+		return err
+	}
+	if v := c.Query("due_after"); v != "" {
+		t, err := services.ParseDateTime(v, loc)
+		if err != nil {
+			return err
+		}
+		filter.DueAfter = &t
+	}
+	if v := c.Query("due_before"); v != "" {
+		t, err := services.ParseDateTime(v, loc)
+		if err != nil {
+			return err
+		}
+		filter.DueBefore = &t
+	}
+	return nil
+}
+
+// parseSort reads the sort and order query parameters. A leading "-" on the
+// sort field is shorthand for order=desc.
+func parseSort(c *gin.Context) (models.TodoSort, error) {
+	var sort models.TodoSort
+	field := c.Query("sort")
+	if strings.HasPrefix(field, "-") {
+		field = strings.TrimPrefix(field, "-")
+		sort.Descending = true
+	}
+	sort.Field = field
+	switch strings.ToLower(c.Query("order")) {
+	case "":
+	case "asc":
+		sort.Descending = false
+	case "desc":
+		sort.Descending = true
+	default:
+		return sort, services.ErrInvalidOrder
+	}
+	return sort, nil
+}
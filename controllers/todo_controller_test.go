@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"todo-list-api/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantPage  int64
+		wantLimit int64
+		wantErr   validation.Errors
+	}{
+		{"", 1, 10, nil},
+		{"page=3&limit=100", 3, 100, nil},
+		{"page=100000&limit=100", 100000, 100, nil},
+		{"page=0", 0, 0, validation.Errors{"page": "must be between 1 and 100000"}},
+		{"page=100001", 0, 0, validation.Errors{"page": "must be between 1 and 100000"}},
+		{"page=9223372036854775807&limit=100", 0, 0, validation.Errors{"page": "must be between 1 and 100000"}},
+		{"page=two&limit=101", 0, 0, validation.Errors{"page": "must be an integer", "limit": "must be between 1 and 100"}},
+		{"limit=0", 0, 0, validation.Errors{"limit": "must be between 1 and 100"}},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/todos?"+tt.query, nil)
+			page, limit, err := pagination(c)
+			if tt.wantErr != nil {
+				if got, ok := validation.FieldErrors(err); !ok || !reflect.DeepEqual(got, tt.wantErr) {
+					t.Errorf("pagination() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || page != tt.wantPage || limit != tt.wantLimit {
+				t.Errorf("pagination() = %d, %d, %v; want %d, %d", page, limit, err, tt.wantPage, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// bindError reports an invalid request body or query string. Validation
+// failures are reported per field; other binding errors, such as malformed
+// JSON, as an invalid body.
+func bindError(c *gin.Context, err error) {
+	c.Error(err).SetType(gin.ErrorTypeBind)
+}
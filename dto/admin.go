@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SetRoleRequest is the body of PUT /admin/users/{id}/role.
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin" example:"admin"`
+}
+
+// AdminUserResponse is a user as shown to admins.
+type AdminUserResponse struct {
+	ID                    primitive.ObjectID `json:"id" swaggertype:"string"`
+	Name                  string             `json:"name" example:"John Doe"`
+	Email                 string             `json:"email" example:"john@doe.com"`
+	Role                  string             `json:"role" enums:"user,admin"`
+	EmailVerified         bool               `json:"email_verified"`
+	MFAEnabled            bool               `json:"mfa_enabled"`
+	Disabled              bool               `json:"disabled"`
+	DisabledAt            *time.Time         `json:"disabled_at,omitempty"`
+	PasswordResetRequired bool               `json:"password_reset_required"`
+}
+
+// NewAdminUserResponse maps a user to its admin response body.
+func NewAdminUserResponse(user *models.User) AdminUserResponse {
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+	return AdminUserResponse{
+		ID:                    user.ID,
+		Name:                  user.Name,
+		Email:                 user.Email,
+		Role:                  role,
+		EmailVerified:         user.EmailVerified,
+		MFAEnabled:            user.MFAEnabled,
+		Disabled:              user.Disabled,
+		DisabledAt:            user.DisabledAt,
+		PasswordResetRequired: user.PasswordResetRequired,
+	}
+}
+
+// NewAdminUserResponses maps a list of users to admin response bodies.
+func NewAdminUserResponses(users []models.User) []AdminUserResponse {
+	responses := make([]AdminUserResponse, len(users))
+	for i := range users {
+		responses[i] = NewAdminUserResponse(&users[i])
+	}
+	return responses
+}
+
+// TodoCountsResponse summarises a user's to-do items.
+type TodoCountsResponse struct {
+	UserID   primitive.ObjectID `json:"user_id" swaggertype:"string"`
+	Total    int64              `json:"total" example:"12"`
+	ByStatus map[string]int64   `json:"by_status"`
+	Overdue  int64              `json:"overdue" example:"2"`
+}
+
+// NewTodoCountsResponse maps the to-do counts of userID to a response body.
+func NewTodoCountsResponse(userID primitive.ObjectID, counts *models.TodoCounts) TodoCountsResponse {
+	return TodoCountsResponse{
+		UserID:   userID,
+		Total:    counts.Total,
+		ByStatus: counts.ByStatus,
+		Overdue:  counts.Overdue,
+	}
+}
+
+// AuditLogEntryResponse is an audit log entry as returned by the API.
+type AuditLogEntryResponse struct {
+	ID           primitive.ObjectID  `json:"id" swaggertype:"string"`
+	ActorID      primitive.ObjectID  `json:"actor_id" swaggertype:"string"`
+	Action       string              `json:"action" example:"disable_user"`
+	TargetUserID *primitive.ObjectID `json:"target_user_id,omitempty" swaggertype:"string"`
+	Details      map[string]string   `json:"details,omitempty"`
+	// Status is pending while the action runs, or when it was interrupted.
+	Status    string    `json:"status" enums:"pending,completed,failed"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAuditLogEntryResponses maps audit log entries to response bodies.
+func NewAuditLogEntryResponses(entries []models.AuditLogEntry) []AuditLogEntryResponse {
+	responses := make([]AuditLogEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = AuditLogEntryResponse{
+			ID:           entry.ID,
+			ActorID:      entry.ActorID,
+			Action:       entry.Action,
+			TargetUserID: entry.TargetUserID,
+			Details:      entry.Details,
+			Status:       entry.Status,
+			CreatedAt:    entry.CreatedAt,
+		}
+	}
+	return responses
+}
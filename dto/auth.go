@@ -0,0 +1,142 @@
+package dto
+
+import (
+	"encoding/base64"
+	"strings"
+	"todo-list-api/models"
+)
+
+// RegisterRequest is the body of POST /register.
+type RegisterRequest struct {
+	Name     string `json:"name" binding:"max=100" example:"John Doe"`
+	Email    string `json:"email" binding:"required,email,max=254" example:"john@doe.com"`
+	Password string `json:"password" binding:"required,min=8,max=72" example:"correct horse battery"`
+	// Timezone is used by the built-in smart lists; UTC when omitted.
+	Timezone string `json:"timezone,omitempty" binding:"omitempty,timezone" example:"Europe/Berlin"`
+}
+
+// NewUser returns the user to register. The password is still in plain text.
+func (r *RegisterRequest) NewUser() *models.User {
+	return &models.User{Name: r.Name, Email: strings.TrimSpace(r.Email), Password: r.Password, Timezone: r.Timezone}
+}
+
+// LoginRequest is the body of POST /login.
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"john@doe.com"`
+	Password string `json:"password" binding:"required" example:"correct horse battery"`
+}
+
+// RefreshRequest is the body of POST /token/refresh.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"hM3o9Qd2x7Vn0yKpL4sT1aBcD5eF6gHiJ8kLmN0pQrS"`
+}
+
+// LogoutRequest is the optional body of POST /logout.
+type LogoutRequest struct {
+	// RefreshToken, if given, is revoked together with the access token.
+	RefreshToken string `json:"refresh_token,omitempty" example:"hM3o9Qd2x7Vn0yKpL4sT1aBcD5eF6gHiJ8kLmN0pQrS"`
+}
+
+// ChangePasswordRequest is the body of PUT /password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"correct horse battery"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=72" example:"battery staple horse"`
+}
+
+// ForgotPasswordRequest is the body of POST /password/forgot.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=254" example:"john@doe.com"`
+}
+
+// ResetPasswordRequest is the body of POST /password/reset.
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required" example:"Xc2k9Qd2x7Vn0yKpL4sT1aBcD5eF6gHiJ8kLmN0pQrS"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=72" example:"battery staple horse"`
+}
+
+// VerifyEmailRequest is the body of POST /verify-email.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required" example:"LAAAAAd1AGrTwVIjrtUyI21gXQJlAAYAAABhQGIuYwASeADSEtVqAAAAAAA.EdKXyE2TBsGQlqph61413f6XOLce15XfOvYlIlotNyQ"`
+}
+
+// MFALoginRequest is the body of POST /login/mfa.
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Code is a code from the authenticator app or a recovery code.
+	Code string `json:"code" binding:"required,max=20" example:"123456"`
+}
+
+// MFAChallengeResponse is returned by POST /login for users with MFA enabled.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required" example:"true"`
+	MFAToken    string `json:"mfa_token"`
+	// ExpiresIn is the lifetime of the challenge in seconds.
+	ExpiresIn int `json:"expires_in" example:"300"`
+}
+
+// NewMFAChallengeResponse maps an MFA challenge to its response body.
+func NewMFAChallengeResponse(result *models.LoginResult) MFAChallengeResponse {
+	return MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    result.MFAToken,
+		ExpiresIn:   int(result.MFAExpiresIn.Seconds()),
+	}
+}
+
+// MFAEnrollmentResponse is returned by POST /mfa/enroll.
+type MFAEnrollmentResponse struct {
+	// Secret is the base32 TOTP secret, for entering into an app by hand.
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	// OTPAuthURL is the otpauth:// URI encoded in the QR code.
+	OTPAuthURL string `json:"otpauth_url" example:"otpauth://totp/Todo%20List%20API:john@doe.com?algorithm=SHA1&digits=6&issuer=Todo%20List%20API&period=30&secret=JBSWY3DPEHPK3PXP"`
+	// QRCode is a PNG image of the URI as a data URL.
+	QRCode string `json:"qr_code" example:"data:image/png;base64,iVBORw0KGgo..."`
+}
+
+// NewMFAEnrollmentResponse maps an enrollment to its response body.
+func NewMFAEnrollmentResponse(enrollment *models.MFAEnrollment) MFAEnrollmentResponse {
+	return MFAEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURL: enrollment.URL,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	}
+}
+
+// MFACodeRequest is the body of POST /mfa/confirm.
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,max=20" example:"123456"`
+}
+
+// RecoveryCodesResponse holds recovery codes, which are only shown once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3xq7-mzp2a,7dh2v-qq4rn"`
+}
+
+// DisableMFARequest is the body of POST /mfa/disable. Either a current code
+// or the password is required.
+type DisableMFARequest struct {
+	Code     string `json:"code,omitempty" binding:"required_without=Password,max=20" example:"123456"`
+	Password string `json:"password,omitempty" binding:"required_without=Code"`
+}
+
+// TokenResponse holds an access token and the refresh token to renew it with.
+type TokenResponse struct {
+	AccessToken string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	// ExpiresIn is the lifetime of the access token in seconds.
+	ExpiresIn    int    `json:"expires_in" example:"900"`
+	RefreshToken string `json:"refresh_token" example:"hM3o9Qd2x7Vn0yKpL4sT1aBcD5eF6gHiJ8kLmN0pQrS"`
+	// Token is the access token under its previous name.
+	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// NewTokenResponse maps a token pair to its response body.
+func NewTokenResponse(pair *models.TokenPair) TokenResponse {
+	return TokenResponse{
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(pair.ExpiresIn.Seconds()),
+		RefreshToken: pair.RefreshToken,
+		Token:        pair.AccessToken,
+	}
+}
@@ -0,0 +1,21 @@
+package dto
+
+// AddChecklistItemRequest is the body of POST /todos/{id}/checklist.
+type AddChecklistItemRequest struct {
+	Text string `json:"text" binding:"required,notblank,max=500" example:"Buy milk"`
+	Done bool   `json:"done"`
+	// Position is the zero-based index to insert at; the item is appended when omitted.
+	Position *int `json:"position,omitempty" binding:"omitempty,min=0"`
+}
+
+// UpdateChecklistItemRequest is the body of PATCH /todos/{id}/checklist/{itemId}.
+// Omitted fields are left unchanged.
+type UpdateChecklistItemRequest struct {
+	Text *string `json:"text,omitempty" binding:"omitempty,notblank,max=500" example:"Buy oat milk"`
+	Done *bool   `json:"done,omitempty"`
+}
+
+// ReorderChecklistRequest is the body of PUT /todos/{id}/checklist/order.
+type ReorderChecklistRequest struct {
+	ItemIDs []string `json:"item_ids" binding:"required"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LabelRequest is the body of POST /labels and PUT /labels/{id}.
+type LabelRequest struct {
+	Name  string `json:"name" binding:"required,notblank,max=50" example:"work"`
+	Color string `json:"color" example:"#1e90ff"`
+}
+
+// NewLabel returns a label of userID built from the request.
+func (r *LabelRequest) NewLabel(id, userID primitive.ObjectID) *models.Label {
+	return &models.Label{ID: id, Name: r.Name, Color: r.Color, UserID: userID}
+}
+
+// LabelResponse is a label as returned by the API.
+type LabelResponse struct {
+	ID        primitive.ObjectID `json:"id" swaggertype:"string"`
+	Name      string             `json:"name" example:"work"`
+	Color     string             `json:"color" example:"#1e90ff"`
+	UserID    primitive.ObjectID `json:"user_id" swaggertype:"string"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// NewLabelResponse maps a label to its response body.
+func NewLabelResponse(label *models.Label) LabelResponse {
+	return LabelResponse{
+		ID:        label.ID,
+		Name:      label.Name,
+		Color:     label.Color,
+		UserID:    label.UserID,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+	}
+}
+
+// NewLabelResponses maps a list of labels to response bodies.
+func NewLabelResponses(labels []models.Label) []LabelResponse {
+	responses := make([]LabelResponse, len(labels))
+	for i := range labels {
+		responses[i] = NewLabelResponse(&labels[i])
+	}
+	return responses
+}
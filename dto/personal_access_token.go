@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PersonalAccessTokenRequest is the body of POST /tokens.
+type PersonalAccessTokenRequest struct {
+	Name string `json:"name" binding:"required,notblank,max=100" example:"CI deploy"`
+	// Scopes limit what the token can do; without scopes it has the same
+	// access as a login.
+	Scopes []string `json:"scopes" example:"todos:read"`
+	// ExpiresAt is optional; tokens without it do not expire.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// NewPersonalAccessToken returns a token of userID built from the request.
+func (r *PersonalAccessTokenRequest) NewPersonalAccessToken(userID primitive.ObjectID) *models.PersonalAccessToken {
+	return &models.PersonalAccessToken{UserID: userID, Name: r.Name, Scopes: r.Scopes, ExpiresAt: r.ExpiresAt}
+}
+
+// PersonalAccessTokenResponse is a personal access token as returned by the
+// API, without the token itself.
+type PersonalAccessTokenResponse struct {
+	ID         primitive.ObjectID `json:"id" swaggertype:"string"`
+	Name       string             `json:"name" example:"CI deploy"`
+	Scopes     []string           `json:"scopes" example:"todos:read"`
+	Prefix     string             `json:"prefix" example:"pat_x4Kq9v"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time         `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// NewPersonalAccessTokenResponse maps a personal access token to its response body.
+func NewPersonalAccessTokenResponse(token *models.PersonalAccessToken) PersonalAccessTokenResponse {
+	scopes := token.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return PersonalAccessTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     scopes,
+		Prefix:     token.Prefix,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// NewPersonalAccessTokenResponses maps a list of personal access tokens to response bodies.
+func NewPersonalAccessTokenResponses(tokens []models.PersonalAccessToken) []PersonalAccessTokenResponse {
+	responses := make([]PersonalAccessTokenResponse, len(tokens))
+	for i := range tokens {
+		responses[i] = NewPersonalAccessTokenResponse(&tokens[i])
+	}
+	return responses
+}
+
+// CreatedPersonalAccessTokenResponse is returned once, when a personal access
+// token is created, and is the only time the token is shown.
+type CreatedPersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token" example:"pat_x4Kq9vR2mT8wL1nB5cY7zA0dF3gH6jK9pQ2sU4vW8xE"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectRequest is the body of POST /projects and PUT /projects/{id}.
+type ProjectRequest struct {
+	Name string `json:"name" binding:"required,notblank,max=100" example:"Groceries"`
+}
+
+// NewProject returns a project of userID built from the request.
+func (r *ProjectRequest) NewProject(id, userID primitive.ObjectID) *models.Project {
+	return &models.Project{ID: id, Name: r.Name, UserID: userID}
+}
+
+// ProjectResponse is a project as returned by the API.
+type ProjectResponse struct {
+	ID        primitive.ObjectID `json:"id" swaggertype:"string"`
+	Name      string             `json:"name" example:"Groceries"`
+	Inbox     bool               `json:"inbox"`
+	UserID    primitive.ObjectID `json:"user_id" swaggertype:"string"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// NewProjectResponse maps a project to its response body.
+func NewProjectResponse(project *models.Project) ProjectResponse {
+	return ProjectResponse{
+		ID:        project.ID,
+		Name:      project.Name,
+		Inbox:     project.Inbox,
+		UserID:    project.UserID,
+		CreatedAt: project.CreatedAt,
+		UpdatedAt: project.UpdatedAt,
+	}
+}
+
+// NewProjectResponses maps a list of projects to response bodies.
+func NewProjectResponses(projects []models.Project) []ProjectResponse {
+	responses := make([]ProjectResponse, len(projects))
+	for i := range projects {
+		responses[i] = NewProjectResponse(&projects[i])
+	}
+	return responses
+}
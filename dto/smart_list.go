@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SmartListRequest is the body of POST /smart-lists and PUT /smart-lists/{id}.
+type SmartListRequest struct {
+	Name   string                 `json:"name" binding:"required,notblank,max=100" example:"Work this week"`
+	Filter models.SmartListFilter `json:"filter"`
+}
+
+// NewSmartList returns a smart list of userID built from the request.
+func (r *SmartListRequest) NewSmartList(id, userID primitive.ObjectID) *models.SmartList {
+	return &models.SmartList{ID: id, Name: r.Name, Filter: r.Filter, UserID: userID}
+}
+
+// SmartListResponse is a smart list as returned by the API. Built-in lists
+// have a key instead of an id and no owner or timestamps.
+type SmartListResponse struct {
+	ID        *primitive.ObjectID    `json:"id,omitempty" swaggertype:"string"`
+	Key       string                 `json:"key,omitempty" enums:"today,upcoming,overdue"`
+	Name      string                 `json:"name" example:"Work this week"`
+	Filter    models.SmartListFilter `json:"filter"`
+	UserID    *primitive.ObjectID    `json:"user_id,omitempty" swaggertype:"string"`
+	CreatedAt *time.Time             `json:"created_at,omitempty"`
+	UpdatedAt *time.Time             `json:"updated_at,omitempty"`
+}
+
+// NewSmartListResponse maps a smart list to its response body.
+func NewSmartListResponse(list *models.SmartList) SmartListResponse {
+	resp := SmartListResponse{Key: list.Key, Name: list.Name, Filter: list.Filter}
+	if list.Key == "" {
+		resp.ID = &list.ID
+		resp.UserID = &list.UserID
+		resp.CreatedAt = &list.CreatedAt
+		resp.UpdatedAt = &list.UpdatedAt
+	}
+	return resp
+}
+
+// NewSmartListResponses maps a list of smart lists to response bodies.
+func NewSmartListResponses(lists []models.SmartList) []SmartListResponse {
+	responses := make([]SmartListResponse, len(lists))
+	for i := range lists {
+		responses[i] = NewSmartListResponse(&lists[i])
+	}
+	return responses
+}
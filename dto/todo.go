@@ -0,0 +1,220 @@
+// Package dto defines the request and response bodies of the API and maps
+// them to and from the persistence models. Requests only carry fields clients
+// may set; responses only carry fields clients may see.
+package dto
+
+import (
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TodoRequest is the body of POST /todos and PUT /todos/{id}.
+type TodoRequest struct {
+	Title       string                 `json:"title" binding:"required,notblank,max=200" example:"Buy groceries"`
+	Description string                 `json:"description" binding:"max=10000" example:"Milk, eggs, bread"`
+	Status      string                 `json:"status" binding:"omitempty,oneof=open in_progress done cancelled" enums:"open,in_progress,done,cancelled"`
+	Priority    models.Priority        `json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	Labels      []string               `json:"labels,omitempty" binding:"max=20,dive,required,max=50" example:"work,errands"`
+	ProjectID   *primitive.ObjectID    `json:"project_id,omitempty" swaggertype:"string"`
+	Checklist   []ChecklistItemRequest `json:"checklist,omitempty" binding:"max=100,dive"`
+	Reminders   []ReminderRequest      `json:"reminders,omitempty" binding:"max=10,dive"`
+	StartAt     *time.Time             `json:"start_at,omitempty"`
+	DueAt       *time.Time             `json:"due_at,omitempty"`
+	Timezone    string                 `json:"timezone,omitempty" binding:"omitempty,timezone" example:"Europe/Berlin"`
+	// RRule is an RFC 5545 recurrence rule (without DTSTART) for repeating todos.
+	RRule      string `json:"rrule,omitempty" binding:"max=500" example:"FREQ=WEEKLY;BYDAY=MO"`
+	RepeatFrom string `json:"repeat_from,omitempty" binding:"omitempty,oneof=due completion" enums:"due,completion"`
+}
+
+// ChecklistItemRequest is a checklist item in a TodoRequest. ID refers to an
+// existing item and is ignored for items the todo does not have.
+type ChecklistItemRequest struct {
+	ID       primitive.ObjectID `json:"id,omitempty" swaggertype:"string"`
+	Text     string             `json:"text" binding:"required,notblank,max=500" example:"Buy milk"`
+	Done     bool               `json:"done"`
+	Position int                `json:"position"`
+}
+
+// ReminderRequest is a reminder in a TodoRequest, either absolute (At) or
+// relative to the due date. ID refers to an existing reminder and is ignored
+// for reminders the todo does not have.
+type ReminderRequest struct {
+	ID            primitive.ObjectID `json:"id,omitempty" swaggertype:"string"`
+	At            *time.Time         `json:"at,omitempty"`
+	OffsetMinutes *int               `json:"offset_minutes,omitempty" example:"30"`
+}
+
+// NewTodo returns a new todo of userID built from the request.
+func (r *TodoRequest) NewTodo(userID primitive.ObjectID) *models.Todo {
+	todo := &models.Todo{UserID: userID}
+	r.ApplyTo(todo)
+	return todo
+}
+
+// ApplyTo replaces the client-controlled fields of todo with the request's, as
+// a PUT does: an omitted status becomes open, and an omitted project,
+// checklist or reminders are cleared. Checklist items and reminders keep their
+// identity only if the request refers to ones the todo already has.
+func (r *TodoRequest) ApplyTo(todo *models.Todo) {
+	todo.Title = r.Title
+	todo.Description = r.Description
+	todo.Status = r.Status
+	if todo.Status == "" {
+		todo.Status = models.TodoStatusOpen
+	}
+	todo.Priority = r.Priority
+	todo.Labels = r.Labels
+	todo.ProjectID = r.ProjectID
+	todo.Checklist = checklistFromRequest(r.Checklist, todo.Checklist)
+	todo.Reminders = remindersFromRequest(r.Reminders, todo.Reminders)
+	todo.StartAt = r.StartAt
+	todo.DueAt = r.DueAt
+	todo.Timezone = r.Timezone
+	todo.RRule = r.RRule
+	todo.RepeatFrom = r.RepeatFrom
+}
+
+func checklistFromRequest(items []ChecklistItemRequest, existing []models.ChecklistItem) []models.ChecklistItem {
+	if items == nil {
+		return nil
+	}
+	known := make(map[primitive.ObjectID]bool, len(existing))
+	for _, item := range existing {
+		known[item.ID] = true
+	}
+	checklist := make([]models.ChecklistItem, len(items))
+	for i, item := range items {
+		checklist[i] = models.ChecklistItem{Text: item.Text, Done: item.Done, Position: item.Position}
+		if known[item.ID] {
+			checklist[i].ID = item.ID
+		}
+	}
+	return checklist
+}
+
+func remindersFromRequest(reminders []ReminderRequest, existing []models.Reminder) []models.Reminder {
+	if reminders == nil {
+		return nil
+	}
+	known := make(map[primitive.ObjectID]bool, len(existing))
+	for _, r := range existing {
+		known[r.ID] = true
+	}
+	result := make([]models.Reminder, len(reminders))
+	for i, r := range reminders {
+		result[i] = models.Reminder{At: r.At, OffsetMinutes: r.OffsetMinutes}
+		if known[r.ID] {
+			result[i].ID = r.ID
+		}
+	}
+	return result
+}
+
+// TodoResponse is a to-do item as returned by the API.
+type TodoResponse struct {
+	ID          primitive.ObjectID     `json:"id" swaggertype:"string"`
+	Title       string                 `json:"title" example:"Buy groceries"`
+	Description string                 `json:"description" example:"Milk, eggs, bread"`
+	Status      string                 `json:"status" enums:"open,in_progress,done,cancelled"`
+	Priority    models.Priority        `json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	Labels      []string               `json:"labels,omitempty" example:"work,errands"`
+	ProjectID   *primitive.ObjectID    `json:"project_id,omitempty" swaggertype:"string"`
+	Checklist   []models.ChecklistItem `json:"checklist,omitempty"`
+	Progress    *models.Progress       `json:"progress,omitempty"`
+	Reminders   []models.Reminder      `json:"reminders,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	StartAt     *time.Time             `json:"start_at,omitempty"`
+	DueAt       *time.Time             `json:"due_at,omitempty"`
+	Timezone    string                 `json:"timezone,omitempty" example:"Europe/Berlin"`
+	RRule       string                 `json:"rrule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	RepeatFrom  string                 `json:"repeat_from,omitempty" enums:"due,completion"`
+	// NextOccurrenceID is the occurrence generated when a recurring todo was completed.
+	NextOccurrenceID *primitive.ObjectID `json:"next_occurrence_id,omitempty" swaggertype:"string"`
+	UserID           primitive.ObjectID  `json:"user_id" swaggertype:"string"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+}
+
+// NewTodoResponse maps a todo to its response body.
+func NewTodoResponse(todo *models.Todo) TodoResponse {
+	return TodoResponse{
+		ID:               todo.ID,
+		Title:            todo.Title,
+		Description:      todo.Description,
+		Status:           todo.Status,
+		Priority:         todo.Priority,
+		Labels:           todo.Labels,
+		ProjectID:        todo.ProjectID,
+		Checklist:        todo.Checklist,
+		Progress:         todo.Progress,
+		Reminders:        todo.Reminders,
+		CompletedAt:      todo.CompletedAt,
+		StartAt:          todo.StartAt,
+		DueAt:            todo.DueAt,
+		Timezone:         todo.Timezone,
+		RRule:            todo.RRule,
+		RepeatFrom:       todo.RepeatFrom,
+		NextOccurrenceID: todo.NextOccurrenceID,
+		UserID:           todo.UserID,
+		CreatedAt:        todo.CreatedAt,
+		UpdatedAt:        todo.UpdatedAt,
+	}
+}
+
+// Request returns the request that would set the todo's client-controlled
+// fields to the values in the response.
+func (t *TodoResponse) Request() TodoRequest {
+	req := TodoRequest{
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		Priority:    t.Priority,
+		Labels:      t.Labels,
+		ProjectID:   t.ProjectID,
+		StartAt:     t.StartAt,
+		DueAt:       t.DueAt,
+		Timezone:    t.Timezone,
+		RRule:       t.RRule,
+		RepeatFrom:  t.RepeatFrom,
+	}
+	for _, item := range t.Checklist {
+		req.Checklist = append(req.Checklist, ChecklistItemRequest{ID: item.ID, Text: item.Text, Done: item.Done, Position: item.Position})
+	}
+	for _, r := range t.Reminders {
+		req.Reminders = append(req.Reminders, ReminderRequest{ID: r.ID, At: r.At, OffsetMinutes: r.OffsetMinutes})
+	}
+	return req
+}
+
+// NewTodoResponses maps a list of todos to response bodies.
+func NewTodoResponses(todos []models.Todo) []TodoResponse {
+	responses := make([]TodoResponse, len(todos))
+	for i := range todos {
+		responses[i] = NewTodoResponse(&todos[i])
+	}
+	return responses
+}
+
+// TodoSearchResultResponse is a to-do item matched by a search.
+type TodoSearchResultResponse struct {
+	TodoResponse
+	// Score is the text relevance; it is zero when the query only uses field prefixes.
+	Score float64 `json:"score"`
+	// Highlights maps field names to HTML-escaped snippets with matches wrapped in <mark>.
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// NewTodoSearchResultResponses maps search results to response bodies.
+func NewTodoSearchResultResponses(results []models.TodoSearchResult) []TodoSearchResultResponse {
+	responses := make([]TodoSearchResultResponse, len(results))
+	for i := range results {
+		responses[i] = TodoSearchResultResponse{
+			TodoResponse: NewTodoResponse(&results[i].Todo),
+			Score:        results[i].Score,
+			Highlights:   results[i].Highlights,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestApplyToReplacesTodo(t *testing.T) {
+	project := primitive.NewObjectID()
+	item := models.ChecklistItem{ID: primitive.NewObjectID(), Text: "Buy bulbs", Position: 0}
+	reminder := models.Reminder{ID: primitive.NewObjectID(), OffsetMinutes: intPtr(30)}
+	existing := models.Todo{
+		Title:     "Plant tulips",
+		Status:    models.TodoStatusInProgress,
+		ProjectID: &project,
+		Checklist: []models.ChecklistItem{item},
+		Reminders: []models.Reminder{reminder},
+	}
+
+	todo := existing
+	(&TodoRequest{Title: "Plant daffodils"}).ApplyTo(&todo)
+	if todo.Status != models.TodoStatusOpen || todo.ProjectID != nil || todo.Checklist != nil || todo.Reminders != nil {
+		t.Errorf("ApplyTo(omitted fields) = %+v, want status open and no project, checklist or reminders", todo)
+	}
+
+	stranger := primitive.NewObjectID()
+	todo = existing
+	(&TodoRequest{
+		Title:     "Plant tulips",
+		Checklist: []ChecklistItemRequest{{ID: item.ID, Text: "Buy more bulbs"}, {ID: stranger, Text: "Water"}},
+		Reminders: []ReminderRequest{{ID: reminder.ID, OffsetMinutes: intPtr(60)}},
+	}).ApplyTo(&todo)
+	if todo.Checklist[0].ID != item.ID || !todo.Checklist[1].ID.IsZero() {
+		t.Errorf("checklist IDs = %v, %v; want %v kept and the unknown one dropped", todo.Checklist[0].ID, todo.Checklist[1].ID, item.ID)
+	}
+	if todo.Reminders[0].ID != reminder.ID || *todo.Reminders[0].OffsetMinutes != 60 {
+		t.Errorf("reminder = %+v, want %v with the new offset", todo.Reminders[0], reminder.ID)
+	}
+}
+
+func TestResponseRequestRoundTrips(t *testing.T) {
+	project := primitive.NewObjectID()
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	todo := models.Todo{
+		ID:          primitive.NewObjectID(),
+		UserID:      primitive.NewObjectID(),
+		Title:       "Water plants",
+		Description: "Balcony",
+		Status:      models.TodoStatusOpen,
+		Priority:    models.PriorityHigh,
+		Labels:      []string{"home"},
+		ProjectID:   &project,
+		Checklist:   []models.ChecklistItem{{ID: primitive.NewObjectID(), Text: "Fill can", Position: 0}},
+		Reminders:   []models.Reminder{{ID: primitive.NewObjectID(), OffsetMinutes: intPtr(15)}},
+		DueAt:       &due,
+		Timezone:    "Europe/Berlin",
+		RRule:       "FREQ=WEEKLY",
+		RepeatFrom:  models.RepeatFromDue,
+	}
+
+	resp := NewTodoResponse(&todo)
+	req := resp.Request()
+	got := models.Todo{ID: todo.ID, UserID: todo.UserID, Checklist: todo.Checklist, Reminders: todo.Reminders}
+	req.ApplyTo(&got)
+	if !reflect.DeepEqual(got, todo) {
+		t.Errorf("applying the response's request = %+v, want %+v", got, todo)
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
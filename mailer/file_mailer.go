@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer writes each email as an .eml file into a directory, where it can
+// be opened with a mail client during local development.
+type FileMailer struct {
+	dir string
+}
+
+// NewFileMailer returns a new FileMailer writing to dir.
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir}
+}
+
+// Send writes the message to a new file named after the current time.
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s.eml", time.Now().Format("20060102T150405.000000000"))
+	return os.WriteFile(filepath.Join(m.dir, name), format(defaultFrom, msg), 0o600)
+}
@@ -0,0 +1,22 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer writes emails, including their bodies, to the application log.
+// It is the default mailer and is only meant for local development; a
+// warning is logged when it is selected outside APP_ENV=development.
+type LogMailer struct{}
+
+// NewLogMailer returns a new LogMailer.
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the message.
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	log.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package mailer sends transactional emails such as password reset links.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// defaultFrom is the sender address used when SMTP_FROM is not set.
+const defaultFrom = "todo-list-api@localhost"
+
+// Message is a plain text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfigFromEnv reads the SMTP_* settings.
+func SMTPConfigFromEnv() (SMTPConfig, error) {
+	port := 25
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return SMTPConfig{}, fmt.Errorf("invalid SMTP_PORT: %w", err)
+		}
+		port = p
+	}
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		host = "localhost"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = defaultFrom
+	}
+	return SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}, nil
+}
+
+// NewFromEnv builds the mailer selected by MAILER (log, file or smtp),
+// defaulting to the log mailer. The SMTP mailer shares the SMTP_* settings
+// of the reminder notifier. The log and file mailers keep live password
+// reset links where anyone with access to the logs or disk can read them,
+// so a warning is logged when they are used outside APP_ENV=development.
+func NewFromEnv() (Mailer, error) {
+	kind := os.Getenv("MAILER")
+	if (kind == "" || kind == "log" || kind == "file") && os.Getenv("APP_ENV") != "development" {
+		log.Printf("WARNING: MAILER=%q writes password reset links to the log or disk; set MAILER=smtp outside development", kind)
+	}
+	switch kind {
+	case "", "log":
+		return NewLogMailer(), nil
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return NewFileMailer(dir), nil
+	case "smtp":
+		config, err := SMTPConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewSMTPMailer(config), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", kind)
+	}
+}
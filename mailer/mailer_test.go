@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		appEnv   string
+		mailer   string
+		want     string // type of the mailer, or "" for an error
+		wantWarn bool
+	}{
+		{name: "unset outside development", want: "*mailer.LogMailer", wantWarn: true},
+		{name: "file outside development", mailer: "file", want: "*mailer.FileMailer", wantWarn: true},
+		{name: "smtp outside development", mailer: "smtp", want: "*mailer.SMTPMailer"},
+		{name: "unset in development", appEnv: "development", want: "*mailer.LogMailer"},
+		{name: "file in development", appEnv: "development", mailer: "file", want: "*mailer.FileMailer"},
+		{name: "unknown", appEnv: "development", mailer: "carrier-pigeon"},
+	}
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("MAILER", tt.mailer)
+			logs.Reset()
+			got, err := NewFromEnv()
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("NewFromEnv() = %T, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewFromEnv(): %v", err)
+			}
+			if typ := fmt.Sprintf("%T", got); typ != tt.want {
+				t.Errorf("NewFromEnv() = %s, want %s", typ, tt.want)
+			}
+			if warned := strings.Contains(logs.String(), "WARNING"); warned != tt.wantWarn {
+				t.Errorf("NewFromEnv() logged %q, want a warning: %v", logs.String(), tt.wantWarn)
+			}
+		})
+	}
+}
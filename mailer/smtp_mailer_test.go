@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// startStalledSMTP accepts connections but never sends the SMTP greeting.
+func startStalledSMTP(t *testing.T) SMTPConfig {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{Host: addr.IP.String(), Port: addr.Port, From: defaultFrom}
+}
+
+func TestSMTPMailerGivesUpOnStalledServer(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+	}{
+		{"deadline", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 100*time.Millisecond)
+		}},
+		{"cancel", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+			return ctx, cancel
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewSMTPMailer(startStalledSMTP(t))
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() { done <- m.Send(ctx, Message{To: "ada@example.com", Subject: "Hi", Body: "Hello"}) }()
+			select {
+			case err := <-done:
+				// Send may return just before ctx records its deadline.
+				<-ctx.Done()
+				if err == nil || !errors.Is(err, ctx.Err()) {
+					t.Errorf("Send = %v, want it to report %v", err, ctx.Err())
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Send still blocked after the context was done")
+			}
+		})
+	}
+}
@@ -1,44 +1,95 @@
 package middlewares
 
 import (
+	"errors"
+	"math"
 	"net/http"
-	"os"
 	"strings"
 	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"todo-list-api/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// JWTAuthMiddleware validates the JWT token and sets the userID in the context.
-func JWTAuthMiddleware() gin.HandlerFunc {
+// Errors reported for requests without a usable token.
+var (
+	errMissingToken = apperrors.Unauthorized("missing_token", "missing bearer token")
+	errInvalidToken = apperrors.Unauthorized("invalid_token", "invalid token")
+	errTokenExpired = apperrors.Unauthorized("token_expired", "token expired")
+	errTokenRevoked = apperrors.Unauthorized("token_revoked", "token has been revoked")
+)
+
+// errEmailNotVerified is reported for writes by users who have not verified their email.
+var errEmailNotVerified = apperrors.Forbidden("email_not_verified", "verify your email address before making changes")
+
+// AuthConfig configures JWTAuthMiddleware.
+type AuthConfig struct {
+	Revocations services.RevocationService
+	// AccessTokens authenticates personal access tokens.
+	AccessTokens services.PersonalAccessTokenService
+	// Users is used to load the user on every request, so that disabling an
+	// account or changing its role takes effect immediately.
+	Users services.AuthService
+	// RequireVerifiedEmail rejects requests other than GET, HEAD and OPTIONS
+	// from users who have not verified their email address.
+	RequireVerifiedEmail bool
+}
+
+// JWTAuthMiddleware validates the JWT token, rejects tokens that have been
+// revoked and sets the userID in the context. The token's jti and expiry are
+// stored as tokenID and tokenExpiresAt so that it can be revoked on logout.
+// Personal access tokens are accepted as well; for them the token's ID is
+// stored as accessTokenID instead. The token's scopes are stored as scopes
+// for RequireScopes, and the user's current role as role for RequireRole.
+// Tokens of disabled users are rejected.
+func JWTAuthMiddleware(config AuthConfig) gin.HandlerFunc {
+	revocations := config.Revocations
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+			abortWithError(c, errMissingToken)
 			return
 		}
 
 		// Expect header format: "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+			abortWithError(c, errInvalidToken)
 			return
 		}
 		tokenString := parts[1]
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			secret = "your_secret_key"
+		if strings.HasPrefix(tokenString, services.PersonalAccessTokenPrefix) {
+			accessToken, err := config.AccessTokens.Authenticate(tokenString)
+			if err != nil {
+				abortWithError(c, err)
+				return
+			}
+			userID := accessToken.UserID.Hex()
+			if !loadUser(c, config, userID) {
+				return
+			}
+			c.Set("userID", userID)
+			c.Set("accessTokenID", accessToken.ID.Hex())
+			c.Set("scopes", models.EffectiveScopes(accessToken.Scopes))
+			c.Next()
+			return
 		}
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			// Validate the signing method.
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, jwt.ErrSignatureInvalid
 			}
-			return []byte(secret), nil
+			return services.JWTSecret(), nil
 		})
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			abortWithError(c, errTokenExpired)
+			return
+		}
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid token"})
+			abortWithError(c, errInvalidToken)
 			return
 		}
 
@@ -46,18 +97,75 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 			if exp, ok := claims["exp"].(float64); ok {
 				if int64(exp) < time.Now().Unix() {
-					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Token expired"})
+					abortWithError(c, errTokenExpired)
 					return
 				}
 			}
+			userID, _ := claims["user_id"].(string)
+			// Tokens issued before revocation existed have no jti or iat and
+			// could not be checked against revocations, so they are refused.
+			tokenID, _ := claims["jti"].(string)
+			iat, ok := claims["iat"].(float64)
+			if tokenID == "" || !ok {
+				abortWithError(c, errInvalidToken)
+				return
+			}
+			issuedAt := time.UnixMilli(int64(math.Round(iat * 1000)))
+			revoked, err := revocations.IsRevoked(tokenID, userID, issuedAt)
+			if err != nil {
+				abortWithError(c, err)
+				return
+			}
+			if revoked {
+				abortWithError(c, errTokenRevoked)
+				return
+			}
+			if !loadUser(c, config, userID) {
+				return
+			}
 			// Store the user_id from the token in the Gin context.
-			if userID, ok := claims["user_id"].(string); ok {
-				c.Set("userID", userID)
+			c.Set("userID", userID)
+			c.Set("tokenID", tokenID)
+			// Tokens issued before scopes existed have no scope claim.
+			scope, _ := claims["scope"].(string)
+			c.Set("scopes", models.EffectiveScopes(strings.Fields(scope)))
+			if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+				c.Set("tokenExpiresAt", exp.Time)
 			}
 			c.Next()
 		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid token"})
+			abortWithError(c, errInvalidToken)
 			return
 		}
 	}
 }
+
+// loadUser loads the token's user and stores their role in the context. It
+// aborts the request and reports false if the user no longer exists, is
+// disabled, or makes a write without a verified email while config requires one.
+func loadUser(c *gin.Context, config AuthConfig, userID string) bool {
+	user, err := config.Users.GetUser(userID)
+	if err != nil {
+		abortWithError(c, err)
+		return false
+	}
+	if user.Disabled {
+		abortWithError(c, services.ErrAccountDisabled)
+		return false
+	}
+	if config.RequireVerifiedEmail && !user.EmailVerified && !isReadOnly(c.Request.Method) {
+		abortWithError(c, errEmailNotVerified)
+		return false
+	}
+	role := user.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+	c.Set("role", role)
+	return true
+}
+
+// isReadOnly reports whether requests with the method do not change state.
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
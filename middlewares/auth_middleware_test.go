@@ -0,0 +1,195 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeRevocations reports the listed token IDs as revoked.
+type fakeRevocations struct {
+	revoked map[string]bool
+}
+
+func (r *fakeRevocations) RevokeToken(jti string, userID string, expiresAt time.Time) error {
+	return nil
+}
+
+func (r *fakeRevocations) RevokeUser(userID string) error {
+	return nil
+}
+
+func (r *fakeRevocations) IsRevoked(jti string, userID string, issuedAt time.Time) (bool, error) {
+	return r.revoked[jti], nil
+}
+
+// fakeUsers reports the listed users as having verified their email or
+// being disabled.
+type fakeUsers struct {
+	services.AuthService
+	verified map[string]bool
+	disabled map[string]bool
+}
+
+func (u *fakeUsers) GetUser(userID string) (*models.User, error) {
+	return &models.User{EmailVerified: u.verified[userID], Disabled: u.disabled[userID]}, nil
+}
+
+// fakeAccessTokens accepts the listed personal access tokens.
+type fakeAccessTokens struct {
+	services.PersonalAccessTokenService
+	tokens map[string]*models.PersonalAccessToken
+}
+
+func (a *fakeAccessTokens) Authenticate(raw string) (*models.PersonalAccessToken, error) {
+	token, ok := a.tokens[raw]
+	if !ok {
+		return nil, services.ErrInvalidAccessToken
+	}
+	return token, nil
+}
+
+func signTestToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	now := time.Now()
+	valid := jwt.MapClaims{"jti": "a", "user_id": "u1", "iat": float64(now.UnixMilli()) / 1000, "exp": now.Add(time.Minute).Unix()}
+	without := func(key string) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			if k != key {
+				claims[k] = v
+			}
+		}
+		return claims
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := without(key)
+		claims[key] = value
+		return claims
+	}
+	tests := []struct {
+		name     string
+		header   string
+		wantCode int
+		wantUser string
+	}{
+		{"valid", "Bearer " + signTestToken(t, "test-secret", valid), http.StatusOK, "u1"},
+		{"missing", "", http.StatusUnauthorized, ""},
+		{"not bearer", "Basic " + signTestToken(t, "test-secret", valid), http.StatusUnauthorized, ""},
+		{"wrong secret", "Bearer " + signTestToken(t, "other-secret", valid), http.StatusUnauthorized, ""},
+		{"expired", "Bearer " + signTestToken(t, "test-secret", with("exp", now.Add(-time.Minute).Unix())), http.StatusUnauthorized, ""},
+		{"no jti", "Bearer " + signTestToken(t, "test-secret", without("jti")), http.StatusUnauthorized, ""},
+		{"no iat", "Bearer " + signTestToken(t, "test-secret", without("iat")), http.StatusUnauthorized, ""},
+		{"revoked", "Bearer " + signTestToken(t, "test-secret", with("jti", "revoked")), http.StatusUnauthorized, ""},
+		{"disabled user", "Bearer " + signTestToken(t, "test-secret", with("user_id", "disabled")), http.StatusForbidden, ""},
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/me", JWTAuthMiddleware(AuthConfig{
+		Revocations: &fakeRevocations{revoked: map[string]bool{"revoked": true}},
+		Users:       &fakeUsers{disabled: map[string]bool{"disabled": true}},
+	}), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("userID"))
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantCode || (tt.wantUser != "" && w.Body.String() != tt.wantUser) {
+				t.Errorf("response = %d %s, want %d %s", w.Code, w.Body, tt.wantCode, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestJWTAuthMiddlewareRequiresVerifiedEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	tokenFor := func(userID string) string {
+		now := time.Now()
+		return "Bearer " + signTestToken(t, "test-secret", jwt.MapClaims{"jti": userID, "user_id": userID, "iat": float64(now.UnixMilli()) / 1000, "exp": now.Add(time.Minute).Unix()})
+	}
+	gin.SetMode(gin.TestMode)
+	for _, require := range []bool{false, true} {
+		r := gin.New()
+		r.Use(ErrorHandler())
+		r.Use(JWTAuthMiddleware(AuthConfig{
+			Revocations:          &fakeRevocations{},
+			Users:                &fakeUsers{verified: map[string]bool{"verified": true}},
+			RequireVerifiedEmail: require,
+		}))
+		r.GET("/todos", func(c *gin.Context) { c.Status(http.StatusOK) })
+		r.POST("/todos", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		for _, tt := range []struct {
+			method, user string
+			wantCode     int
+		}{
+			{http.MethodGet, "unverified", http.StatusOK},
+			{http.MethodPost, "verified", http.StatusOK},
+			{http.MethodPost, "unverified", map[bool]int{false: http.StatusOK, true: http.StatusForbidden}[require]},
+		} {
+			req := httptest.NewRequest(tt.method, "/todos", nil)
+			req.Header.Set("Authorization", tokenFor(tt.user))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("RequireVerifiedEmail=%v: %s by %s user = %d %s, want %d", require, tt.method, tt.user, w.Code, w.Body, tt.wantCode)
+			}
+		}
+	}
+}
+
+func TestJWTAuthMiddlewareAcceptsAccessTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	token := &models.PersonalAccessToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/me", JWTAuthMiddleware(AuthConfig{
+		Revocations:  &fakeRevocations{},
+		AccessTokens: &fakeAccessTokens{tokens: map[string]*models.PersonalAccessToken{"pat_valid": token}},
+		Users:        &fakeUsers{},
+	}), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("userID")+" "+c.GetString("accessTokenID")+c.GetString("tokenID"))
+	})
+	for _, tt := range []struct {
+		name     string
+		header   string
+		wantCode int
+		wantBody string
+	}{
+		{"valid", "Bearer pat_valid", http.StatusOK, token.UserID.Hex() + " " + token.ID.Hex()},
+		{"unknown", "Bearer pat_unknown", http.StatusUnauthorized, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", tt.header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantCode || (tt.wantBody != "" && w.Body.String() != tt.wantBody) {
+				t.Errorf("response = %d %s, want %d %s", w.Code, w.Body, tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"todo-list-api/apperrors"
+	"todo-list-api/query"
+	"todo-list-api/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler writes the last error a handler attached with c.Error as an
+// RFC 7807 application/problem+json response. Handlers report failures by
+// calling c.Error and returning without writing a body.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		respondProblem(c, c.Errors.Last())
+	}
+}
+
+// NoRoute reports requests for unknown routes as a problem.
+func NoRoute(c *gin.Context) {
+	c.Error(apperrors.NotFound("route_not_found", "no route matches "+c.Request.Method+" "+c.Request.URL.Path))
+}
+
+// abortWithError stops the handler chain with err, which ErrorHandler reports.
+func abortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+func respondProblem(c *gin.Context, ginErr *gin.Error) {
+	problem := problemFor(ginErr)
+	problem.Instance = c.Request.URL.Path
+	if problem.Status == http.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, ginErr.Err)
+	}
+	c.Header("Content-Type", apperrors.ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
+
+// problemFor maps an error to its problem details. Errors that are not typed
+// are internal; their messages may contain driver details and are not shown.
+func problemFor(ginErr *gin.Error) apperrors.Problem {
+	err := ginErr.Err
+	if fields, ok := validation.FieldErrors(err); ok {
+		problem := newProblem(http.StatusBadRequest, "validation_failed", "request validation failed")
+		problem.Errors = fields
+		return problem
+	}
+	var queryErr *query.Error
+	if errors.As(err, &queryErr) {
+		problem := newProblem(http.StatusBadRequest, "invalid_filter", queryErr.Error())
+		problem.Position = &queryErr.Pos
+		problem.Token = queryErr.Token
+		return problem
+	}
+	if appErr, ok := apperrors.As(err); ok {
+		detail := err.Error()
+		if appErr.Kind == apperrors.KindInternal {
+			detail = appErr.Message
+		}
+		return newProblem(appErr.Kind.Status(), appErr.Code, detail)
+	}
+	if ginErr.IsType(gin.ErrorTypeBind) {
+		return newProblem(http.StatusBadRequest, "invalid_body", err.Error())
+	}
+	internal := apperrors.Internal(err)
+	return newProblem(internal.Kind.Status(), internal.Code, internal.Message)
+}
+
+func newProblem(status int, code, detail string) apperrors.Problem {
+	return apperrors.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"todo-list-api/apperrors"
+	"todo-list-api/query"
+	"todo-list-api/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestErrorHandlerWritesProblems(t *testing.T) {
+	_, queryErr := query.Parse("status:open AND")
+	notFound := apperrors.NotFound("todo_not_found", "todo not found")
+	tests := []struct {
+		name string
+		err  error
+		want apperrors.Problem
+	}{
+		{"typed", notFound, apperrors.Problem{Title: "Not Found", Status: 404, Code: "todo_not_found", Detail: "todo not found"}},
+		{"wrapped typed", fmt.Errorf("loading todo: %w", notFound), apperrors.Problem{Title: "Not Found", Status: 404, Code: "todo_not_found", Detail: "loading todo: todo not found"}},
+		{"field errors", validation.Errors{"title": "is required"}, apperrors.Problem{Title: "Bad Request", Status: 400, Code: "validation_failed", Detail: "request validation failed", Errors: map[string]string{"title": "is required"}}},
+		{"filter", queryErr, apperrors.Problem{Title: "Bad Request", Status: 400, Code: "invalid_filter", Detail: queryErr.Error(), Position: intPtr(16)}},
+		{"untyped", errors.New("connection refused by 10.0.0.5"), apperrors.Problem{Title: "Internal Server Error", Status: 500, Code: "internal_error", Detail: "an unexpected error occurred"}},
+		{"internal", apperrors.Internal(errors.New("secret detail")), apperrors.Problem{Title: "Internal Server Error", Status: 500, Code: "internal_error", Detail: "an unexpected error occurred"}},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(ErrorHandler())
+			r.GET("/todos/1", func(c *gin.Context) { c.Error(tt.err) })
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/todos/1", nil))
+
+			if got := w.Header().Get("Content-Type"); got != apperrors.ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", got, apperrors.ProblemContentType)
+			}
+			var got apperrors.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode %s: %v", w.Body, err)
+			}
+			want := tt.want
+			want.Type, want.Instance = "about:blank", "/todos/1"
+			if want.Position != nil {
+				want.Token = got.Token
+			}
+			if w.Code != want.Status || !reflect.DeepEqual(got, want) {
+				t.Errorf("response = %d %+v, want %+v", w.Code, got, want)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerLeavesWrittenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.NoRoute(NoRoute)
+	r.GET("/ok", func(c *gin.Context) {
+		c.Error(errors.New("logged only"))
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") == apperrors.ProblemContentType {
+		t.Errorf("written response replaced: %d %s", w.Code, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/nowhere", nil))
+	var problem apperrors.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil || w.Code != http.StatusNotFound || problem.Code != "route_not_found" {
+		t.Errorf("unknown route = %d %s, want a route_not_found problem", w.Code, w.Body)
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"todo-list-api/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole rejects requests from users without the given role. It runs
+// after JWTAuthMiddleware, which loads the user's current role.
+func RequireRole(role string) gin.HandlerFunc {
+	errRoleRequired := apperrors.Forbidden("role_required", "this endpoint requires the "+role+" role")
+	return func(c *gin.Context) {
+		if c.GetString("role") != role {
+			abortWithError(c, errRoleRequired)
+			return
+		}
+		c.Next()
+	}
+}
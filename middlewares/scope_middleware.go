@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"strings"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScopes rejects requests whose token lacks any of the given scopes.
+// It runs after JWTAuthMiddleware, which stores the token's scopes.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	errInsufficientScope := apperrors.Forbidden("insufficient_scope", "token lacks the required scope: "+strings.Join(scopes, " "))
+	return func(c *gin.Context) {
+		if !models.HasScopes(c.GetStringSlice("scopes"), scopes...) {
+			abortWithError(c, errInsufficientScope)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-list-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRequireScopes(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	now := time.Now()
+	jwtWithScope := func(scope interface{}) string {
+		claims := jwt.MapClaims{"jti": "a", "user_id": "u1", "iat": float64(now.UnixMilli()) / 1000, "exp": now.Add(time.Minute).Unix()}
+		if scope != nil {
+			claims["scope"] = scope
+		}
+		return "Bearer " + signTestToken(t, "test-secret", claims)
+	}
+	accessTokens := &fakeAccessTokens{tokens: map[string]*models.PersonalAccessToken{
+		"pat_read":     {ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Scopes: []string{models.ScopeTodosRead}},
+		"pat_unscoped": {ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()},
+	}}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.Use(JWTAuthMiddleware(AuthConfig{Revocations: &fakeRevocations{}, AccessTokens: accessTokens, Users: &fakeUsers{}}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/todos", RequireScopes(models.ScopeTodosRead), ok)
+	r.POST("/todos", RequireScopes(models.ScopeTodosWrite), ok)
+	r.GET("/admin", RequireScopes(models.ScopeAdmin), ok)
+	r.POST("/both", RequireScopes(models.ScopeTodosRead, models.ScopeProfile), ok)
+
+	tests := []struct {
+		name, header, method, path string
+		wantCode                   int
+	}{
+		{"read scope reads", jwtWithScope("todos:read"), http.MethodGet, "/todos", http.StatusOK},
+		{"read scope writes", jwtWithScope("todos:read"), http.MethodPost, "/todos", http.StatusForbidden},
+		{"space separated scopes", jwtWithScope("todos:read profile"), http.MethodPost, "/both", http.StatusOK},
+		{"missing one of two scopes", jwtWithScope("todos:read"), http.MethodPost, "/both", http.StatusForbidden},
+		{"no scope claim gets defaults", jwtWithScope(nil), http.MethodPost, "/todos", http.StatusOK},
+		{"defaults exclude admin", jwtWithScope(nil), http.MethodGet, "/admin", http.StatusForbidden},
+		{"admin scope", jwtWithScope("admin"), http.MethodGet, "/admin", http.StatusOK},
+		{"scoped access token reads", "Bearer pat_read", http.MethodGet, "/todos", http.StatusOK},
+		{"scoped access token writes", "Bearer pat_read", http.MethodPost, "/todos", http.StatusForbidden},
+		{"unscoped access token gets defaults", "Bearer pat_unscoped", http.MethodPost, "/todos", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", tt.header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("%s %s = %d %s, want %d", tt.method, tt.path, w.Code, w.Body, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Admin actions recorded in the audit log.
+const (
+	AuditListUsers          = "list_users"
+	AuditViewUser           = "view_user"
+	AuditViewTodoCounts     = "view_todo_counts"
+	AuditSetRole            = "set_role"
+	AuditDisableUser        = "disable_user"
+	AuditEnableUser         = "enable_user"
+	AuditForcePasswordReset = "force_password_reset"
+	AuditDeleteUser         = "delete_user"
+	AuditViewAuditLog       = "view_audit_log"
+)
+
+// Outcomes of an audited action. An entry is written as pending before the
+// action runs and marked completed or failed afterwards, so an action is
+// never taken without a record of it.
+const (
+	AuditPending   = "pending"
+	AuditCompleted = "completed"
+	AuditFailed    = "failed"
+)
+
+// AuditLogEntry records an action an admin took. Apart from the outcome,
+// entries are never changed.
+type AuditLogEntry struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty"`
+	ActorID primitive.ObjectID `bson:"actor_id"`
+	Action  string             `bson:"action"`
+	// TargetUserID is the user the action concerned, if any.
+	TargetUserID *primitive.ObjectID `bson:"target_user_id,omitempty"`
+	// Details holds action-specific values, such as a search query or a new role.
+	Details   map[string]string `bson:"details,omitempty"`
+	Status    string            `bson:"status,omitempty"`
+	CreatedAt time.Time         `bson:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ChecklistItem is a single step in a to-do item's embedded checklist.
+type ChecklistItem struct {
+	ID       primitive.ObjectID `bson:"_id" json:"id"`
+	Text     string             `bson:"text" json:"text" example:"Buy milk"`
+	Done     bool               `bson:"done" json:"done"`
+	Position int                `bson:"position" json:"position"`
+}
+
+// Progress summarises how much of a to-do item's checklist is done.
+type Progress struct {
+	Done  int `json:"done" example:"3"`
+	Total int `json:"total" example:"5"`
+}
+
+// UpdateProgress recomputes the todo's checklist progress. Todos without a
+// checklist have no progress.
+func (t *Todo) UpdateProgress() {
+	if len(t.Checklist) == 0 {
+		t.Progress = nil
+		return
+	}
+	progress := Progress{Total: len(t.Checklist)}
+	for _, item := range t.Checklist {
+		if item.Done {
+			progress.Done++
+		}
+	}
+	t.Progress = &progress
+}
@@ -0,0 +1,10 @@
+package models
+
+// MFAEnrollment is a new TOTP secret waiting to be confirmed with a first code.
+type MFAEnrollment struct {
+	Secret string
+	// URL is the otpauth:// URI that authenticator apps import.
+	URL string
+	// QRCode is a PNG image of URL.
+	QRCode []byte
+}
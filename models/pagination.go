@@ -0,0 +1,34 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// PageRequest selects a page of a to-do listing. Offset mode uses Page; keyset
+// mode starts at the beginning when Cursor is empty and otherwise continues
+// from the position the cursor token encodes.
+type PageRequest struct {
+	Page   int64
+	Limit  int64
+	Keyset bool
+	Cursor string
+	// Count requests the total number of matching todos.
+	Count bool
+}
+
+// TodoPage is a page of a to-do listing. Total is nil when no count was
+// requested; the cursors are empty in offset mode and at either end of the listing.
+type TodoPage struct {
+	Todos      []Todo
+	Total      *int64
+	NextCursor string
+	PrevCursor string
+}
+
+// TodoCursor is a position in a sorted to-do listing: the sort key and _id of
+// the todo at the edge of a page. Before selects the todos preceding it
+// instead of those following it.
+type TodoCursor struct {
+	Sort   TodoSort           `bson:"s"`
+	Value  interface{}        `bson:"v"`
+	ID     primitive.ObjectID `bson:"i"`
+	Before bool               `bson:"b,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PersonalAccessToken is a long-lived token a user creates for scripts and
+// CI. Only the SHA-256 hash of the token is stored; the token itself is shown
+// once, when it is created.
+type PersonalAccessToken struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	UserID primitive.ObjectID `bson:"user_id"`
+	Name   string             `bson:"name"`
+	Scopes []string           `bson:"scopes,omitempty"`
+	// Prefix is the start of the token, so users can tell their tokens apart.
+	Prefix    string     `bson:"prefix"`
+	TokenHash string     `bson:"token_hash"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"`
+	// LastUsedAt is updated at most once a minute.
+	LastUsedAt *time.Time `bson:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `bson:"created_at"`
+}
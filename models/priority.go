@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Priority ranks how urgent a to-do item is. It is stored as an integer so
+// MongoDB can sort on it, and exposed in JSON by name.
+type Priority int
+
+// Priority levels in ascending order of urgency.
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+// ParsePriority converts a priority name into its Priority level.
+func ParsePriority(name string) (Priority, error) {
+	for i, n := range priorityNames {
+		if n == name {
+			return Priority(i), nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("invalid priority %q", name)
+}
+
+// IsValid reports whether p is a known priority level.
+func (p Priority) IsValid() bool {
+	return p >= PriorityNone && p <= PriorityUrgent
+}
+
+// String returns the priority name.
+func (p Priority) String() string {
+	if !p.IsValid() {
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+	return priorityNames[p]
+}
+
+// MarshalJSON encodes the priority by name.
+func (p Priority) MarshalJSON() ([]byte, error) {
+	if !p.IsValid() {
+		return nil, fmt.Errorf("invalid priority %d", int(p))
+	}
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes a priority name; an empty string means none.
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("priority must be one of none, low, medium, high, urgent")
+	}
+	if name == "" {
+		*p = PriorityNone
+		return nil
+	}
+	parsed, err := ParsePriority(name)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
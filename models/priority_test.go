@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPriorityJSON(t *testing.T) {
+	tests := []struct {
+		json string
+		want Priority
+	}{
+		{`"none"`, PriorityNone},
+		{`""`, PriorityNone},
+		{`"low"`, PriorityLow},
+		{`"medium"`, PriorityMedium},
+		{`"high"`, PriorityHigh},
+		{`"urgent"`, PriorityUrgent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var got Priority
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", tt.json, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.json, got, tt.want)
+			}
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal(%v) error = %v", got, err)
+			}
+			if want := `"` + tt.want.String() + `"`; string(data) != want {
+				t.Errorf("Marshal(%v) = %s, want %s", got, data, want)
+			}
+		})
+	}
+}
+
+func TestPriorityRejectsUnknownValues(t *testing.T) {
+	for _, input := range []string{`"critical"`, `"High"`, `3`} {
+		var p Priority
+		if err := json.Unmarshal([]byte(input), &p); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", input, p)
+		}
+	}
+	if _, err := json.Marshal(Priority(7)); err == nil {
+		t.Error("Marshal(Priority(7)) succeeded, want an error")
+	}
+	if Priority(-1).IsValid() || Priority(5).IsValid() {
+		t.Error("out of range priorities reported as valid")
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is a long-lived token that can be exchanged once for a new
+// access/refresh token pair. Only the SHA-256 hash of the token is stored.
+// Tokens issued from the same login share a family; presenting a token that
+// was already rotated revokes the whole family.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	FamilyID  primitive.ObjectID `bson:"family_id"`
+	TokenHash string             `bson:"token_hash"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+	CreatedAt time.Time          `bson:"created_at"`
+	// RotatedAt is set once the token has been exchanged for a new pair.
+	RotatedAt *time.Time `bson:"rotated_at,omitempty"`
+	// RevokedAt is set when the token's family was revoked.
+	RevokedAt *time.Time `bson:"revoked_at,omitempty"`
+}
+
+// TokenPair is the result of a login or refresh: a short-lived access token
+// and the refresh token that replaces it when it expires.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresIn is the lifetime of the access token.
+	ExpiresIn time.Duration
+}
+
+// LoginResult is the outcome of checking a user's password: either a token
+// pair, or, for users with MFA enabled, a challenge token to be exchanged for
+// a token pair together with a second-factor code.
+type LoginResult struct {
+	Tokens   *TokenPair
+	MFAToken string
+	// MFAExpiresIn is the lifetime of the challenge token.
+	MFAExpiresIn time.Duration
+}
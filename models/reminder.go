@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reminder notifies the user about a to-do item at a given time. It is either
+// absolute (At) or relative to the todo's due date (OffsetMinutes before due_at).
+type Reminder struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	At            *time.Time         `bson:"at,omitempty" json:"at,omitempty"`
+	OffsetMinutes *int               `bson:"offset_minutes,omitempty" json:"offset_minutes,omitempty" example:"30"`
+	// FireAt is when the reminder is due to be sent, computed by the server.
+	FireAt time.Time  `bson:"fire_at" json:"fire_at"`
+	SentAt *time.Time `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	// Attempts counts failed deliveries and RetryAt is when the next one is
+	// made; after too many failures the scheduler gives up and sets FailedAt.
+	Attempts int        `bson:"attempts,omitempty" json:"-"`
+	RetryAt  *time.Time `bson:"retry_at,omitempty" json:"-"`
+	FailedAt *time.Time `bson:"failed_at,omitempty" json:"failed_at,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevokedToken records an access token that was revoked before it expired,
+// identified by its jti claim. It is kept until the token would have expired.
+type RevokedToken struct {
+	ID        string             `bson:"_id"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+}
+
+// UserTokenRevocation revokes every access token of a user issued before
+// RevokedBefore, for example after logging out everywhere or changing the
+// password. It is kept until the last of those tokens would have expired.
+type UserTokenRevocation struct {
+	UserID        primitive.ObjectID `bson:"_id"`
+	RevokedBefore time.Time          `bson:"revoked_before"`
+	ExpiresAt     time.Time          `bson:"expires_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// Scopes limit what a token may be used for.
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+	ScopeProfile    = "profile"
+	ScopeAdmin      = "admin"
+)
+
+// AllScopes lists every known scope.
+var AllScopes = []string{ScopeTodosRead, ScopeTodosWrite, ScopeProfile, ScopeAdmin}
+
+// DefaultScopes are granted to tokens that do not name any scopes, such as
+// access tokens issued before scopes existed. They allow everything a user
+// could do before.
+var DefaultScopes = []string{ScopeTodosRead, ScopeTodosWrite, ScopeProfile}
+
+// ScopesForRole returns the scopes of access tokens issued to users with
+// the role: the default scopes, and for admins the admin scope as well.
+func ScopesForRole(role string) []string {
+	if role == RoleAdmin {
+		return append(append([]string{}, DefaultScopes...), ScopeAdmin)
+	}
+	return DefaultScopes
+}
+
+// EffectiveScopes returns the scopes a token with the given scopes has.
+func EffectiveScopes(scopes []string) []string {
+	if len(scopes) == 0 {
+		return DefaultScopes
+	}
+	return scopes
+}
+
+// HasScopes reports whether granted contains every required scope.
+func HasScopes(granted []string, required ...string) bool {
+	for _, scope := range required {
+		found := false
+		for _, g := range granted {
+			if g == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
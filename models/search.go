@@ -0,0 +1,35 @@
+package models
+
+// Fields a search term can be restricted to with a field prefix such as title:foo.
+const (
+	SearchFieldTitle       = "title"
+	SearchFieldDescription = "description"
+)
+
+// SearchTerm is a single word or quoted phrase of a full-text search query.
+type SearchTerm struct {
+	// Text is the word or phrase to look for.
+	Text string
+	// Phrase marks a quoted phrase that must match as a whole.
+	Phrase bool
+	// Negated excludes todos that match the term.
+	Negated bool
+	// Field restricts the term to one field; empty matches title or description.
+	Field string
+}
+
+// TodoSearch is a parsed full-text search query combined with the usual list filters.
+type TodoSearch struct {
+	Terms  []SearchTerm
+	Filter TodoFilter
+}
+
+// TodoSearchResult is a to-do item matched by a search, with its relevance
+// score and HTML snippets of the matching fields.
+type TodoSearchResult struct {
+	Todo `bson:",inline"`
+	// Score is the text relevance; it is zero when the query only uses field prefixes.
+	Score float64 `bson:"score,omitempty" json:"score"`
+	// Highlights maps field names to HTML-escaped snippets with matches wrapped in <mark>.
+	Highlights map[string]string `bson:"-" json:"highlights,omitempty"`
+}
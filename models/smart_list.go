@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Keys of the built-in smart lists every user has.
+const (
+	SmartListToday    = "today"
+	SmartListUpcoming = "upcoming"
+	SmartListOverdue  = "overdue"
+)
+
+// SmartList is a named, saved to-do filter. Built-in lists are not stored;
+// they have no ID and are identified by Key instead.
+type SmartList struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Key       string             `bson:"-" json:"key,omitempty" enums:"today,upcoming,overdue"`
+	Name      string             `bson:"name" json:"name" example:"Work this week"`
+	Filter    SmartListFilter    `bson:"filter" json:"filter"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// SmartListFilter holds the saved criteria of a smart list. Relative windows
+// such as Due=today are resolved each time the list is opened.
+type SmartListFilter struct {
+	Statuses   []string            `bson:"statuses,omitempty" json:"statuses,omitempty" binding:"dive,oneof=open in_progress done cancelled" example:"open,in_progress"`
+	Due        string              `bson:"due,omitempty" json:"due,omitempty" binding:"omitempty,oneof=overdue today week none" enums:"overdue,today,week,none"`
+	DueAfter   *time.Time          `bson:"due_after,omitempty" json:"due_after,omitempty"`
+	DueBefore  *time.Time          `bson:"due_before,omitempty" json:"due_before,omitempty"`
+	Timezone   string              `bson:"timezone,omitempty" json:"timezone,omitempty" binding:"omitempty,timezone" example:"Europe/Berlin"`
+	Labels     []string            `bson:"labels,omitempty" json:"labels,omitempty" binding:"max=20,dive,required,max=50" example:"work"`
+	LabelMatch string              `bson:"label_match,omitempty" json:"label_match,omitempty" binding:"omitempty,oneof=any all" enums:"any,all"`
+	ProjectID  *primitive.ObjectID `bson:"project_id,omitempty" json:"project_id,omitempty" swaggertype:"string"`
+	// Query is a filter expression, as accepted by GET /todos?filter=.
+	Query string `bson:"query,omitempty" json:"query,omitempty" binding:"max=1024" example:"priority>=high"`
+	// Search is a full-text search query; when set, results are ranked by relevance unless Sort is given.
+	Search string `bson:"search,omitempty" json:"search,omitempty" binding:"max=500"`
+	Sort   string `bson:"sort,omitempty" json:"sort,omitempty" binding:"omitempty,oneof=priority due_at created_at updated_at title" enums:"priority,due_at,created_at,updated_at,title"`
+	Order  string `bson:"order,omitempty" json:"order,omitempty" binding:"omitempty,oneof=asc desc" enums:"asc,desc"`
+}
+
+// TodoFilter converts the saved criteria into a to-do list filter.
+func (f SmartListFilter) TodoFilter() TodoFilter {
+	filter := TodoFilter{
+		Statuses:   f.Statuses,
+		Due:        f.Due,
+		Timezone:   f.Timezone,
+		DueAfter:   f.DueAfter,
+		DueBefore:  f.DueBefore,
+		Labels:     f.Labels,
+		LabelMatch: f.LabelMatch,
+		Query:      f.Query,
+		Sort:       TodoSort{Field: f.Sort, Descending: f.Order == "desc"},
+	}
+	if f.ProjectID != nil {
+		filter.ProjectID = *f.ProjectID
+	}
+	return filter
+}
@@ -6,12 +6,145 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Todo lifecycle states.
+const (
+	TodoStatusOpen       = "open"
+	TodoStatusInProgress = "in_progress"
+	TodoStatusDone       = "done"
+	TodoStatusCancelled  = "cancelled"
+)
+
+// TodoStatuses lists every valid lifecycle state.
+var TodoStatuses = []string{
+	TodoStatusOpen,
+	TodoStatusInProgress,
+	TodoStatusDone,
+	TodoStatusCancelled,
+}
+
+// IsValidTodoStatus reports whether status is a known lifecycle state.
+func IsValidTodoStatus(status string) bool {
+	for _, s := range TodoStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Recurrence anchors accepted by Todo.RepeatFrom.
+const (
+	// RepeatFromDue schedules the next occurrence from the current due date.
+	RepeatFromDue = "due"
+	// RepeatFromCompletion schedules the next occurrence from the completion date.
+	RepeatFromCompletion = "completion"
+)
+
 // Todo represents a task or to-do list item.
 type Todo struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Title       string             `bson:"title" json:"title"`
-	Description string             `bson:"description" json:"description"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	Title       string              `bson:"title" json:"title"`
+	Description string              `bson:"description" json:"description"`
+	Status      string              `bson:"status" json:"status" enums:"open,in_progress,done,cancelled"`
+	Priority    Priority            `bson:"priority" json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	Labels      []string            `bson:"labels,omitempty" json:"labels,omitempty" example:"work,errands"`
+	ProjectID   *primitive.ObjectID `bson:"project_id,omitempty" json:"project_id,omitempty" swaggertype:"string"`
+	Checklist   []ChecklistItem     `bson:"checklist,omitempty" json:"checklist,omitempty"`
+	Progress    *Progress           `bson:"-" json:"progress,omitempty"`
+	Reminders   []Reminder          `bson:"reminders,omitempty" json:"reminders,omitempty"`
+	CompletedAt *time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	StartAt     *time.Time          `bson:"start_at,omitempty" json:"start_at,omitempty"`
+	DueAt       *time.Time          `bson:"due_at,omitempty" json:"due_at,omitempty"`
+	Timezone    string              `bson:"timezone,omitempty" json:"timezone,omitempty" example:"Europe/Berlin"`
+	// RRule is an RFC 5545 recurrence rule (without DTSTART) for repeating todos.
+	RRule            string              `bson:"rrule,omitempty" json:"rrule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	RepeatFrom       string              `bson:"repeat_from,omitempty" json:"repeat_from,omitempty" enums:"due,completion"`
+	NextOccurrenceID *primitive.ObjectID `bson:"next_occurrence_id,omitempty" json:"next_occurrence_id,omitempty" swaggertype:"string"`
+	UserID           primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	CreatedAt        time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Predefined due date windows accepted by TodoFilter.Due.
+const (
+	DueOverdue  = "overdue"
+	DueToday    = "today"
+	DueThisWeek = "week"
+	DueNone     = "none"
+)
+
+// Fields to-do items can be sorted by.
+const (
+	SortByPriority  = "priority"
+	SortByDueAt     = "due_at"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+	SortByTitle     = "title"
+)
+
+// TodoSortFields lists every field accepted by TodoSort.Field.
+var TodoSortFields = []string{
+	SortByPriority,
+	SortByDueAt,
+	SortByCreatedAt,
+	SortByUpdatedAt,
+	SortByTitle,
+}
+
+// IsValidTodoSortField reports whether field can be used to sort to-do items.
+func IsValidTodoSortField(field string) bool {
+	for _, f := range TodoSortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// TodoSort describes the order in which to-do items are listed.
+type TodoSort struct {
+	Field      string
+	Descending bool
+}
+
+// Label matching modes accepted by TodoFilter.LabelMatch.
+const (
+	LabelMatchAny = "any"
+	LabelMatchAll = "all"
+)
+
+// TodoFilter holds the optional criteria used when listing to-do items.
+type TodoFilter struct {
+	// Statuses restricts results to todos in any of the given states.
+	Statuses []string
+	// Due selects a predefined due date window (overdue, today, week or none).
+	Due string
+	// Timezone is the IANA location used to compute day and week boundaries.
+	Timezone string
+	// DueAfter and DueBefore bound the due date (inclusive and exclusive respectively).
+	DueAfter  *time.Time
+	DueBefore *time.Time
+	// NoDueDate restricts results to todos without a due date.
+	NoDueDate bool
+	// Pending restricts results to todos that are neither done nor cancelled.
+	Pending bool
+	// Labels restricts results to todos carrying the given label names,
+	// matching any of them or all of them depending on LabelMatch.
+	Labels     []string
+	LabelMatch string
+	// ProjectID restricts results to todos in the given project.
+	ProjectID primitive.ObjectID
+	// Query is a filter expression such as `status:open AND priority>=high`,
+	// compiled by the query package.
+	Query string
+	// Sort orders the results; the zero value sorts by creation time.
+	Sort TodoSort
+}
+
+// TodoCounts summarises a user's to-do items.
+type TodoCounts struct {
+	Total    int64
+	ByStatus map[string]int64
+	// Overdue counts open and in-progress items whose due date has passed.
+	Overdue int64
 }
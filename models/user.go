@@ -1,11 +1,59 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// User roles. Users stored without a role have RoleUser.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Roles lists every valid role.
+var Roles = []string{RoleUser, RoleAdmin}
 
 // User represents a registered user in the system.
 type User struct {
 	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	Name     string             `bson:"name" json:"name"`
 	Email    string             `bson:"email" json:"email"`
-	Password string             `bson:"password" json:"password"` // omit in responses
+	Password string             `bson:"password" json:"-"` // bcrypt hash, never serialised
+	Role     string             `bson:"role,omitempty" json:"role"`
+	// Disabled users cannot log in and their tokens are rejected.
+	Disabled   bool       `bson:"disabled,omitempty" json:"disabled"`
+	DisabledAt *time.Time `bson:"disabled_at,omitempty" json:"disabled_at,omitempty"`
+	// PasswordResetRequired is set when an admin forces a password reset;
+	// logging in fails until the user sets a new password.
+	PasswordResetRequired bool `bson:"password_reset_required,omitempty" json:"password_reset_required"`
+	// Timezone is the IANA timezone the built-in smart lists use for "today".
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"`
+	// EmailVerified is set once the user opened the link sent to their email.
+	EmailVerified bool       `bson:"email_verified" json:"email_verified"`
+	VerifiedAt    *time.Time `bson:"verified_at,omitempty" json:"verified_at,omitempty"`
+	// MFAEnabled is set once the user confirmed a TOTP authenticator; logging
+	// in then requires a code from it or a recovery code.
+	MFAEnabled bool   `bson:"mfa_enabled" json:"mfa_enabled"`
+	MFASecret  string `bson:"mfa_secret,omitempty" json:"-"`
+	// MFAPendingSecret is the secret of an enrollment that was not confirmed yet.
+	MFAPendingSecret string `bson:"mfa_pending_secret,omitempty" json:"-"`
+	// MFARecoveryCodes holds the SHA-256 hashes of the unused recovery codes.
+	MFARecoveryCodes []string `bson:"mfa_recovery_codes,omitempty" json:"-"`
+	// MFALastStep is the TOTP time step of the last accepted code, so that a
+	// code cannot be used twice.
+	MFALastStep int64 `bson:"mfa_last_step,omitempty" json:"-"`
+	// MFAChallengeID identifies the only MFA login challenge that may still
+	// be completed; a new login or too many wrong codes replace or clear it.
+	MFAChallengeID string `bson:"mfa_challenge_id,omitempty" json:"-"`
+	// MFAFailedAttempts counts the codes tried since the last successful MFA login.
+	MFAFailedAttempts int `bson:"mfa_failed_attempts,omitempty" json:"-"`
+	// MFALockedUntil blocks MFA logins after too many wrong codes.
+	MFALockedUntil *time.Time `bson:"mfa_locked_until,omitempty" json:"-"`
+}
+
+// IsAdmin reports whether the user has the admin role.
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
 }
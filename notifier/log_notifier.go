@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"context"
+	"log"
+)
+
+// LogNotifier writes notifications to the application log. It is the default
+// notifier and is useful for local development.
+type LogNotifier struct{}
+
+// NewLogNotifier returns a new LogNotifier.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the notification.
+func (n *LogNotifier) Notify(_ context.Context, notification Notification) error {
+	log.Printf("Reminder for %s (user %s): %q (todo %s)", notification.Email, notification.UserID, notification.Title, notification.TodoID)
+	return nil
+}
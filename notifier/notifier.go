@@ -0,0 +1,51 @@
+// Package notifier delivers reminder notifications through pluggable channels.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+	"todo-list-api/mailer"
+)
+
+// Notification describes a reminder for a to-do item.
+type Notification struct {
+	UserID      string     `json:"user_id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	TodoID      string     `json:"todo_id"`
+	ReminderID  string     `json:"reminder_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	FireAt      time.Time  `json:"fire_at"`
+}
+
+// Notifier sends notifications to users.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewFromEnv builds the notifier selected by REMINDER_NOTIFIER (log, smtp or
+// webhook), defaulting to the log notifier.
+func NewFromEnv() (Notifier, error) {
+	switch kind := os.Getenv("REMINDER_NOTIFIER"); kind {
+	case "", "log":
+		return NewLogNotifier(), nil
+	case "smtp":
+		config, err := mailer.SMTPConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewSMTPNotifier(mailer.NewSMTPMailer(config)), nil
+	case "webhook":
+		url := os.Getenv("WEBHOOK_URL")
+		if url == "" {
+			return nil, fmt.Errorf("WEBHOOK_URL is required for the webhook notifier")
+		}
+		return NewWebhookNotifier(url, os.Getenv("WEBHOOK_SECRET")), nil
+	default:
+		return nil, fmt.Errorf("unknown REMINDER_NOTIFIER %q", kind)
+	}
+}
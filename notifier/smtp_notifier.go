@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"todo-list-api/mailer"
+)
+
+// SMTPNotifier emails notifications. It sends through a mailer, normally
+// mailer.SMTPMailer, so that reminders and account emails share one SMTP
+// implementation.
+type SMTPNotifier struct {
+	mailer mailer.Mailer
+}
+
+// NewSMTPNotifier returns a new SMTPNotifier sending through m.
+func NewSMTPNotifier(m mailer.Mailer) *SMTPNotifier {
+	return &SMTPNotifier{m}
+}
+
+// Notify emails the notification to the user.
+func (n *SMTPNotifier) Notify(ctx context.Context, notification Notification) error {
+	if notification.Email == "" {
+		return fmt.Errorf("user %s has no email address", notification.UserID)
+	}
+	return n.mailer.Send(ctx, message(notification))
+}
+
+func message(notification Notification) mailer.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Reminder: %s\n", notification.Title)
+	if notification.DueAt != nil {
+		fmt.Fprintf(&body, "Due: %s\n", notification.DueAt.Format(time.RFC1123))
+	}
+	if notification.Description != "" {
+		fmt.Fprintf(&body, "\n%s\n", notification.Description)
+	}
+	return mailer.Message{
+		To:      notification.Email,
+		Subject: "Reminder: " + notification.Title,
+		Body:    body.String(),
+	}
+}
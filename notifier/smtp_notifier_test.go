@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+	"todo-list-api/mailer"
+)
+
+// smtpMessage is an email received by the fake SMTP server.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// startFakeSMTP runs a minimal SMTP server on a local port that accepts every
+// message and delivers it on the returned channel.
+func startFakeSMTP(t *testing.T) (host string, port int, messages <-chan smtpMessage) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan smtpMessage, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, received)
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func serveSMTP(conn net.Conn, received chan<- smtpMessage) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	reply := func(line string) { _ = text.PrintfLine("%s", line) }
+
+	var msg smtpMessage
+	reply("220 localhost fake SMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			msg.from = smtpAddress(line)
+			reply("250 OK")
+		case "RCPT":
+			msg.to = append(msg.to, smtpAddress(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = string(data)
+			received <- msg
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// smtpAddress extracts the address from a MAIL FROM or RCPT TO command.
+func smtpAddress(line string) string {
+	start, end := strings.Index(line, "<"), strings.LastIndex(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func TestSMTPNotifierSendsThroughSMTPServer(t *testing.T) {
+	host, port, messages := startFakeSMTP(t)
+	n := NewSMTPNotifier(mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "reminders@example.com",
+	}))
+
+	due := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	err := n.Notify(context.Background(), Notification{
+		UserID:      "u1",
+		Email:       "ada@example.com",
+		Title:       "Pay rent\r\nBcc: victim@example.com",
+		Description: "Transfer before noon",
+		DueAt:       &due,
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var msg smtpMessage
+	select {
+	case msg = <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+	if msg.from != "reminders@example.com" {
+		t.Errorf("MAIL FROM = %q", msg.from)
+	}
+	if len(msg.to) != 1 || msg.to[0] != "ada@example.com" {
+		t.Errorf("RCPT TO = %v, want [ada@example.com]", msg.to)
+	}
+
+	headers, body, _ := strings.Cut(msg.data, "\n\n")
+	for _, want := range []string{
+		"To: ada@example.com",
+		"Subject: Reminder: Pay rent  Bcc: victim@example.com",
+	} {
+		if !strings.Contains(headers, want+"\n") {
+			t.Errorf("headers missing %q:\n%s", want, headers)
+		}
+	}
+	if strings.Contains(headers, "\nBcc:") {
+		t.Errorf("title injected a header:\n%s", headers)
+	}
+	for _, want := range []string{"Due: " + due.Format(time.RFC1123), "Transfer before noon"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestSMTPNotifierRequiresEmail(t *testing.T) {
+	n := NewSMTPNotifier(mailer.NewSMTPMailer(mailer.SMTPConfig{Host: "127.0.0.1", Port: 1}))
+	if err := n.Notify(context.Background(), Notification{UserID: "u1", Title: "Pay rent"}); err == nil {
+		t.Fatal("Notify without an email address succeeded")
+	}
+}
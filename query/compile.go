@@ -0,0 +1,260 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fieldKind int
+
+const (
+	kindStatus fieldKind = iota
+	kindPriority
+	kindLabel
+	kindProject
+	kindText
+	kindDate
+)
+
+// field is a filterable to-do field: the document key it maps to and how its values are read.
+type field struct {
+	key  string
+	kind fieldKind
+}
+
+// fields whitelists the names usable in filter expressions. Document keys
+// only ever come from this table, never from user input.
+var fields = map[string]field{
+	"status":      {"status", kindStatus},
+	"priority":    {"priority", kindPriority},
+	"label":       {"labels", kindLabel},
+	"labels":      {"labels", kindLabel},
+	"project":     {"project_id", kindProject},
+	"title":       {"title", kindText},
+	"description": {"description", kindText},
+	"due":         {"due_at", kindDate},
+	"start":       {"start_at", kindDate},
+	"completed":   {"completed_at", kindDate},
+	"created":     {"created_at", kindDate},
+	"updated":     {"updated_at", kindDate},
+}
+
+// Fields returns the field names accepted in filter expressions, sorted.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options control how filter values are interpreted.
+type Options struct {
+	// Location is used for plain dates and day keywords; nil means UTC.
+	Location *time.Location
+	// Now anchors today, tomorrow, yesterday and now; zero means the current time.
+	Now time.Time
+}
+
+// Compile parses a filter expression and converts it into a MongoDB filter
+// document over the todos collection.
+func Compile(input string, opts Options) (bson.M, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	c := compiler{opts}
+	return c.compile(node)
+}
+
+type compiler struct {
+	opts Options
+}
+
+func (c compiler) compile(node Node) (bson.M, error) {
+	switch n := node.(type) {
+	case *And:
+		operands, err := c.compileAll(n.Operands)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$and": operands}, nil
+	case *Or:
+		operands, err := c.compileAll(n.Operands)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$or": operands}, nil
+	case *Not:
+		operand, err := c.compile(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$nor": bson.A{operand}}, nil
+	case *Comparison:
+		return c.compileComparison(n)
+	default:
+		return nil, fmt.Errorf("query: unknown node %T", node)
+	}
+}
+
+func (c compiler) compileAll(nodes []Node) (bson.A, error) {
+	operands := make(bson.A, 0, len(nodes))
+	for _, node := range nodes {
+		operand, err := c.compile(node)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	return operands, nil
+}
+
+func (c compiler) compileComparison(cmp *Comparison) (bson.M, error) {
+	f, ok := fields[strings.ToLower(cmp.Field)]
+	if !ok {
+		return nil, &Error{Pos: cmp.FieldPos, Token: cmp.Field, Msg: fmt.Sprintf("unknown field %q, expected one of %s", cmp.Field, strings.Join(Fields(), ", "))}
+	}
+	ordered := cmp.Op == "<" || cmp.Op == "<=" || cmp.Op == ">" || cmp.Op == ">="
+	if ordered && f.kind != kindPriority && f.kind != kindDate {
+		return nil, &Error{Pos: cmp.OpPos, Token: cmp.Op, Msg: fmt.Sprintf("operator %s is not supported for field %q", cmp.Op, cmp.Field)}
+	}
+	negate := cmp.Op == "!="
+
+	switch f.kind {
+	case kindStatus:
+		if !models.IsValidTodoStatus(cmp.Value) {
+			return nil, c.badValue(cmp, "status", strings.Join(models.TodoStatuses, ", "))
+		}
+		values := bson.A{cmp.Value}
+		// Todos created before statuses existed have no status field; treat them as open.
+		if cmp.Value == models.TodoStatusOpen {
+			values = append(values, nil)
+		}
+		if negate {
+			return bson.M{f.key: bson.M{"$nin": values}}, nil
+		}
+		return bson.M{f.key: bson.M{"$in": values}}, nil
+
+	case kindPriority:
+		priority, err := models.ParsePriority(strings.ToLower(cmp.Value))
+		if err != nil {
+			return nil, c.badValue(cmp, "priority", "none, low, medium, high, urgent")
+		}
+		return bson.M{f.key: bson.M{mongoOp(cmp.Op): priority}}, nil
+
+	case kindLabel:
+		return bson.M{f.key: bson.M{mongoOp(cmp.Op): cmp.Value}}, nil
+
+	case kindProject:
+		id, err := primitive.ObjectIDFromHex(cmp.Value)
+		if err != nil {
+			return nil, c.badValue(cmp, "project id", "a 24 character hex id")
+		}
+		return bson.M{f.key: bson.M{mongoOp(cmp.Op): id}}, nil
+
+	case kindText:
+		if cmp.Op == ":" {
+			// Substring match; the value is escaped so it is never interpreted as a pattern.
+			return bson.M{f.key: primitive.Regex{Pattern: regexp.QuoteMeta(cmp.Value), Options: "i"}}, nil
+		}
+		return bson.M{f.key: bson.M{mongoOp(cmp.Op): cmp.Value}}, nil
+
+	case kindDate:
+		return c.compileDate(cmp, f)
+	}
+	return nil, fmt.Errorf("query: unknown field kind %d", f.kind)
+}
+
+// compileDate compares a date field with a timestamp, a calendar day or
+// "none". A calendar day (YYYY-MM-DD, today, tomorrow, yesterday) covers the
+// whole day in the configured location, so due<2026-11-01 means before that
+// day starts and due<=2026-11-01 includes it.
+func (c compiler) compileDate(cmp *Comparison, f field) (bson.M, error) {
+	value := strings.ToLower(cmp.Value)
+	if value == "none" || value == "null" {
+		switch cmp.Op {
+		case ":", "=":
+			return bson.M{f.key: nil}, nil
+		case "!=":
+			return bson.M{f.key: bson.M{"$ne": nil}}, nil
+		}
+		return nil, &Error{Pos: cmp.OpPos, Token: cmp.Op, Msg: fmt.Sprintf("operator %s cannot be used with none", cmp.Op)}
+	}
+
+	if value == "now" {
+		return bson.M{f.key: bson.M{mongoOp(cmp.Op): c.opts.Now}}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, cmp.Value); err == nil {
+		return bson.M{f.key: bson.M{mongoOp(cmp.Op): t}}, nil
+	}
+
+	var start time.Time
+	y, m, d := c.opts.Now.In(c.opts.Location).Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, c.opts.Location)
+	switch value {
+	case "today":
+		start = today
+	case "tomorrow":
+		start = today.AddDate(0, 0, 1)
+	case "yesterday":
+		start = today.AddDate(0, 0, -1)
+	default:
+		t, err := time.ParseInLocation("2006-01-02", cmp.Value, c.opts.Location)
+		if err != nil {
+			return nil, c.badValue(cmp, "date", "an RFC 3339 timestamp, YYYY-MM-DD, today, tomorrow, yesterday, now or none")
+		}
+		start = t
+	}
+	end := start.AddDate(0, 0, 1)
+	switch cmp.Op {
+	case "<":
+		return bson.M{f.key: bson.M{"$lt": start}}, nil
+	case "<=":
+		return bson.M{f.key: bson.M{"$lt": end}}, nil
+	case ">":
+		return bson.M{f.key: bson.M{"$gte": end}}, nil
+	case ">=":
+		return bson.M{f.key: bson.M{"$gte": start}}, nil
+	case "!=":
+		return bson.M{f.key: bson.M{"$not": bson.M{"$gte": start, "$lt": end}}}, nil
+	default:
+		return bson.M{f.key: bson.M{"$gte": start, "$lt": end}}, nil
+	}
+}
+
+func (c compiler) badValue(cmp *Comparison, what, expected string) *Error {
+	return &Error{Pos: cmp.ValuePos, Token: cmp.Value, Msg: fmt.Sprintf("invalid %s %q for field %q, expected %s", what, cmp.Value, cmp.Field, expected)}
+}
+
+// mongoOp maps a comparison operator to its MongoDB query operator.
+func mongoOp(op string) string {
+	switch op {
+	case "!=":
+		return "$ne"
+	case "<":
+		return "$lt"
+	case "<=":
+		return "$lte"
+	case ">":
+		return "$gt"
+	case ">=":
+		return "$gte"
+	default:
+		return "$eq"
+	}
+}
@@ -0,0 +1,123 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOp
+	tokenWord
+	tokenString
+)
+
+// token is a lexical unit of a filter expression. Pos is the 1-based
+// character position of its first character in the input.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe names the token in error messages.
+func (t token) describe() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return "\"" + t.text + "\""
+}
+
+// lex splits a filter expression into tokens. The word following a
+// comparison operator is read as a value, so values such as RFC 3339
+// timestamps may contain ':' without quoting.
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	afterOp := false
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", pos})
+			i++
+		case r == '"':
+			text, end, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenString, text, pos})
+			i = end
+		case !afterOp && isOpChar(r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != ':' && r != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &Error{Pos: pos, Token: op, Msg: "unknown operator, expected :, =, !=, <, <=, > or >="}
+			}
+			tokens = append(tokens, token{tokenOp, op, pos})
+			i += len(op)
+			afterOp = true
+			continue
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' && (afterOp || !isOpChar(runes[i])) {
+				i++
+			}
+			text := string(runes[start:i])
+			kind := tokenWord
+			if !afterOp {
+				switch strings.ToUpper(text) {
+				case "AND":
+					kind = tokenAnd
+				case "OR":
+					kind = tokenOr
+				case "NOT":
+					kind = tokenNot
+				}
+			}
+			tokens = append(tokens, token{kind, text, pos})
+		}
+		afterOp = false
+	}
+	return append(tokens, token{tokenEOF, "", len(runes) + 1}), nil
+}
+
+// lexString reads the double-quoted string starting at runes[start]. A
+// backslash escapes the following character.
+func lexString(runes []rune, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			}
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, &Error{Pos: start + 1, Token: "\"", Msg: "unterminated string"}
+}
+
+func isOpChar(r rune) bool {
+	return r == ':' || r == '=' || r == '!' || r == '<' || r == '>'
+}
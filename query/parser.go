@@ -0,0 +1,207 @@
+package query
+
+import "fmt"
+
+// MaxLength is the longest filter expression accepted, in characters.
+const MaxLength = 1024
+
+// maxDepth bounds the nesting of parentheses and NOT operators.
+const maxDepth = 32
+
+// Node is a parsed filter expression.
+type Node interface {
+	node()
+}
+
+// And matches documents matched by every operand.
+type And struct {
+	Operands []Node
+}
+
+// Or matches documents matched by any operand.
+type Or struct {
+	Operands []Node
+}
+
+// Not matches documents not matched by its operand.
+type Not struct {
+	Operand Node
+}
+
+// Comparison compares a field with a value, e.g. priority>=high.
+type Comparison struct {
+	Field    string
+	Op       string
+	Value    string
+	FieldPos int
+	OpPos    int
+	ValuePos int
+}
+
+func (*And) node()        {}
+func (*Or) node()         {}
+func (*Not) node()        {}
+func (*Comparison) node() {}
+
+// Error describes an invalid filter expression. Pos is the 1-based character
+// position of the offending token, which is also reported as Token.
+type Error struct {
+	Pos   int
+	Token string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses a filter expression. The grammar, from lowest to highest precedence, is
+//
+//	expr       = and { "OR" and }
+//	and        = unary { ["AND"] unary }
+//	unary      = "NOT" unary | "(" expr ")" | comparison
+//	comparison = field op value
+//	op         = ":" | "=" | "!=" | "<" | "<=" | ">" | ">="
+//
+// Keywords are case-insensitive, adjacent terms are joined with AND, and
+// values containing spaces or parentheses must be double-quoted.
+func Parse(input string) (Node, error) {
+	if n := len([]rune(input)); n > MaxLength {
+		return nil, &Error{Pos: MaxLength + 1, Msg: fmt.Sprintf("expression is longer than %d characters", MaxLength)}
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 1, Msg: "empty expression"}
+	}
+	node, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t, "AND, OR or end of expression")
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) unexpected(t token, expected string) *Error {
+	return &Error{Pos: t.pos, Token: t.text, Msg: fmt.Sprintf("unexpected %s, expected %s", t.describe(), expected)}
+}
+
+func (p *parser) parseOr(depth int) (Node, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	operands := []Node{left}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+	if len(operands) == 1 {
+		return left, nil
+	}
+	return &Or{Operands: operands}, nil
+}
+
+func (p *parser) parseAnd(depth int) (Node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	operands := []Node{left}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenNot, tokenLParen, tokenWord:
+		default:
+			if len(operands) == 1 {
+				return left, nil
+			}
+			return &And{Operands: operands}, nil
+		}
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, right)
+	}
+}
+
+func (p *parser) parseUnary(depth int) (Node, error) {
+	t := p.peek()
+	if depth >= maxDepth {
+		return nil, &Error{Pos: t.pos, Token: t.text, Msg: fmt.Sprintf("expression is nested more than %d levels deep", maxDepth)}
+	}
+	switch t.kind {
+	case tokenNot:
+		p.next()
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: operand}, nil
+	case tokenLParen:
+		p.next()
+		node, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != tokenRParen {
+			return nil, p.unexpected(closing, "\")\" to close \"(\" at position "+fmt.Sprint(t.pos))
+		}
+		p.next()
+		return node, nil
+	case tokenWord:
+		return p.parseComparison()
+	default:
+		return nil, p.unexpected(t, "a field, NOT or \"(\"")
+	}
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	field := p.next()
+	op := p.peek()
+	if op.kind != tokenOp {
+		return nil, p.unexpected(op, "an operator after field \""+field.text+"\"")
+	}
+	p.next()
+	value := p.peek()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, p.unexpected(value, "a value after \""+field.text+op.text+"\"")
+	}
+	p.next()
+	return &Comparison{
+		Field:    field.text,
+		Op:       op.text,
+		Value:    value.text,
+		FieldPos: field.pos,
+		OpPos:    op.pos,
+		ValuePos: value.pos,
+	}, nil
+}
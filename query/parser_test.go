@@ -0,0 +1,138 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// format renders a node as an S-expression so parse trees can be compared as strings.
+func format(node Node) string {
+	switch n := node.(type) {
+	case *And:
+		return "(AND " + formatAll(n.Operands) + ")"
+	case *Or:
+		return "(OR " + formatAll(n.Operands) + ")"
+	case *Not:
+		return "(NOT " + format(n.Operand) + ")"
+	case *Comparison:
+		return n.Field + n.Op + n.Value
+	}
+	return "?"
+}
+
+func formatAll(nodes []Node) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = format(node)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"status:open", "status:open"},
+		{"status != done", "status!=done"},
+		{"status:open priority>=high", "(AND status:open priority>=high)"},
+		{"status:open and priority>=high", "(AND status:open priority>=high)"},
+		{"a:1 OR b:2 c:3", "(OR a:1 (AND b:2 c:3))"},
+		{"a:1 b:2 or c:3", "(OR (AND a:1 b:2) c:3)"},
+		{"(a:1 OR b:2) c:3", "(AND (OR a:1 b:2) c:3)"},
+		{"not a:1 and b:2", "(AND (NOT a:1) b:2)"},
+		{"NOT NOT a:1", "(NOT (NOT a:1))"},
+		{"NOT (a:1 OR b:2)", "(NOT (OR a:1 b:2))"},
+		{"((a:1))", "a:1"},
+		{`title:"buy milk (2)"`, "title:buy milk (2)"},
+		{`title:"say \"hi\""`, `title:say "hi"`},
+		{"due>=2026-11-01T09:00:00Z", "due>=2026-11-01T09:00:00Z"},
+		{"label:AND", "label:AND"},
+		{"due<=today", "due<=today"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got := format(node); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		pos     int
+		message string
+	}{
+		{"empty", "", 1, "empty expression"},
+		{"blank", "   ", 1, "empty expression"},
+		{"missing operator", "status", 7, `expected an operator after field "status"`},
+		{"missing value", "status:", 8, `expected a value after "status:"`},
+		{"missing value after ordered operator", "due<", 5, `expected a value after "due<"`},
+		{"dangling AND", "status:open AND", 16, `expected a field, NOT or "("`},
+		{"dangling NOT", "status:open NOT", 16, `expected a field, NOT or "("`},
+		{"leading OR", "OR status:open", 1, `unexpected "OR"`},
+		{"double OR", "a:1 OR OR b:2", 8, `unexpected "OR"`},
+		{"unclosed parenthesis", "(status:open", 13, `")" to close "(" at position 1`},
+		{"extra closing parenthesis", "status:open)", 12, `unexpected ")", expected AND, OR or end of expression`},
+		{"empty parentheses", "()", 2, `unexpected ")"`},
+		{"unterminated string", `title:"milk`, 7, "unterminated string"},
+		{"unknown operator", "status!open", 7, "unknown operator"},
+		{"too deep", strings.Repeat("(", maxDepth+1) + "a:1" + strings.Repeat(")", maxDepth+1), maxDepth + 1, "nested more than 32 levels"},
+		{"too deep with NOT", strings.Repeat("NOT ", maxDepth+1) + "a:1", 4*maxDepth + 1, "nested more than 32 levels"},
+		{"too long", "title:" + strings.Repeat("a", MaxLength), MaxLength + 1, "longer than 1024 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) = %s, want an error", tt.input, format(node))
+			}
+			var perr *Error
+			if !errors.As(err, &perr) {
+				t.Fatalf("Parse(%q) error = %T, want *Error", tt.input, err)
+			}
+			if perr.Pos != tt.pos {
+				t.Errorf("Parse(%q) error position = %d, want %d (%v)", tt.input, perr.Pos, tt.pos, err)
+			}
+			if !strings.Contains(perr.Msg, tt.message) {
+				t.Errorf("Parse(%q) error = %q, want it to mention %q", tt.input, perr.Msg, tt.message)
+			}
+		})
+	}
+}
+
+func TestCompileRejectsInvalidComparisons(t *testing.T) {
+	tests := []struct {
+		input   string
+		pos     int
+		message string
+	}{
+		{"colour:red", 1, `unknown field "colour"`},
+		{"status:open title>milk", 18, `operator > is not supported for field "title"`},
+		{"status:someday", 8, `invalid status "someday"`},
+		{"priority>=huge", 11, `invalid priority "huge"`},
+		{"project:abc", 9, "invalid project id"},
+		{"due:next-week", 5, `invalid date "next-week"`},
+		{"due<none", 4, "operator < cannot be used with none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Compile(tt.input, Options{})
+			var perr *Error
+			if !errors.As(err, &perr) {
+				t.Fatalf("Compile(%q) error = %v, want *Error", tt.input, err)
+			}
+			if perr.Pos != tt.pos || !strings.Contains(perr.Msg, tt.message) {
+				t.Errorf("Compile(%q) error = %d %q, want %d %q", tt.input, perr.Pos, perr.Msg, tt.pos, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Key returns the document key the comparison's field maps to, or "" when
+// the field is unknown.
+func (c *Comparison) Key() string {
+	return fields[strings.ToLower(c.Field)].key
+}
+
+// Rewrite parses input and passes each comparison to fn, which may change it
+// in place or return false to drop it. Operators left without operands are
+// dropped as well, so the result is "" when nothing remains. When fn changes
+// nothing, input is returned as written and changed is false.
+func Rewrite(input string, fn func(*Comparison) bool) (output string, changed bool, err error) {
+	node, err := Parse(input)
+	if err != nil {
+		return "", false, err
+	}
+	node = rewrite(node, fn, &changed)
+	if !changed {
+		return input, false, nil
+	}
+	if node == nil {
+		return "", true, nil
+	}
+	return Format(node), true, nil
+}
+
+func rewrite(node Node, fn func(*Comparison) bool, changed *bool) Node {
+	switch n := node.(type) {
+	case *And:
+		return rewriteOperands(n.Operands, fn, changed, func(operands []Node) Node { return &And{Operands: operands} })
+	case *Or:
+		return rewriteOperands(n.Operands, fn, changed, func(operands []Node) Node { return &Or{Operands: operands} })
+	case *Not:
+		operand := rewrite(n.Operand, fn, changed)
+		if operand == nil {
+			return nil
+		}
+		return &Not{Operand: operand}
+	case *Comparison:
+		before := *n
+		if !fn(n) {
+			*changed = true
+			return nil
+		}
+		if *n != before {
+			*changed = true
+		}
+		return n
+	}
+	return node
+}
+
+func rewriteOperands(nodes []Node, fn func(*Comparison) bool, changed *bool, join func([]Node) Node) Node {
+	var operands []Node
+	for _, node := range nodes {
+		if operand := rewrite(node, fn, changed); operand != nil {
+			operands = append(operands, operand)
+		}
+	}
+	switch len(operands) {
+	case 0:
+		return nil
+	case 1:
+		return operands[0]
+	}
+	return join(operands)
+}
+
+// Format prints a parsed expression in a form that Parse reads back as the
+// same expression. Keywords are upper case and values are quoted only when
+// they have to be.
+func Format(node Node) string {
+	switch n := node.(type) {
+	case *And:
+		return formatOperands(n.Operands, " AND ")
+	case *Or:
+		return formatOperands(n.Operands, " OR ")
+	case *Not:
+		return "NOT " + formatOperand(n.Operand)
+	case *Comparison:
+		return n.Field + n.Op + quote(n.Value)
+	}
+	return ""
+}
+
+func formatOperands(nodes []Node, separator string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = formatOperand(node)
+	}
+	return strings.Join(parts, separator)
+}
+
+// formatOperand parenthesizes nested AND and OR expressions. This is more
+// than precedence requires for AND inside OR, but reads unambiguously.
+func formatOperand(node Node) string {
+	switch node.(type) {
+	case *And, *Or:
+		return "(" + Format(node) + ")"
+	}
+	return Format(node)
+}
+
+// quote double-quotes a value that the lexer would otherwise split or end early.
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, `()"\`) && strings.IndexFunc(value, unicode.IsSpace) < 0 {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,73 @@
+package query
+
+import "testing"
+
+func TestRewrite(t *testing.T) {
+	renameWork := func(c *Comparison) bool {
+		if c.Key() == "labels" && c.Value == "work" {
+			c.Value = "day job"
+		}
+		return true
+	}
+	dropWork := func(c *Comparison) bool {
+		return c.Key() != "labels" || c.Value != "work"
+	}
+	tests := []struct {
+		name        string
+		input       string
+		fn          func(*Comparison) bool
+		want        string
+		wantChanged bool
+	}{
+		{"untouched keeps the input", "label:home  and  priority>=high", renameWork, "label:home  and  priority>=high", false},
+		{"rename quotes the value", "label:work priority>=high", renameWork, `label:"day job" AND priority>=high`, true},
+		{"rename under NOT and OR", "NOT (labels!=work OR status:done)", renameWork, `NOT (labels!="day job" OR status:done)`, true},
+		{"drop one operand", "label:work OR label:home", dropWork, "label:home", true},
+		{"drop inside a group", "(label:work OR label:home) status:open", dropWork, "label:home AND status:open", true},
+		{"drop a negation", "NOT label:work AND status:open", dropWork, "status:open", true},
+		{"drop everything", "label:work", dropWork, "", true},
+		{"other fields are kept", `title:work OR label:"work"`, dropWork, "title:work", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := Rewrite(tt.input, tt.fn)
+			if err != nil {
+				t.Fatalf("Rewrite(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("Rewrite(%q) = %q, %v; want %q, %v", tt.input, got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+	if _, _, err := Rewrite("label:", renameWork); err == nil {
+		t.Error("Rewrite(invalid) error = nil, want a parse error")
+	}
+}
+
+func TestFormatRoundTrips(t *testing.T) {
+	inputs := []string{
+		"status:open priority>=high",
+		"a:1 OR b:2 c:3",
+		"(a:1 OR b:2) c:3",
+		"NOT (a:1 OR b:2)",
+		"NOT NOT a:1",
+		`title:"say \"hi\" (twice)"`,
+		`title:"C:\\temp"`,
+		`title:""`,
+		"due>=2026-11-01T09:00:00Z",
+	}
+	for _, input := range inputs {
+		node, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", input, err)
+		}
+		formatted := Format(node)
+		again, err := Parse(formatted)
+		if err != nil {
+			t.Fatalf("Parse(Format(%q)) = Parse(%q) error = %v", input, formatted, err)
+		}
+		if format(again) != format(node) {
+			t.Errorf("Format(%q) = %q, which parses as %s, want %s", input, formatted, format(again), format(node))
+		}
+	}
+}
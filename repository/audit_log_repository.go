@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditLogRepository defines data access methods for the admin audit log.
+// Entries can only be added and read, apart from recording their outcome.
+type AuditLogRepository interface {
+	Create(entry *models.AuditLogEntry) error
+	// SetStatus records the outcome of a pending entry's action.
+	SetStatus(id primitive.ObjectID, status string) error
+	// GetEntries returns a page of entries, newest first, and the number of
+	// matching entries. A non-nil targetUserID restricts them to actions on
+	// that user.
+	GetEntries(targetUserID *primitive.ObjectID, page, limit int64) ([]models.AuditLogEntry, int64, error)
+}
+
+type auditLogRepository struct{}
+
+// NewAuditLogRepository returns a new instance of AuditLogRepository.
+func NewAuditLogRepository() AuditLogRepository {
+	return &auditLogRepository{}
+}
+
+func (r *auditLogRepository) Create(entry *models.AuditLogEntry) error {
+	collection := config.DB.Collection("audit_log")
+	entry.CreatedAt = time.Now()
+	res, err := collection.InsertOne(context.Background(), entry)
+	if err != nil {
+		return err
+	}
+	entry.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *auditLogRepository) SetStatus(id primitive.ObjectID, status string) error {
+	collection := config.DB.Collection("audit_log")
+	filter := bson.M{"_id": id, "status": models.AuditPending}
+	_, err := collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"status": status}})
+	return err
+}
+
+func (r *auditLogRepository) GetEntries(targetUserID *primitive.ObjectID, page, limit int64) ([]models.AuditLogEntry, int64, error) {
+	collection := config.DB.Collection("audit_log")
+	filter := bson.M{}
+	if targetUserID != nil {
+		filter["target_user_id"] = *targetUserID
+	}
+	total, err := collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
+	cursor, err := collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := []models.AuditLogEntry{}
+	if err := cursor.All(context.Background(), &entries); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+	"todo-list-api/apperrors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Errors returned when a document does not exist or belongs to another user.
+var (
+	ErrTodoNotFound      = apperrors.NotFound("todo_not_found", "todo not found")
+	ErrLabelNotFound     = apperrors.NotFound("label_not_found", "label not found")
+	ErrProjectNotFound   = apperrors.NotFound("project_not_found", "project not found")
+	ErrSmartListNotFound = apperrors.NotFound("smart_list_not_found", "smart list not found")
+	ErrUserNotFound      = apperrors.NotFound("user_not_found", "user not found")
+)
+
+// ErrTodoModified is returned when a conditional write finds that the todo
+// changed since it was read.
+var ErrTodoModified = apperrors.Conflict("todo_modified", "todo was modified by another request")
+
+// ErrDuplicate is returned when a write would violate a unique index.
+var ErrDuplicate = apperrors.Conflict("duplicate", "a conflicting document already exists")
+
+// translateError replaces MongoDB driver errors that callers act on with
+// typed errors; notFound is returned for a missing document. Other errors
+// are returned unchanged and surface as internal errors.
+func translateError(err error, notFound error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return notFound
+	case mongo.IsDuplicateKeyError(err):
+		return ErrDuplicate
+	default:
+		return err
+	}
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTranslateError(t *testing.T) {
+	other := errors.New("connection reset")
+	duplicate := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"no documents", mongo.ErrNoDocuments, ErrLabelNotFound},
+		{"wrapped no documents", fmt.Errorf("decode: %w", mongo.ErrNoDocuments), ErrLabelNotFound},
+		{"duplicate key", duplicate, ErrDuplicate},
+		{"other", other, other},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := translateError(tt.err, ErrLabelNotFound); got != tt.want {
+				t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
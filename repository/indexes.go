@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"todo-list-api/config"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the MongoDB indexes the repositories rely on.
+// Creating an index that already exists is a no-op, so it is safe to call on every start.
+func EnsureIndexes() error {
+	todoIndexes := []mongo.IndexModel{
+		// Due date range queries (due today, this week, no due date).
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "due_at", Value: 1}}},
+		// Status filters, including overdue which combines status and due date.
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "due_at", Value: 1}}},
+		// Sorted listings; _id keeps the order stable for equal keys.
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "priority", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "title", Value: 1}, {Key: "_id", Value: 1}}},
+		// Label filters and label rename/delete cascades.
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "labels", Value: 1}}},
+		// Project listings and project delete/move cascades.
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "project_id", Value: 1}}},
+		// Full-text search; title matches rank above description matches.
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "description", Value: 2}}).
+				SetName("user_id_text"),
+		},
+		// Reminder scheduler scans for unsent reminders that are due.
+		{Keys: bson.D{{Key: "reminders.fire_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+	}
+	if _, err := config.DB.Collection("todos").Indexes().CreateMany(context.Background(), todoIndexes); err != nil {
+		return err
+	}
+
+	labelIndexes := []mongo.IndexModel{
+		// Label names are unique per user.
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}
+	if _, err := config.DB.Collection("labels").Indexes().CreateMany(context.Background(), labelIndexes); err != nil {
+		return err
+	}
+
+	projectIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		// Every user has at most one inbox.
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"inbox": true}).SetName("user_id_inbox_unique"),
+		},
+	}
+	if _, err := config.DB.Collection("projects").Indexes().CreateMany(context.Background(), projectIndexes); err != nil {
+		return err
+	}
+
+	smartListIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	}
+	if _, err := config.DB.Collection("smart_lists").Indexes().CreateMany(context.Background(), smartListIndexes); err != nil {
+		return err
+	}
+
+	refreshTokenIndexes := []mongo.IndexModel{
+		// Refresh requests look tokens up by their hash.
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		// Reuse detection revokes a whole family.
+		{Keys: bson.D{{Key: "family_id", Value: 1}}},
+		// Logging out everywhere revokes all of a user's tokens.
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		// Expired tokens are removed by MongoDB.
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}
+	if _, err := config.DB.Collection("refresh_tokens").Indexes().CreateMany(context.Background(), refreshTokenIndexes); err != nil {
+		return err
+	}
+
+	passwordResetIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}
+	if _, err := config.DB.Collection("password_resets").Indexes().CreateMany(context.Background(), passwordResetIndexes); err != nil {
+		return err
+	}
+
+	accessTokenIndexes := []mongo.IndexModel{
+		// The middleware looks tokens up by their hash.
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		// Expired tokens are removed by MongoDB; tokens without expiry are kept.
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}
+	if _, err := config.DB.Collection("personal_access_tokens").Indexes().CreateMany(context.Background(), accessTokenIndexes); err != nil {
+		return err
+	}
+
+	auditLogIndexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		// Entries about one user.
+		{Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+	if _, err := config.DB.Collection("audit_log").Indexes().CreateMany(context.Background(), auditLogIndexes); err != nil {
+		return err
+	}
+
+	// Revocations are only needed until the tokens they cover expire.
+	expiryIndex := mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)}
+	for _, name := range []string{"revoked_tokens", "user_token_revocations"} {
+		if _, err := config.DB.Collection(name).Indexes().CreateOne(context.Background(), expiryIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
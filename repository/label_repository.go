@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LabelRepository defines data access methods for Label.
+type LabelRepository interface {
+	Create(label *models.Label) error
+	Update(label *models.Label) error
+	Delete(id primitive.ObjectID, userID primitive.ObjectID) error
+	GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Label, error)
+	GetLabels(userID primitive.ObjectID) ([]models.Label, error)
+	FindByNames(userID primitive.ObjectID, names []string) ([]models.Label, error)
+	DeleteByUser(userID primitive.ObjectID) error
+}
+
+type labelRepository struct{}
+
+// NewLabelRepository returns a new instance of LabelRepository.
+func NewLabelRepository() LabelRepository {
+	return &labelRepository{}
+}
+
+func (r *labelRepository) Create(label *models.Label) error {
+	collection := config.DB.Collection("labels")
+	label.CreatedAt = time.Now()
+	label.UpdatedAt = time.Now()
+	res, err := collection.InsertOne(context.Background(), label)
+	if err != nil {
+		return translateError(err, ErrLabelNotFound)
+	}
+	label.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *labelRepository) Update(label *models.Label) error {
+	collection := config.DB.Collection("labels")
+	label.UpdatedAt = time.Now()
+	filter := bson.M{"_id": label.ID, "user_id": label.UserID}
+	update := bson.M{"$set": bson.M{
+		"name":       label.Name,
+		"color":      label.Color,
+		"updated_at": label.UpdatedAt,
+	}}
+	res, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return translateError(err, ErrLabelNotFound)
+	}
+	if res.MatchedCount == 0 {
+		return ErrLabelNotFound
+	}
+	return nil
+}
+
+func (r *labelRepository) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
+	collection := config.DB.Collection("labels")
+	filter := bson.M{"_id": id, "user_id": userID}
+	res, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrLabelNotFound
+	}
+	return nil
+}
+
+func (r *labelRepository) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Label, error) {
+	collection := config.DB.Collection("labels")
+	var label models.Label
+	err := collection.FindOne(context.Background(), bson.M{"_id": id, "user_id": userID}).Decode(&label)
+	if err != nil {
+		return nil, translateError(err, ErrLabelNotFound)
+	}
+	return &label, nil
+}
+
+func (r *labelRepository) GetLabels(userID primitive.ObjectID) ([]models.Label, error) {
+	collection := config.DB.Collection("labels")
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	labels := []models.Label{}
+	if err := cursor.All(context.Background(), &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (r *labelRepository) FindByNames(userID primitive.ObjectID, names []string) ([]models.Label, error) {
+	collection := config.DB.Collection("labels")
+	filter := bson.M{"user_id": userID, "name": bson.M{"$in": names}}
+	cursor, err := collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+	var labels []models.Label
+	if err := cursor.All(context.Background(), &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (r *labelRepository) DeleteByUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("labels")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/config"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockRepository manages named leases stored in MongoDB so that only one
+// replica at a time runs a background job.
+type LockRepository interface {
+	// Acquire takes or renews the lease for owner and reports whether owner holds it.
+	Acquire(name, owner string, ttl time.Duration) (bool, error)
+	// Release gives up the lease if owner holds it.
+	Release(name, owner string) error
+}
+
+type lockRepository struct{}
+
+// NewLockRepository returns a new instance of LockRepository.
+func NewLockRepository() LockRepository {
+	return &lockRepository{}
+}
+
+func (r *lockRepository) Acquire(name, owner string, ttl time.Duration) (bool, error) {
+	collection := config.DB.Collection("locks")
+	now := time.Now()
+	// Match the lease if it has expired or is already ours; otherwise the
+	// upsert collides with the existing document on _id.
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lte": now}},
+			bson.M{"owner": owner},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(ttl)}}
+	_, err := collection.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *lockRepository) Release(name, owner string) error {
+	collection := config.DB.Collection("locks")
+	_, err := collection.DeleteOne(context.Background(), bson.M{"_id": name, "owner": owner})
+	return err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrPasswordResetNotFound is returned when no password reset has the given hash.
+var ErrPasswordResetNotFound = apperrors.NotFound("password_reset_not_found", "password reset not found")
+
+// PasswordResetRepository defines data access methods for password reset tokens.
+type PasswordResetRepository interface {
+	Create(reset *models.PasswordReset) error
+	FindByHash(hash string) (*models.PasswordReset, error)
+	// MarkUsed marks the reset as used and reports whether it was still
+	// unused, so that a token cannot be used twice concurrently.
+	MarkUsed(id primitive.ObjectID) (bool, error)
+	// Release marks a reset taken by MarkUsed as unused again, for when the
+	// password could not be changed.
+	Release(id primitive.ObjectID) error
+	// InvalidateUser marks every unused reset of the user as used.
+	InvalidateUser(userID primitive.ObjectID) error
+	// DeleteByUser removes every reset of the user when the account is deleted.
+	DeleteByUser(userID primitive.ObjectID) error
+}
+
+type passwordResetRepository struct{}
+
+// NewPasswordResetRepository returns a new instance of PasswordResetRepository.
+func NewPasswordResetRepository() PasswordResetRepository {
+	return &passwordResetRepository{}
+}
+
+func (r *passwordResetRepository) Create(reset *models.PasswordReset) error {
+	collection := config.DB.Collection("password_resets")
+	reset.CreatedAt = time.Now()
+	res, err := collection.InsertOne(context.Background(), reset)
+	if err != nil {
+		return translateError(err, ErrPasswordResetNotFound)
+	}
+	reset.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *passwordResetRepository) FindByHash(hash string) (*models.PasswordReset, error) {
+	collection := config.DB.Collection("password_resets")
+	var reset models.PasswordReset
+	err := collection.FindOne(context.Background(), bson.M{"token_hash": hash}).Decode(&reset)
+	if err != nil {
+		return nil, translateError(err, ErrPasswordResetNotFound)
+	}
+	return &reset, nil
+}
+
+func (r *passwordResetRepository) MarkUsed(id primitive.ObjectID) (bool, error) {
+	collection := config.DB.Collection("password_resets")
+	filter := bson.M{"_id": id, "used_at": nil}
+	res, err := collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"used_at": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *passwordResetRepository) Release(id primitive.ObjectID) error {
+	collection := config.DB.Collection("password_resets")
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$unset": bson.M{"used_at": ""}})
+	return err
+}
+
+func (r *passwordResetRepository) InvalidateUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("password_resets")
+	filter := bson.M{"user_id": userID, "used_at": nil}
+	_, err := collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"used_at": time.Now()}})
+	return err
+}
+
+func (r *passwordResetRepository) DeleteByUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("password_resets")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrAccessTokenNotFound is returned when a personal access token does not
+// exist or belongs to another user.
+var ErrAccessTokenNotFound = apperrors.NotFound("access_token_not_found", "access token not found")
+
+// PersonalAccessTokenRepository defines data access methods for personal access tokens.
+type PersonalAccessTokenRepository interface {
+	Create(token *models.PersonalAccessToken) error
+	Delete(id primitive.ObjectID, userID primitive.ObjectID) error
+	GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.PersonalAccessToken, error)
+	GetTokens(userID primitive.ObjectID) ([]models.PersonalAccessToken, error)
+	FindByHash(hash string) (*models.PersonalAccessToken, error)
+	// TouchLastUsed sets last_used_at unless it was set after notBefore.
+	TouchLastUsed(id primitive.ObjectID, at, notBefore time.Time) error
+	// DeleteByUser deletes every token of the user, for account deletion.
+	DeleteByUser(userID primitive.ObjectID) error
+}
+
+type personalAccessTokenRepository struct{}
+
+// NewPersonalAccessTokenRepository returns a new instance of PersonalAccessTokenRepository.
+func NewPersonalAccessTokenRepository() PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{}
+}
+
+func (r *personalAccessTokenRepository) Create(token *models.PersonalAccessToken) error {
+	collection := config.DB.Collection("personal_access_tokens")
+	token.CreatedAt = time.Now()
+	res, err := collection.InsertOne(context.Background(), token)
+	if err != nil {
+		return translateError(err, ErrAccessTokenNotFound)
+	}
+	token.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *personalAccessTokenRepository) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
+	collection := config.DB.Collection("personal_access_tokens")
+	res, err := collection.DeleteOne(context.Background(), bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+func (r *personalAccessTokenRepository) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.PersonalAccessToken, error) {
+	collection := config.DB.Collection("personal_access_tokens")
+	var token models.PersonalAccessToken
+	err := collection.FindOne(context.Background(), bson.M{"_id": id, "user_id": userID}).Decode(&token)
+	if err != nil {
+		return nil, translateError(err, ErrAccessTokenNotFound)
+	}
+	return &token, nil
+}
+
+func (r *personalAccessTokenRepository) GetTokens(userID primitive.ObjectID) ([]models.PersonalAccessToken, error) {
+	collection := config.DB.Collection("personal_access_tokens")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	tokens := []models.PersonalAccessToken{}
+	if err := cursor.All(context.Background(), &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *personalAccessTokenRepository) FindByHash(hash string) (*models.PersonalAccessToken, error) {
+	collection := config.DB.Collection("personal_access_tokens")
+	var token models.PersonalAccessToken
+	err := collection.FindOne(context.Background(), bson.M{"token_hash": hash}).Decode(&token)
+	if err != nil {
+		return nil, translateError(err, ErrAccessTokenNotFound)
+	}
+	return &token, nil
+}
+
+func (r *personalAccessTokenRepository) TouchLastUsed(id primitive.ObjectID, at, notBefore time.Time) error {
+	collection := config.DB.Collection("personal_access_tokens")
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"last_used_at": bson.M{"$exists": false}},
+			bson.M{"last_used_at": bson.M{"$lt": notBefore}},
+		},
+	}
+	_, err := collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
+
+func (r *personalAccessTokenRepository) DeleteByUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("personal_access_tokens")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ProjectRepository defines data access methods for Project.
+type ProjectRepository interface {
+	Create(project *models.Project) error
+	Update(project *models.Project) error
+	Delete(id primitive.ObjectID, userID primitive.ObjectID) error
+	GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Project, error)
+	GetProjects(userID primitive.ObjectID) ([]models.Project, error)
+	FindInbox(userID primitive.ObjectID) (*models.Project, error)
+	DeleteByUser(userID primitive.ObjectID) error
+}
+
+type projectRepository struct{}
+
+// NewProjectRepository returns a new instance of ProjectRepository.
+func NewProjectRepository() ProjectRepository {
+	return &projectRepository{}
+}
+
+func (r *projectRepository) Create(project *models.Project) error {
+	collection := config.DB.Collection("projects")
+	project.CreatedAt = time.Now()
+	project.UpdatedAt = time.Now()
+	res, err := collection.InsertOne(context.Background(), project)
+	if err != nil {
+		return translateError(err, ErrProjectNotFound)
+	}
+	project.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *projectRepository) Update(project *models.Project) error {
+	collection := config.DB.Collection("projects")
+	project.UpdatedAt = time.Now()
+	filter := bson.M{"_id": project.ID, "user_id": project.UserID}
+	update := bson.M{"$set": bson.M{
+		"name":       project.Name,
+		"updated_at": project.UpdatedAt,
+	}}
+	res, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return translateError(err, ErrProjectNotFound)
+	}
+	if res.MatchedCount == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func (r *projectRepository) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
+	collection := config.DB.Collection("projects")
+	filter := bson.M{"_id": id, "user_id": userID}
+	res, err := collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func (r *projectRepository) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Project, error) {
+	collection := config.DB.Collection("projects")
+	var project models.Project
+	err := collection.FindOne(context.Background(), bson.M{"_id": id, "user_id": userID}).Decode(&project)
+	if err != nil {
+		return nil, translateError(err, ErrProjectNotFound)
+	}
+	return &project, nil
+}
+
+func (r *projectRepository) GetProjects(userID primitive.ObjectID) ([]models.Project, error) {
+	collection := config.DB.Collection("projects")
+	// The inbox comes first, followed by the other projects in creation order.
+	opts := options.Find().SetSort(bson.D{{Key: "inbox", Value: -1}, {Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	projects := []models.Project{}
+	if err := cursor.All(context.Background(), &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (r *projectRepository) FindInbox(userID primitive.ObjectID) (*models.Project, error) {
+	collection := config.DB.Collection("projects")
+	var project models.Project
+	err := collection.FindOne(context.Background(), bson.M{"user_id": userID, "inbox": true}).Decode(&project)
+	if err != nil {
+		return nil, translateError(err, ErrProjectNotFound)
+	}
+	return &project, nil
+}
+
+func (r *projectRepository) DeleteByUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("projects")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrRefreshTokenNotFound is returned when no refresh token has the given hash.
+var ErrRefreshTokenNotFound = apperrors.NotFound("refresh_token_not_found", "refresh token not found")
+
+// RefreshTokenRepository defines data access methods for refresh tokens.
+type RefreshTokenRepository interface {
+	Create(token *models.RefreshToken) error
+	FindByHash(hash string) (*models.RefreshToken, error)
+	// Rotate marks the token as exchanged and reports whether it was still
+	// active, so that two concurrent refreshes cannot both succeed.
+	Rotate(id primitive.ObjectID) (bool, error)
+	// RevokeFamily revokes every active token of the family.
+	RevokeFamily(familyID primitive.ObjectID) error
+	// RevokeUser revokes every active token of the user.
+	RevokeUser(userID primitive.ObjectID) error
+	// DeleteByUser removes every token of the user when the account is deleted.
+	DeleteByUser(userID primitive.ObjectID) error
+}
+
+type refreshTokenRepository struct{}
+
+// NewRefreshTokenRepository returns a new instance of RefreshTokenRepository.
+func NewRefreshTokenRepository() RefreshTokenRepository {
+	return &refreshTokenRepository{}
+}
+
+func (r *refreshTokenRepository) Create(token *models.RefreshToken) error {
+	collection := config.DB.Collection("refresh_tokens")
+	token.CreatedAt = time.Now()
+	res, err := collection.InsertOne(context.Background(), token)
+	if err != nil {
+		return translateError(err, ErrRefreshTokenNotFound)
+	}
+	token.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByHash(hash string) (*models.RefreshToken, error) {
+	collection := config.DB.Collection("refresh_tokens")
+	var token models.RefreshToken
+	err := collection.FindOne(context.Background(), bson.M{"token_hash": hash}).Decode(&token)
+	if err != nil {
+		return nil, translateError(err, ErrRefreshTokenNotFound)
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) Rotate(id primitive.ObjectID) (bool, error) {
+	collection := config.DB.Collection("refresh_tokens")
+	filter := bson.M{"_id": id, "rotated_at": nil, "revoked_at": nil}
+	res, err := collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"rotated_at": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID primitive.ObjectID) error {
+	collection := config.DB.Collection("refresh_tokens")
+	filter := bson.M{"family_id": familyID, "revoked_at": nil}
+	_, err := collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("refresh_tokens")
+	filter := bson.M{"user_id": userID, "revoked_at": nil}
+	_, err := collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	return err
+}
+
+func (r *refreshTokenRepository) DeleteByUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("refresh_tokens")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
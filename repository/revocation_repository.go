@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RevocationRepository stores revoked access tokens. Entries are removed by
+// TTL indexes once the tokens they cover have expired.
+type RevocationRepository interface {
+	RevokeToken(token *models.RevokedToken) error
+	IsTokenRevoked(jti string) (bool, error)
+	// RevokeUserTokens revokes the user's tokens issued before the given time.
+	// An existing revocation is only ever extended.
+	RevokeUserTokens(revocation *models.UserTokenRevocation) error
+	// UserTokensRevokedBefore returns the user's revocation cutoff, or the
+	// zero time if none is in effect.
+	UserTokensRevokedBefore(userID primitive.ObjectID) (time.Time, error)
+	// DeleteByUser removes every revocation of the user when the account is deleted.
+	DeleteByUser(userID primitive.ObjectID) error
+}
+
+type revocationRepository struct{}
+
+// NewRevocationRepository returns a new instance of RevocationRepository.
+func NewRevocationRepository() RevocationRepository {
+	return &revocationRepository{}
+}
+
+func (r *revocationRepository) RevokeToken(token *models.RevokedToken) error {
+	collection := config.DB.Collection("revoked_tokens")
+	_, err := collection.ReplaceOne(context.Background(), bson.M{"_id": token.ID}, token, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *revocationRepository) IsTokenRevoked(jti string) (bool, error) {
+	collection := config.DB.Collection("revoked_tokens")
+	err := collection.FindOne(context.Background(), bson.M{"_id": jti}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *revocationRepository) RevokeUserTokens(revocation *models.UserTokenRevocation) error {
+	collection := config.DB.Collection("user_token_revocations")
+	update := bson.M{"$max": bson.M{"revoked_before": revocation.RevokedBefore, "expires_at": revocation.ExpiresAt}}
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": revocation.UserID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *revocationRepository) UserTokensRevokedBefore(userID primitive.ObjectID) (time.Time, error) {
+	collection := config.DB.Collection("user_token_revocations")
+	var revocation models.UserTokenRevocation
+	err := collection.FindOne(context.Background(), bson.M{"_id": userID}).Decode(&revocation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return revocation.RevokedBefore, nil
+}
+
+func (r *revocationRepository) DeleteByUser(userID primitive.ObjectID) error {
+	if _, err := config.DB.Collection("revoked_tokens").DeleteMany(context.Background(), bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+	_, err := config.DB.Collection("user_token_revocations").DeleteOne(context.Background(), bson.M{"_id": userID})
+	return err
+}
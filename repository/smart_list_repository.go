@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"todo-list-api/config"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SmartListRepository defines data access methods for SmartList.
+type SmartListRepository interface {
+	Create(list *models.SmartList) error
+	Update(list *models.SmartList) error
+	Delete(id primitive.ObjectID, userID primitive.ObjectID) error
+	GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.SmartList, error)
+	GetSmartLists(userID primitive.ObjectID) ([]models.SmartList, error)
+	RenameLabel(userID primitive.ObjectID, oldName, newName string) error
+	RemoveLabel(userID primitive.ObjectID, name string) error
+	ReplaceProject(userID primitive.ObjectID, oldID primitive.ObjectID, newID *primitive.ObjectID) error
+	SetQuery(id primitive.ObjectID, userID primitive.ObjectID, oldQuery, newQuery string) error
+	DeleteByUser(userID primitive.ObjectID) error
+}
+
+type smartListRepository struct{}
+
+// NewSmartListRepository returns a new instance of SmartListRepository.
+func NewSmartListRepository() SmartListRepository {
+	return &smartListRepository{}
+}
+
+func (r *smartListRepository) Create(list *models.SmartList) error {
+	collection := config.DB.Collection("smart_lists")
+	list.CreatedAt = time.Now()
+	list.UpdatedAt = time.Now()
+	res, err := collection.InsertOne(context.Background(), list)
+	if err != nil {
+		return translateError(err, ErrSmartListNotFound)
+	}
+	list.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *smartListRepository) Update(list *models.SmartList) error {
+	collection := config.DB.Collection("smart_lists")
+	list.UpdatedAt = time.Now()
+	filter := bson.M{"_id": list.ID, "user_id": list.UserID}
+	update := bson.M{"$set": bson.M{
+		"name":       list.Name,
+		"filter":     list.Filter,
+		"updated_at": list.UpdatedAt,
+	}}
+	res, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return translateError(err, ErrSmartListNotFound)
+	}
+	if res.MatchedCount == 0 {
+		return ErrSmartListNotFound
+	}
+	return nil
+}
+
+func (r *smartListRepository) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
+	collection := config.DB.Collection("smart_lists")
+	res, err := collection.DeleteOne(context.Background(), bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrSmartListNotFound
+	}
+	return nil
+}
+
+func (r *smartListRepository) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.SmartList, error) {
+	collection := config.DB.Collection("smart_lists")
+	var list models.SmartList
+	err := collection.FindOne(context.Background(), bson.M{"_id": id, "user_id": userID}).Decode(&list)
+	if err != nil {
+		return nil, translateError(err, ErrSmartListNotFound)
+	}
+	return &list, nil
+}
+
+func (r *smartListRepository) GetSmartLists(userID primitive.ObjectID) ([]models.SmartList, error) {
+	collection := config.DB.Collection("smart_lists")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	lists := []models.SmartList{}
+	if err := cursor.All(context.Background(), &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// RenameLabel renames a label in the filter of every smart list of the user that uses it.
+func (r *smartListRepository) RenameLabel(userID primitive.ObjectID, oldName, newName string) error {
+	collection := config.DB.Collection("smart_lists")
+	filter := bson.M{"user_id": userID, "filter.labels": oldName}
+	update := bson.M{"$set": bson.M{"filter.labels.$": newName}}
+	_, err := collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// RemoveLabel removes a label name from the filter of every smart list of the user that uses it.
+func (r *smartListRepository) RemoveLabel(userID primitive.ObjectID, name string) error {
+	collection := config.DB.Collection("smart_lists")
+	filter := bson.M{"user_id": userID, "filter.labels": name}
+	update := bson.M{"$pull": bson.M{"filter.labels": name}}
+	_, err := collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// ReplaceProject points every smart list of the user that filters on the
+// project oldID at newID instead, or drops the project filter when newID is nil.
+func (r *smartListRepository) ReplaceProject(userID primitive.ObjectID, oldID primitive.ObjectID, newID *primitive.ObjectID) error {
+	collection := config.DB.Collection("smart_lists")
+	filter := bson.M{"user_id": userID, "filter.project_id": oldID}
+	update := bson.M{"$unset": bson.M{"filter.project_id": ""}}
+	if newID != nil {
+		update = bson.M{"$set": bson.M{"filter.project_id": *newID}}
+	}
+	_, err := collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// SetQuery replaces the filter expression of a smart list, unless its
+// expression changed since it was read as oldQuery.
+func (r *smartListRepository) SetQuery(id primitive.ObjectID, userID primitive.ObjectID, oldQuery, newQuery string) error {
+	collection := config.DB.Collection("smart_lists")
+	filter := bson.M{"_id": id, "user_id": userID, "filter.query": oldQuery}
+	update := bson.M{"$unset": bson.M{"filter.query": ""}}
+	if newQuery != "" {
+		update = bson.M{"$set": bson.M{"filter.query": newQuery}}
+	}
+	_, err := collection.UpdateOne(context.Background(), filter, update)
+	return err
+}
+
+func (r *smartListRepository) DeleteByUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("smart_lists")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 	"todo-list-api/config"
 	"todo-list-api/models"
+	querylang "todo-list-api/query"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,10 +18,27 @@ import (
 // TodoRepository defines data access methods for Todo items.
 type TodoRepository interface {
 	Create(todo *models.Todo) error
-	Update(todo *models.Todo) error
+	Update(todo *models.Todo, unmodifiedSince time.Time) error
+	Patch(todo *models.Todo, fields []string, unmodifiedSince time.Time) error
 	Delete(id primitive.ObjectID, userID primitive.ObjectID) error
-	GetTodos(userID primitive.ObjectID, page, limit int64) ([]models.Todo, int64, error)
-	GetByID(id primitive.ObjectID) (*models.Todo, error)
+	GetTodos(userID primitive.ObjectID, filter models.TodoFilter, page, limit int64) ([]models.Todo, error)
+	GetTodosAfter(userID primitive.ObjectID, filter models.TodoFilter, cursor *models.TodoCursor, limit int64) ([]models.Todo, *models.TodoCursor, *models.TodoCursor, error)
+	CountTodos(userID primitive.ObjectID, filter models.TodoFilter) (int64, error)
+	CountByStatus(userID primitive.ObjectID) (map[string]int64, error)
+	GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Todo, error)
+	Search(userID primitive.ObjectID, search models.TodoSearch, page, limit int64) ([]models.TodoSearchResult, int64, error)
+	SetStatus(id primitive.ObjectID, userID primitive.ObjectID, status string, completedAt *time.Time) (*models.Todo, error)
+	RenameLabel(userID primitive.ObjectID, oldName, newName string) error
+	RemoveLabel(userID primitive.ObjectID, name string) error
+	MoveToProject(userID primitive.ObjectID, fromProjectID, toProjectID primitive.ObjectID) error
+	DeleteByProject(userID primitive.ObjectID, projectID primitive.ObjectID) error
+	SetChecklist(id primitive.ObjectID, userID primitive.ObjectID, items []models.ChecklistItem) (*models.Todo, error)
+	SetNextOccurrence(id primitive.ObjectID, userID primitive.ObjectID, nextID primitive.ObjectID) error
+	FindDueReminders(now time.Time, limit int64) ([]models.Todo, error)
+	ClaimReminder(id primitive.ObjectID, reminderID primitive.ObjectID, sentAt time.Time) (bool, error)
+	RetryReminder(id primitive.ObjectID, reminderID primitive.ObjectID, retryAt time.Time) error
+	FailReminder(id primitive.ObjectID, reminderID primitive.ObjectID, failedAt time.Time) error
+	DeleteByUser(userID primitive.ObjectID) error
 }
 
 type todoRepository struct{}
@@ -32,27 +52,112 @@ func (r *todoRepository) Create(todo *models.Todo) error {
 	collection := config.DB.Collection("todos")
 	todo.CreatedAt = time.Now()
 	todo.UpdatedAt = time.Now()
-	_, err := collection.InsertOne(context.Background(), todo)
-	return err
+	res, err := collection.InsertOne(context.Background(), todo)
+	if err != nil {
+		return err
+	}
+	todo.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
 }
 
-func (r *todoRepository) Update(todo *models.Todo) error {
-	collection := config.DB.Collection("todos")
+// Update replaces every stored client-controlled field of todo. Like Patch,
+// it only writes the todo if it was last updated at unmodifiedSince.
+func (r *todoRepository) Update(todo *models.Todo, unmodifiedSince time.Time) error {
 	todo.UpdatedAt = time.Now()
-	filter := bson.M{"_id": todo.ID, "user_id": todo.UserID}
-	update := bson.M{"$set": bson.M{
-		"title":       todo.Title,
-		"description": todo.Description,
-		"updated_at":  todo.UpdatedAt,
-	}}
+	set, unset := todoUpdateFields(todo)
+	return r.update(todo, unmodifiedSince, set, unset)
+}
+
+// Patch writes only the given top-level fields of todo, leaving every other
+// stored field untouched. Fields are named by their BSON keys. The todo is
+// only written if it was last updated at unmodifiedSince; otherwise
+// ErrTodoModified is returned so the caller can patch the current version.
+func (r *todoRepository) Patch(todo *models.Todo, fields []string, unmodifiedSince time.Time) error {
+	todo.UpdatedAt = time.Now()
+	allSet, allUnset := todoUpdateFields(todo)
+	set := bson.M{"updated_at": todo.UpdatedAt}
+	unset := bson.M{}
+	for _, field := range fields {
+		if v, ok := allSet[field]; ok {
+			set[field] = v
+		}
+		if v, ok := allUnset[field]; ok {
+			unset[field] = v
+		}
+	}
+	return r.update(todo, unmodifiedSince, set, unset)
+}
+
+// update applies set and unset to the user's todo if it was last updated at
+// unmodifiedSince, returning ErrTodoModified if it was updated since.
+func (r *todoRepository) update(todo *models.Todo, unmodifiedSince time.Time, set, unset bson.M) error {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"_id": todo.ID, "user_id": todo.UserID, "updated_at": unmodifiedSince}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
 	res, err := collection.UpdateOne(context.Background(), filter, update)
 	if err != nil {
 		return err
 	}
-	if res.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+	if res.MatchedCount > 0 {
+		return nil
 	}
-	return nil
+	// Tell a concurrent change apart from a todo that no longer exists.
+	n, err := collection.CountDocuments(context.Background(), bson.M{"_id": todo.ID, "user_id": todo.UserID})
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrTodoModified
+	}
+	return ErrTodoNotFound
+}
+
+// todoUpdateFields builds the $set and $unset documents that store every
+// client-editable field of todo; empty optional fields are unset.
+func todoUpdateFields(todo *models.Todo) (bson.M, bson.M) {
+	set := bson.M{
+		"title":       todo.Title,
+		"description": todo.Description,
+		"status":      todo.Status,
+		"priority":    todo.Priority,
+		"project_id":  todo.ProjectID,
+		"updated_at":  todo.UpdatedAt,
+	}
+	unset := bson.M{}
+	setOrUnset(set, unset, "completed_at", todo.CompletedAt)
+	setOrUnset(set, unset, "start_at", todo.StartAt)
+	setOrUnset(set, unset, "due_at", todo.DueAt)
+	if todo.Timezone != "" {
+		set["timezone"] = todo.Timezone
+	} else {
+		unset["timezone"] = ""
+	}
+	if len(todo.Labels) > 0 {
+		set["labels"] = todo.Labels
+	} else {
+		unset["labels"] = ""
+	}
+	if len(todo.Checklist) > 0 {
+		set["checklist"] = todo.Checklist
+	} else {
+		unset["checklist"] = ""
+	}
+	if len(todo.Reminders) > 0 {
+		set["reminders"] = todo.Reminders
+	} else {
+		unset["reminders"] = ""
+	}
+	if todo.RRule != "" {
+		set["rrule"] = todo.RRule
+		set["repeat_from"] = todo.RepeatFrom
+	} else {
+		unset["rrule"] = ""
+		unset["repeat_from"] = ""
+	}
+	return set, unset
 }
 
 func (r *todoRepository) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
@@ -63,42 +168,522 @@ func (r *todoRepository) Delete(id primitive.ObjectID, userID primitive.ObjectID
 		return err
 	}
 	if res.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+		return ErrTodoNotFound
 	}
 	return nil
 }
 
-func (r *todoRepository) GetTodos(userID primitive.ObjectID, page, limit int64) ([]models.Todo, int64, error) {
+func (r *todoRepository) GetTodos(userID primitive.ObjectID, filter models.TodoFilter, page, limit int64) ([]models.Todo, error) {
 	collection := config.DB.Collection("todos")
-	filter := bson.M{"user_id": userID}
+	query, err := buildTodoQuery(userID, filter)
+	if err != nil {
+		return nil, err
+	}
 
 	opts := options.Find()
+	opts.SetSort(todoSortOrder(filter.Sort))
 	opts.SetSkip((page - 1) * limit)
 	opts.SetLimit(limit)
 
-	cursor, err := collection.Find(context.Background(), filter, opts)
+	cursor, err := collection.Find(context.Background(), query, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	var todos []models.Todo
 	if err := cursor.All(context.Background(), &todos); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// GetTodosAfter returns up to limit todos following the cursor in the filter's
+// sort order, or preceding it when cursor.Before is set; a nil cursor starts at
+// the beginning. Unlike skip-based paging, this stays fast on deep pages and does
+// not shift when todos are added. It also returns cursors for the next and
+// previous pages, which are nil at either end of the listing.
+func (r *todoRepository) GetTodosAfter(userID primitive.ObjectID, filter models.TodoFilter, after *models.TodoCursor, limit int64) ([]models.Todo, *models.TodoCursor, *models.TodoCursor, error) {
+	collection := config.DB.Collection("todos")
+	query, err := buildTodoQuery(userID, filter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sort := filter.Sort
+	if sort.Field == "" {
+		sort.Field = models.SortByCreatedAt
+	}
+	backward := after != nil && after.Before
+	if after != nil {
+		query = bson.M{"$and": bson.A{query, keysetCondition(sort, after.Value, after.ID, backward)}}
+	}
+	scan := sort
+	if backward {
+		scan.Descending = !scan.Descending
+	}
+
+	// Fetch one extra todo to learn whether another page follows.
+	opts := options.Find().SetSort(todoSortOrder(scan)).SetLimit(limit + 1)
+	cursor, err := collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer cursor.Close(context.Background())
+	var todos []models.Todo
+	var keys []interface{}
+	for cursor.Next(context.Background()) {
+		var todo models.Todo
+		if err := cursor.Decode(&todo); err != nil {
+			return nil, nil, nil, err
+		}
+		// Read the sort key from the raw document so missing fields stay nil.
+		var key interface{}
+		if value, err := cursor.Current.LookupErr(sort.Field); err == nil {
+			if err := value.Unmarshal(&key); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		todos = append(todos, todo)
+		keys = append(keys, key)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	more := int64(len(todos)) > limit
+	if more {
+		todos, keys = todos[:limit], keys[:limit]
+	}
+	if backward {
+		for i, j := 0, len(todos)-1; i < j; i, j = i+1, j-1 {
+			todos[i], todos[j] = todos[j], todos[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+	if len(todos) == 0 {
+		return todos, nil, nil, nil
+	}
+	var next, prev *models.TodoCursor
+	last, first := len(todos)-1, 0
+	if more || backward {
+		next = &models.TodoCursor{Sort: sort, Value: keys[last], ID: todos[last].ID}
+	}
+	if (backward && more) || (!backward && after != nil) {
+		prev = &models.TodoCursor{Sort: sort, Value: keys[first], ID: todos[first].ID, Before: true}
+	}
+	return todos, next, prev, nil
+}
+
+// CountTodos returns the number of the user's todos matching the filter.
+func (r *todoRepository) CountTodos(userID primitive.ObjectID, filter models.TodoFilter) (int64, error) {
+	query, err := buildTodoQuery(userID, filter)
+	if err != nil {
+		return 0, err
+	}
+	return config.DB.Collection("todos").CountDocuments(context.Background(), query)
+}
+
+// CountByStatus returns the number of the user's todos in each status that
+// has any.
+func (r *todoRepository) CountByStatus(userID primitive.ObjectID) (map[string]int64, error) {
+	collection := config.DB.Collection("todos")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(context.Background(), &groups); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(groups))
+	for _, g := range groups {
+		counts[g.Status] = g.Count
+	}
+	return counts, nil
+}
+
+// Search returns a page of the user's todos matching a full-text query.
+// Results are ranked by relevance unless the filter asks for another order.
+func (r *todoRepository) Search(userID primitive.ObjectID, search models.TodoSearch, page, limit int64) ([]models.TodoSearchResult, int64, error) {
+	collection := config.DB.Collection("todos")
+	query, err := buildTodoQuery(userID, search.Filter)
+	if err != nil {
 		return nil, 0, err
 	}
+	text, conditions := buildSearchConditions(search.Terms)
+	if text != "" {
+		query["$text"] = bson.M{"$search": text}
+	}
+	if len(conditions) > 0 {
+		if expr, ok := query["$and"].(bson.A); ok {
+			conditions = append(expr, conditions...)
+		}
+		query["$and"] = conditions
+	}
 
-	// Get total count
-	total, err := collection.CountDocuments(context.Background(), filter)
+	opts := options.Find()
+	if text != "" {
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+	}
+	if text != "" && search.Filter.Sort.Field == "" {
+		opts.SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}})
+	} else {
+		opts.SetSort(todoSortOrder(search.Filter.Sort))
+	}
+	opts.SetSkip((page - 1) * limit)
+	opts.SetLimit(limit)
+
+	cursor, err := collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	var results []models.TodoSearchResult
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, 0, err
+	}
+	total, err := collection.CountDocuments(context.Background(), query)
 	if err != nil {
 		return nil, 0, err
 	}
-	return todos, total, nil
+	return results, total, nil
 }
 
-func (r *todoRepository) GetByID(id primitive.ObjectID) (*models.Todo, error) {
+func (r *todoRepository) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Todo, error) {
 	collection := config.DB.Collection("todos")
 	var todo models.Todo
-	err := collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&todo)
+	err := collection.FindOne(context.Background(), bson.M{"_id": id, "user_id": userID}).Decode(&todo)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, ErrTodoNotFound)
+	}
+	return &todo, nil
+}
+
+func (r *todoRepository) SetStatus(id primitive.ObjectID, userID primitive.ObjectID, status string, completedAt *time.Time) (*models.Todo, error) {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"_id": id, "user_id": userID}
+	set := bson.M{
+		"status":     status,
+		"updated_at": time.Now(),
+	}
+	update := bson.M{"$set": set}
+	if completedAt != nil {
+		set["completed_at"] = completedAt
+	} else {
+		update["$unset"] = bson.M{"completed_at": ""}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var todo models.Todo
+	if err := collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&todo); err != nil {
+		return nil, translateError(err, ErrTodoNotFound)
 	}
 	return &todo, nil
 }
+
+// SetChecklist replaces the todo's checklist and returns the updated todo.
+func (r *todoRepository) SetChecklist(id primitive.ObjectID, userID primitive.ObjectID, items []models.ChecklistItem) (*models.Todo, error) {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"_id": id, "user_id": userID}
+	update := bson.M{"$set": bson.M{"checklist": items, "updated_at": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var todo models.Todo
+	if err := collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&todo); err != nil {
+		return nil, translateError(err, ErrTodoNotFound)
+	}
+	return &todo, nil
+}
+
+// SetNextOccurrence links a completed recurring todo to the occurrence generated from it.
+func (r *todoRepository) SetNextOccurrence(id primitive.ObjectID, userID primitive.ObjectID, nextID primitive.ObjectID) error {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"_id": id, "user_id": userID}
+	update := bson.M{"$set": bson.M{"next_occurrence_id": nextID}}
+	res, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrTodoNotFound
+	}
+	return nil
+}
+
+// FindDueReminders returns open todos with at least one unsent reminder whose
+// fire time, and retry time after a failed delivery, has passed. Todos with
+// the earliest reminders come first, so a full batch does not hold back
+// older reminders.
+func (r *todoRepository) FindDueReminders(now time.Time, limit int64) ([]models.Todo, error) {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{
+		"reminders": bson.M{"$elemMatch": bson.M{
+			"fire_at":   bson.M{"$lte": now},
+			"sent_at":   nil,
+			"failed_at": nil,
+			"$or":       bson.A{bson.M{"retry_at": nil}, bson.M{"retry_at": bson.M{"$lte": now}}},
+		}},
+		"status": bson.M{"$nin": bson.A{models.TodoStatusDone, models.TodoStatusCancelled}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "reminders.fire_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var todos []models.Todo
+	if err := cursor.All(context.Background(), &todos); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// ClaimReminder marks a reminder as sent if no one else has done so yet and
+// reports whether the caller won the claim.
+func (r *todoRepository) ClaimReminder(id primitive.ObjectID, reminderID primitive.ObjectID, sentAt time.Time) (bool, error) {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"reminders.$[r].sent_at": sentAt}}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"r._id": reminderID, "r.sent_at": nil, "r.failed_at": nil}},
+	})
+	res, err := collection.UpdateOne(context.Background(), filter, update, opts)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// RetryReminder gives back a claimed reminder after a failed delivery,
+// counting the attempt and postponing the next one until retryAt.
+func (r *todoRepository) RetryReminder(id primitive.ObjectID, reminderID primitive.ObjectID, retryAt time.Time) error {
+	update := bson.M{
+		"$unset": bson.M{"reminders.$[r].sent_at": ""},
+		"$set":   bson.M{"reminders.$[r].retry_at": retryAt},
+		"$inc":   bson.M{"reminders.$[r].attempts": 1},
+	}
+	return r.updateReminder(id, reminderID, update)
+}
+
+// FailReminder gives up on a reminder so the scheduler no longer picks it up.
+func (r *todoRepository) FailReminder(id primitive.ObjectID, reminderID primitive.ObjectID, failedAt time.Time) error {
+	update := bson.M{
+		"$unset": bson.M{"reminders.$[r].sent_at": "", "reminders.$[r].retry_at": ""},
+		"$set":   bson.M{"reminders.$[r].failed_at": failedAt},
+		"$inc":   bson.M{"reminders.$[r].attempts": 1},
+	}
+	return r.updateReminder(id, reminderID, update)
+}
+
+func (r *todoRepository) updateReminder(id primitive.ObjectID, reminderID primitive.ObjectID, update bson.M) error {
+	collection := config.DB.Collection("todos")
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"r._id": reminderID}},
+	})
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": id}, update, opts)
+	return err
+}
+
+// RenameLabel replaces a label name on every todo of the user that carries it.
+func (r *todoRepository) RenameLabel(userID primitive.ObjectID, oldName, newName string) error {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"user_id": userID, "labels": oldName}
+	update := bson.M{"$set": bson.M{"labels.$": newName}}
+	_, err := collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// RemoveLabel removes a label name from every todo of the user that carries it.
+func (r *todoRepository) RemoveLabel(userID primitive.ObjectID, name string) error {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"user_id": userID, "labels": name}
+	update := bson.M{"$pull": bson.M{"labels": name}}
+	_, err := collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// MoveToProject moves every todo of the user in one project to another.
+func (r *todoRepository) MoveToProject(userID primitive.ObjectID, fromProjectID, toProjectID primitive.ObjectID) error {
+	collection := config.DB.Collection("todos")
+	filter := bson.M{"user_id": userID, "project_id": fromProjectID}
+	update := bson.M{"$set": bson.M{"project_id": toProjectID, "updated_at": time.Now()}}
+	_, err := collection.UpdateMany(context.Background(), filter, update)
+	return err
+}
+
+// DeleteByProject deletes every todo of the user in the given project.
+func (r *todoRepository) DeleteByProject(userID primitive.ObjectID, projectID primitive.ObjectID) error {
+	collection := config.DB.Collection("todos")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID, "project_id": projectID})
+	return err
+}
+
+// buildTodoQuery translates a TodoFilter into a MongoDB filter scoped to the user.
+// The filter expression, if any, is compiled by the query package and must
+// match as well.
+func buildTodoQuery(userID primitive.ObjectID, filter models.TodoFilter) (bson.M, error) {
+	query := bson.M{"user_id": userID}
+	if filter.Query != "" {
+		loc, err := time.LoadLocation(filter.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := querylang.Compile(filter.Query, querylang.Options{Location: loc})
+		if err != nil {
+			return nil, err
+		}
+		query["$and"] = bson.A{expr}
+	}
+
+	status := bson.M{}
+	if len(filter.Statuses) > 0 {
+		statuses := bson.A{}
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, status)
+			// Todos created before statuses existed have no status field; treat them as open.
+			if status == models.TodoStatusOpen {
+				statuses = append(statuses, nil)
+			}
+		}
+		status["$in"] = statuses
+	}
+	if filter.Pending {
+		status["$nin"] = bson.A{models.TodoStatusDone, models.TodoStatusCancelled}
+	}
+	if len(status) > 0 {
+		query["status"] = status
+	}
+
+	if filter.NoDueDate {
+		query["due_at"] = nil
+	} else {
+		due := bson.M{}
+		if filter.DueAfter != nil {
+			due["$gte"] = *filter.DueAfter
+		}
+		if filter.DueBefore != nil {
+			due["$lt"] = *filter.DueBefore
+		}
+		if len(due) > 0 {
+			query["due_at"] = due
+		}
+	}
+
+	if len(filter.Labels) > 0 {
+		operator := "$in"
+		if filter.LabelMatch == models.LabelMatchAll {
+			operator = "$all"
+		}
+		query["labels"] = bson.M{operator: filter.Labels}
+	}
+	if !filter.ProjectID.IsZero() {
+		query["project_id"] = filter.ProjectID
+	}
+	return query, nil
+}
+
+// buildSearchConditions turns search terms into a $text search string and
+// additional conditions. The text index cannot be restricted to one field, so
+// field-prefixed terms become case-insensitive regex matches on that field.
+// A $text search made only of negated terms matches nothing, so in that case
+// negated terms are also matched with regexes.
+func buildSearchConditions(terms []models.SearchTerm) (string, bson.A) {
+	positive := false
+	for _, term := range terms {
+		if term.Field == "" && !term.Negated {
+			positive = true
+		}
+	}
+	var words []string
+	conditions := bson.A{}
+	for _, term := range terms {
+		switch {
+		case term.Field == "" && positive:
+			word := term.Text
+			if term.Phrase {
+				word = `"` + word + `"`
+			}
+			if term.Negated {
+				word = "-" + word
+			}
+			words = append(words, word)
+		case term.Field == "":
+			pattern := searchRegex(term.Text)
+			conditions = append(conditions, bson.M{"$nor": bson.A{
+				bson.M{models.SearchFieldTitle: pattern},
+				bson.M{models.SearchFieldDescription: pattern},
+			}})
+		case term.Negated:
+			conditions = append(conditions, bson.M{term.Field: bson.M{"$not": searchRegex(term.Text)}})
+		default:
+			conditions = append(conditions, bson.M{term.Field: searchRegex(term.Text)})
+		}
+	}
+	return strings.Join(words, " "), conditions
+}
+
+// searchRegex matches text literally and case-insensitively.
+func searchRegex(text string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(text), Options: "i"}
+}
+
+// keysetCondition matches the todos after the position (value, id) in the
+// given sort order, or before it when backward is set. MongoDB sorts missing
+// and null keys before every other value, and range operators never match
+// them, so null keys need their own clauses.
+func keysetCondition(sort models.TodoSort, value interface{}, id primitive.ObjectID, backward bool) bson.M {
+	field := sort.Field
+	ascending := sort.Descending == backward
+	if ascending {
+		if value == nil {
+			return bson.M{"$or": bson.A{
+				bson.M{field: nil, "_id": bson.M{"$gt": id}},
+				bson.M{field: bson.M{"$ne": nil}},
+			}}
+		}
+		return bson.M{"$or": bson.A{
+			bson.M{field: bson.M{"$gt": value}},
+			bson.M{field: value, "_id": bson.M{"$gt": id}},
+		}}
+	}
+	if value == nil {
+		return bson.M{field: nil, "_id": bson.M{"$lt": id}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{"$lt": value}},
+		bson.M{field: value, "_id": bson.M{"$lt": id}},
+		bson.M{field: nil},
+	}}
+}
+
+// todoSortOrder builds the sort document for a TodoSort. The _id is always
+// appended as a tie-breaker so pages stay stable between requests.
+func todoSortOrder(sort models.TodoSort) bson.D {
+	field := sort.Field
+	if field == "" {
+		field = models.SortByCreatedAt
+	}
+	direction := 1
+	if sort.Descending {
+		direction = -1
+	}
+	return bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// setOrUnset adds an optional time field to the $set document, or to the
+// $unset document when it has been cleared.
+func setOrUnset(set, unset bson.M, key string, value *time.Time) {
+	if value != nil {
+		set[key] = *value
+	} else {
+		unset[key] = ""
+	}
+}
+
+// DeleteByUser deletes every todo of the user.
+func (r *todoRepository) DeleteByUser(userID primitive.ObjectID) error {
+	collection := config.DB.Collection("todos")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	return err
+}
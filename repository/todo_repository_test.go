@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTodoSortOrder(t *testing.T) {
+	tests := []struct {
+		sort models.TodoSort
+		want bson.D
+	}{
+		{models.TodoSort{}, bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{models.TodoSort{Field: models.SortByPriority, Descending: true}, bson.D{{Key: "priority", Value: -1}, {Key: "_id", Value: -1}}},
+		{models.TodoSort{Field: models.SortByTitle}, bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}}},
+	}
+	for _, tt := range tests {
+		if got := todoSortOrder(tt.sort); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("todoSortOrder(%+v) = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+// keysetDoc is a todo reduced to its sort key and _id.
+type keysetDoc struct {
+	value interface{} // int or nil
+	id    primitive.ObjectID
+}
+
+// compareKeys orders two sort keys the way MongoDB does for these values:
+// null sorts before every number.
+func compareKeys(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.(int) - b.(int)
+}
+
+// matches evaluates the subset of the MongoDB query language that
+// keysetCondition produces against a document.
+func matches(doc keysetDoc, cond bson.M) bool {
+	for key, want := range cond {
+		if key == "$or" {
+			matched := false
+			for _, clause := range want.(bson.A) {
+				matched = matched || matches(doc, clause.(bson.M))
+			}
+			if !matched {
+				return false
+			}
+			continue
+		}
+		var got interface{} = doc.value
+		compare := func(want interface{}) int { return compareKeys(got, want) }
+		if key == "_id" {
+			got = doc.id
+			compare = func(want interface{}) int {
+				id := want.(primitive.ObjectID)
+				return bytes.Compare(doc.id[:], id[:])
+			}
+		}
+		ops, ok := want.(bson.M)
+		if !ok {
+			ops = bson.M{"$eq": want}
+		}
+		for op, arg := range ops {
+			// Range operators never match null or missing values.
+			ranged := got != nil && arg != nil
+			var ok bool
+			switch op {
+			case "$eq":
+				ok = (got == nil && arg == nil) || (ranged && compare(arg) == 0)
+			case "$ne":
+				ok = !((got == nil && arg == nil) || (ranged && compare(arg) == 0))
+			case "$gt":
+				ok = ranged && compare(arg) > 0
+			case "$lt":
+				ok = ranged && compare(arg) < 0
+			default:
+				panic("unexpected operator " + op)
+			}
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestKeysetConditionOrdering(t *testing.T) {
+	// Documents in ascending sort order: null keys first, then by value,
+	// with ties broken by _id.
+	var docs []keysetDoc
+	for _, value := range []interface{}{nil, nil, nil, 1, 1, 2, 3, 3} {
+		docs = append(docs, keysetDoc{value: value, id: primitive.NewObjectID()})
+	}
+
+	for _, descending := range []bool{false, true} {
+		ordered := docs
+		if descending {
+			ordered = make([]keysetDoc, len(docs))
+			for i, doc := range docs {
+				ordered[len(docs)-1-i] = doc
+			}
+		}
+		sort := models.TodoSort{Field: models.SortByPriority, Descending: descending}
+		for i, position := range ordered {
+			for _, backward := range []bool{false, true} {
+				want := ordered[i+1:]
+				if backward {
+					want = ordered[:i]
+				}
+				cond := keysetCondition(sort, position.value, position.id, backward)
+				var got []keysetDoc
+				for _, doc := range ordered {
+					if matches(doc, cond) {
+						got = append(got, doc)
+					}
+				}
+				name := fmt.Sprintf("descending=%v backward=%v position=%d", descending, backward, i)
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("%s: matched %v, want %v", name, got, want)
+				}
+			}
+		}
+	}
+}
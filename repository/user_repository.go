@@ -2,11 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"time"
 	"todo-list-api/config"
 	"todo-list-api/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // UserRepository defines data access methods for User.
@@ -14,6 +19,43 @@ type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id primitive.ObjectID) (*models.User, error)
+	// UpdatePassword sets the password hash and clears a forced password reset.
+	UpdatePassword(id primitive.ObjectID, hash string) error
+	MarkEmailVerified(id primitive.ObjectID, at time.Time) error
+	// SetMFAPendingSecret starts an MFA enrollment with the given secret.
+	SetMFAPendingSecret(id primitive.ObjectID, secret string) error
+	// EnableMFA makes the secret active and stores the recovery code hashes.
+	EnableMFA(id primitive.ObjectID, secret string, recoveryCodeHashes []string) error
+	DisableMFA(id primitive.ObjectID) error
+	// UseMFAStep records an accepted TOTP time step and reports whether it is
+	// later than the last one, so that each code is accepted only once.
+	UseMFAStep(id primitive.ObjectID, step int64) (bool, error)
+	// StartMFAChallenge makes challengeID the user's only open MFA login challenge.
+	StartMFAChallenge(id primitive.ObjectID, challengeID string) error
+	// ClaimMFAAttempt counts an attempt at a second factor before the code is
+	// checked and returns the number of attempts since the last successful
+	// MFA login. It claims nothing, reporting false, if MFA is disabled or
+	// locked, maxAttempts were already claimed, or challengeID is not empty
+	// and is not the user's open challenge. A single conditional update makes
+	// parallel requests unable to try more codes than allowed.
+	ClaimMFAAttempt(id primitive.ObjectID, challengeID string, maxAttempts int) (int, bool, error)
+	// LockMFA closes the open MFA challenge and blocks MFA logins until the given time.
+	LockMFA(id primitive.ObjectID, until time.Time) error
+	// EndMFAChallenge closes the open MFA challenge after a successful login
+	// and resets the attempt count. It reports false if challengeID is no longer
+	// the open challenge, so that one challenge completes at most one login.
+	EndMFAChallenge(id primitive.ObjectID, challengeID string) (bool, error)
+	// UseRecoveryCode removes the recovery code hash and reports whether the
+	// user still had it.
+	UseRecoveryCode(id primitive.ObjectID, hash string) (bool, error)
+	// Search returns a page of users whose name or email contains text, in
+	// registration order, and the number of matching users.
+	Search(text string, page, limit int64) ([]models.User, int64, error)
+	SetRole(id primitive.ObjectID, role string) error
+	SetRoleByEmail(email, role string) error
+	SetDisabled(id primitive.ObjectID, disabled bool) error
+	RequirePasswordReset(id primitive.ObjectID) error
+	Delete(id primitive.ObjectID) error
 }
 
 type userRepository struct{}
@@ -25,8 +67,12 @@ func NewUserRepository() UserRepository {
 
 func (r *userRepository) Create(user *models.User) error {
 	collection := config.DB.Collection("users")
-	_, err := collection.InsertOne(context.Background(), user)
-	return err
+	res, err := collection.InsertOne(context.Background(), user)
+	if err != nil {
+		return err
+	}
+	user.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
 }
 
 func (r *userRepository) FindByEmail(email string) (*models.User, error) {
@@ -34,7 +80,7 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	var user models.User
 	err := collection.FindOne(context.Background(), bson.M{"email": email}).Decode(&user)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, ErrUserNotFound)
 	}
 	return &user, nil
 }
@@ -44,7 +90,190 @@ func (r *userRepository) FindByID(id primitive.ObjectID) (*models.User, error) {
 	var user models.User
 	err := collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&user)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, ErrUserNotFound)
 	}
 	return &user, nil
 }
+
+func (r *userRepository) UpdatePassword(id primitive.ObjectID, hash string) error {
+	return r.update(id, bson.M{
+		"$set":   bson.M{"password": hash},
+		"$unset": bson.M{"password_reset_required": ""},
+	})
+}
+
+func (r *userRepository) MarkEmailVerified(id primitive.ObjectID, at time.Time) error {
+	return r.update(id, bson.M{"$set": bson.M{"email_verified": true, "verified_at": at}})
+}
+
+func (r *userRepository) SetMFAPendingSecret(id primitive.ObjectID, secret string) error {
+	return r.update(id, bson.M{"$set": bson.M{"mfa_pending_secret": secret}})
+}
+
+func (r *userRepository) EnableMFA(id primitive.ObjectID, secret string, recoveryCodeHashes []string) error {
+	return r.update(id, bson.M{
+		"$set":   bson.M{"mfa_enabled": true, "mfa_secret": secret, "mfa_recovery_codes": recoveryCodeHashes},
+		"$unset": bson.M{"mfa_pending_secret": "", "mfa_last_step": ""},
+	})
+}
+
+func (r *userRepository) DisableMFA(id primitive.ObjectID) error {
+	return r.update(id, bson.M{
+		"$set":   bson.M{"mfa_enabled": false},
+		"$unset": bson.M{"mfa_secret": "", "mfa_pending_secret": "", "mfa_recovery_codes": "", "mfa_last_step": "", "mfa_challenge_id": "", "mfa_failed_attempts": "", "mfa_locked_until": ""},
+	})
+}
+
+func (r *userRepository) UseMFAStep(id primitive.ObjectID, step int64) (bool, error) {
+	collection := config.DB.Collection("users")
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"mfa_last_step": bson.M{"$exists": false}},
+			bson.M{"mfa_last_step": bson.M{"$lt": step}},
+		},
+	}
+	res, err := collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"mfa_last_step": step}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *userRepository) StartMFAChallenge(id primitive.ObjectID, challengeID string) error {
+	return r.update(id, bson.M{"$set": bson.M{"mfa_challenge_id": challengeID}})
+}
+
+func (r *userRepository) ClaimMFAAttempt(id primitive.ObjectID, challengeID string, maxAttempts int) (int, bool, error) {
+	collection := config.DB.Collection("users")
+	filter := bson.M{
+		"_id":                 id,
+		"mfa_enabled":         true,
+		"mfa_failed_attempts": bson.M{"$not": bson.M{"$gte": maxAttempts}},
+		"$or": bson.A{
+			bson.M{"mfa_locked_until": bson.M{"$exists": false}},
+			bson.M{"mfa_locked_until": bson.M{"$lte": time.Now()}},
+		},
+	}
+	if challengeID != "" {
+		filter["mfa_challenge_id"] = challengeID
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"mfa_failed_attempts": 1})
+	var user models.User
+	err := collection.FindOneAndUpdate(context.Background(), filter, bson.M{"$inc": bson.M{"mfa_failed_attempts": 1}}, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return user.MFAFailedAttempts, true, nil
+}
+
+func (r *userRepository) LockMFA(id primitive.ObjectID, until time.Time) error {
+	return r.update(id, bson.M{
+		"$set":   bson.M{"mfa_locked_until": until},
+		"$unset": bson.M{"mfa_challenge_id": "", "mfa_failed_attempts": ""},
+	})
+}
+
+func (r *userRepository) EndMFAChallenge(id primitive.ObjectID, challengeID string) (bool, error) {
+	collection := config.DB.Collection("users")
+	filter := bson.M{"_id": id, "mfa_challenge_id": challengeID}
+	update := bson.M{"$unset": bson.M{"mfa_challenge_id": "", "mfa_failed_attempts": "", "mfa_locked_until": ""}}
+	res, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *userRepository) UseRecoveryCode(id primitive.ObjectID, hash string) (bool, error) {
+	collection := config.DB.Collection("users")
+	filter := bson.M{"_id": id, "mfa_recovery_codes": hash}
+	res, err := collection.UpdateOne(context.Background(), filter, bson.M{"$pull": bson.M{"mfa_recovery_codes": hash}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *userRepository) Search(text string, page, limit int64) ([]models.User, int64, error) {
+	collection := config.DB.Collection("users")
+	filter := bson.M{}
+	if text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(text), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"email": pattern}}
+	}
+	total, err := collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
+	cursor, err := collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	users := []models.User{}
+	if err := cursor.All(context.Background(), &users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *userRepository) SetRole(id primitive.ObjectID, role string) error {
+	return r.update(id, bson.M{"$set": bson.M{"role": role}})
+}
+
+func (r *userRepository) SetRoleByEmail(email, role string) error {
+	collection := config.DB.Collection("users")
+	res, err := collection.UpdateOne(context.Background(), bson.M{"email": email}, bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) SetDisabled(id primitive.ObjectID, disabled bool) error {
+	if disabled {
+		return r.update(id, bson.M{"$set": bson.M{"disabled": true, "disabled_at": time.Now()}})
+	}
+	return r.update(id, bson.M{"$unset": bson.M{"disabled": "", "disabled_at": ""}})
+}
+
+func (r *userRepository) RequirePasswordReset(id primitive.ObjectID) error {
+	return r.update(id, bson.M{"$set": bson.M{"password_reset_required": true}})
+}
+
+func (r *userRepository) Delete(id primitive.ObjectID) error {
+	collection := config.DB.Collection("users")
+	res, err := collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// update applies update to the user and reports a missing user as ErrUserNotFound.
+func (r *userRepository) update(id primitive.ObjectID, update bson.M) error {
+	collection := config.DB.Collection("users")
+	res, err := collection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
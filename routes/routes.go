@@ -1,10 +1,15 @@
 package routes
 
 import (
+	"log"
+	"os"
 	"todo-list-api/controllers"
+	"todo-list-api/mailer"
 	"todo-list-api/middlewares"
+	"todo-list-api/models"
 	"todo-list-api/repository"
 	"todo-list-api/services"
+	"todo-list-api/validation"
 
 	"github.com/gin-gonic/gin"
 
@@ -14,30 +19,144 @@ import (
 
 // RegisterRoutes sets up all API routes.
 func RegisterRoutes(r *gin.Engine) {
+	// Custom validators used by the binding tags on request bodies.
+	validation.Register()
+
+	// Errors attached by handlers are written as application/problem+json.
+	r.Use(middlewares.ErrorHandler())
+	r.NoRoute(middlewares.NoRoute)
+
 	// Initialize repositories.
 	userRepo := repository.NewUserRepository()
 	todoRepo := repository.NewTodoRepository()
+	labelRepo := repository.NewLabelRepository()
+	projectRepo := repository.NewProjectRepository()
+	smartListRepo := repository.NewSmartListRepository()
+	refreshTokenRepo := repository.NewRefreshTokenRepository()
+	revocationRepo := repository.NewRevocationRepository()
+	passwordResetRepo := repository.NewPasswordResetRepository()
+	accessTokenRepo := repository.NewPersonalAccessTokenRepository()
+	auditLogRepo := repository.NewAuditLogRepository()
+
+	if len(services.JWTSecret()) == 0 {
+		log.Fatal("JWT_SECRET must be set")
+	}
+	tokenConfig, err := services.TokenConfigFromEnv()
+	if err != nil {
+		log.Fatal("Invalid token configuration: ", err)
+	}
+	mail, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure mailer: ", err)
+	}
 
 	// Initialize services.
-	authService := services.NewAuthService(userRepo)
-	todoService := services.NewTodoService(todoRepo)
+	revocationService := services.NewRevocationService(revocationRepo, tokenConfig)
+	authService := services.NewAuthService(userRepo, projectRepo, refreshTokenRepo, accessTokenRepo, passwordResetRepo, revocationService, mail, tokenConfig)
+	accessTokenService := services.NewPersonalAccessTokenService(accessTokenRepo)
+	adminService := services.NewAdminService(userRepo, todoRepo, labelRepo, projectRepo, smartListRepo, refreshTokenRepo, accessTokenRepo, passwordResetRepo, revocationRepo, auditLogRepo, authService)
+	todoService := services.NewTodoService(todoRepo, labelRepo, projectRepo)
+	labelService := services.NewLabelService(labelRepo, todoRepo, smartListRepo)
+	projectService := services.NewProjectService(projectRepo, todoRepo, smartListRepo)
+	smartListService := services.NewSmartListService(smartListRepo, projectRepo, userRepo)
 
 	// Initialize controllers.
 	authController := controllers.NewAuthController(authService)
+	accessTokenController := controllers.NewAccessTokenController(accessTokenService)
+	adminController := controllers.NewAdminController(adminService)
 	todoController := controllers.NewTodoController(todoService)
+	labelController := controllers.NewLabelController(labelService)
+	projectController := controllers.NewProjectController(projectService, todoService)
+	smartListController := controllers.NewSmartListController(smartListService, todoService)
 
 	// Public routes.
 	r.POST("/register", authController.Register)
 	r.POST("/login", authController.Login)
+	r.POST("/login/mfa", authController.LoginMFA)
+	r.POST("/token/refresh", authController.Refresh)
+	r.POST("/password/forgot", authController.ForgotPassword)
+	r.POST("/password/reset", authController.ResetPassword)
+	r.POST("/verify-email", authController.VerifyEmail)
+
+	// Scopes required per route; tokens without scopes have the default ones.
+	readTodos := middlewares.RequireScopes(models.ScopeTodosRead)
+	writeTodos := middlewares.RequireScopes(models.ScopeTodosWrite)
+	profile := middlewares.RequireScopes(models.ScopeProfile)
+
+	// Account routes stay available to users who have not verified their email.
+	authConfig := middlewares.AuthConfig{Revocations: revocationService, AccessTokens: accessTokenService, Users: authService}
+	accountRoutes := r.Group("/")
+	accountRoutes.Use(middlewares.JWTAuthMiddleware(authConfig))
+	{
+		accountRoutes.POST("/logout", authController.Logout)
+		accountRoutes.POST("/logout-all", profile, authController.LogoutAll)
+		accountRoutes.PUT("/password", profile, authController.ChangePassword)
+		accountRoutes.POST("/verify-email/resend", profile, authController.ResendVerification)
+		accountRoutes.POST("/mfa/enroll", profile, authController.EnrollMFA)
+		accountRoutes.POST("/mfa/confirm", profile, authController.ConfirmMFA)
+		accountRoutes.POST("/mfa/disable", profile, authController.DisableMFA)
+
+		accountRoutes.POST("/tokens", profile, accessTokenController.CreateToken)
+		accountRoutes.GET("/tokens", profile, accessTokenController.GetTokens)
+		accountRoutes.GET("/tokens/:id", profile, accessTokenController.GetToken)
+		accountRoutes.DELETE("/tokens/:id", profile, accessTokenController.RevokeToken)
+	}
+
+	// Admin routes need the admin scope and the user's current role to be admin.
+	adminRoutes := r.Group("/admin")
+	adminRoutes.Use(middlewares.JWTAuthMiddleware(authConfig), middlewares.RequireScopes(models.ScopeAdmin), middlewares.RequireRole(models.RoleAdmin))
+	{
+		adminRoutes.GET("/users", adminController.ListUsers)
+		adminRoutes.GET("/users/:id", adminController.GetUser)
+		adminRoutes.DELETE("/users/:id", adminController.DeleteUser)
+		adminRoutes.GET("/users/:id/todo-counts", adminController.GetTodoCounts)
+		adminRoutes.PUT("/users/:id/role", adminController.SetRole)
+		adminRoutes.POST("/users/:id/disable", adminController.DisableUser)
+		adminRoutes.POST("/users/:id/enable", adminController.EnableUser)
+		adminRoutes.POST("/users/:id/force-password-reset", adminController.ForcePasswordReset)
+		adminRoutes.GET("/audit-log", adminController.GetAuditLog)
+	}
 
 	// Protected routes (require JWT).
 	authRoutes := r.Group("/")
-	authRoutes.Use(middlewares.JWTAuthMiddleware())
+	// REQUIRE_VERIFIED_EMAIL=true makes to-do data read-only until the email is verified.
+	authConfig.RequireVerifiedEmail = os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true"
+	authRoutes.Use(middlewares.JWTAuthMiddleware(authConfig))
 	{
-		authRoutes.POST("/todos", todoController.CreateTodo)
-		authRoutes.PUT("/todos/:id", todoController.UpdateTodo)
-		authRoutes.DELETE("/todos/:id", todoController.DeleteTodo)
-		authRoutes.GET("/todos", todoController.GetTodos)
+		authRoutes.POST("/todos", writeTodos, todoController.CreateTodo)
+		authRoutes.GET("/todos/search", readTodos, todoController.SearchTodos)
+		authRoutes.GET("/todos/:id", readTodos, todoController.GetTodo)
+		authRoutes.PUT("/todos/:id", writeTodos, todoController.UpdateTodo)
+		authRoutes.PATCH("/todos/:id", writeTodos, todoController.PatchTodo)
+		authRoutes.DELETE("/todos/:id", writeTodos, todoController.DeleteTodo)
+		authRoutes.GET("/todos", readTodos, todoController.GetTodos)
+		authRoutes.POST("/todos/:id/complete", writeTodos, todoController.CompleteTodo)
+		authRoutes.POST("/todos/:id/reopen", writeTodos, todoController.ReopenTodo)
+		authRoutes.POST("/todos/:id/checklist", writeTodos, todoController.AddChecklistItem)
+		authRoutes.PUT("/todos/:id/checklist/order", writeTodos, todoController.ReorderChecklist)
+		authRoutes.PATCH("/todos/:id/checklist/:itemId", writeTodos, todoController.UpdateChecklistItem)
+		authRoutes.POST("/todos/:id/checklist/:itemId/toggle", writeTodos, todoController.ToggleChecklistItem)
+		authRoutes.DELETE("/todos/:id/checklist/:itemId", writeTodos, todoController.RemoveChecklistItem)
+
+		authRoutes.POST("/labels", writeTodos, labelController.CreateLabel)
+		authRoutes.GET("/labels", readTodos, labelController.GetLabels)
+		authRoutes.GET("/labels/:id", readTodos, labelController.GetLabel)
+		authRoutes.PUT("/labels/:id", writeTodos, labelController.UpdateLabel)
+		authRoutes.DELETE("/labels/:id", writeTodos, labelController.DeleteLabel)
+
+		authRoutes.POST("/projects", writeTodos, projectController.CreateProject)
+		authRoutes.GET("/projects", readTodos, projectController.GetProjects)
+		authRoutes.GET("/projects/:id", readTodos, projectController.GetProject)
+		authRoutes.PUT("/projects/:id", writeTodos, projectController.UpdateProject)
+		authRoutes.DELETE("/projects/:id", writeTodos, projectController.DeleteProject)
+		authRoutes.GET("/projects/:id/todos", readTodos, projectController.GetProjectTodos)
+
+		authRoutes.POST("/smart-lists", writeTodos, smartListController.CreateSmartList)
+		authRoutes.GET("/smart-lists", readTodos, smartListController.GetSmartLists)
+		authRoutes.GET("/smart-lists/:id", readTodos, smartListController.GetSmartList)
+		authRoutes.PUT("/smart-lists/:id", writeTodos, smartListController.UpdateSmartList)
+		authRoutes.DELETE("/smart-lists/:id", writeTodos, smartListController.DeleteSmartList)
+		authRoutes.GET("/smart-lists/:id/todos", readTodos, smartListController.GetSmartListTodos)
 	}
 
 	// Uncomment to serve Swagger docs.
@@ -0,0 +1,170 @@
+// Package scheduler runs background jobs inside the API process.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"os"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/notifier"
+	"todo-list-api/repository"
+)
+
+const (
+	// reminderLockName is the lease document shared by all replicas.
+	reminderLockName = "reminder-scheduler"
+	// reminderBatchSize caps how many todos are processed per scan.
+	reminderBatchSize = 100
+	// maxReminderAttempts is how many failed deliveries a reminder gets
+	// before the scheduler gives up on it.
+	maxReminderAttempts = 5
+	// reminderRetryDelay is the wait after the first failed delivery; it
+	// doubles with every further failure.
+	reminderRetryDelay = time.Minute
+)
+
+// ReminderScheduler periodically sends due reminders. Replicas compete for a
+// MongoDB lease so only one of them dispatches reminders at a time, and each
+// reminder is claimed before it is sent so it is delivered once.
+type ReminderScheduler struct {
+	todoRepo repository.TodoRepository
+	userRepo repository.UserRepository
+	lockRepo repository.LockRepository
+	notifier notifier.Notifier
+	interval time.Duration
+	owner    string
+}
+
+// NewReminderScheduler returns a scheduler that scans for due reminders every interval.
+func NewReminderScheduler(todoRepo repository.TodoRepository, userRepo repository.UserRepository, lockRepo repository.LockRepository, n notifier.Notifier, interval time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{
+		todoRepo: todoRepo,
+		userRepo: userRepo,
+		lockRepo: lockRepo,
+		notifier: n,
+		interval: interval,
+		owner:    instanceID(),
+	}
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (s *ReminderScheduler) Start(ctx context.Context) {
+	log.Printf("Reminder scheduler started (instance %s, interval %s)", s.owner, s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			if err := s.lockRepo.Release(reminderLockName, s.owner); err != nil {
+				log.Printf("Reminder scheduler: failed to release lease: %v", err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReminderScheduler) tick(ctx context.Context) {
+	// The lease outlives a few intervals so a slow scan does not lose it,
+	// while a crashed replica is replaced quickly.
+	leader, err := s.lockRepo.Acquire(reminderLockName, s.owner, 3*s.interval)
+	if err != nil {
+		log.Printf("Reminder scheduler: failed to acquire lease: %v", err)
+		return
+	}
+	if !leader {
+		return
+	}
+
+	now := time.Now()
+	todos, err := s.todoRepo.FindDueReminders(now, reminderBatchSize)
+	if err != nil {
+		log.Printf("Reminder scheduler: failed to load due reminders: %v", err)
+		return
+	}
+	for i := range todos {
+		s.dispatch(ctx, &todos[i], now)
+	}
+}
+
+// dispatch sends every due, unsent reminder of a todo. A failed delivery is
+// retried with exponential backoff until maxReminderAttempts is reached, and
+// reminders of a user that no longer exists are given up at once.
+func (s *ReminderScheduler) dispatch(ctx context.Context, todo *models.Todo, now time.Time) {
+	user, err := s.userRepo.FindByID(todo.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		log.Printf("Reminder scheduler: user %s of todo %s no longer exists", todo.UserID.Hex(), todo.ID.Hex())
+		for _, reminder := range dueReminders(todo, now) {
+			s.fail(todo, reminder, now)
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("Reminder scheduler: failed to load user %s: %v", todo.UserID.Hex(), err)
+		return
+	}
+	for _, reminder := range dueReminders(todo, now) {
+		claimed, err := s.todoRepo.ClaimReminder(todo.ID, reminder.ID, now)
+		if err != nil || !claimed {
+			continue
+		}
+		notification := notifier.Notification{
+			UserID:      user.ID.Hex(),
+			Email:       user.Email,
+			Name:        user.Name,
+			TodoID:      todo.ID.Hex(),
+			ReminderID:  reminder.ID.Hex(),
+			Title:       todo.Title,
+			Description: todo.Description,
+			DueAt:       todo.DueAt,
+			FireAt:      reminder.FireAt,
+		}
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			log.Printf("Reminder scheduler: failed to send reminder %s (attempt %d): %v", reminder.ID.Hex(), reminder.Attempts+1, err)
+			if reminder.Attempts+1 >= maxReminderAttempts {
+				s.fail(todo, reminder, now)
+				continue
+			}
+			retryAt := now.Add(reminderRetryDelay << reminder.Attempts)
+			if err := s.todoRepo.RetryReminder(todo.ID, reminder.ID, retryAt); err != nil {
+				log.Printf("Reminder scheduler: failed to reschedule reminder %s: %v", reminder.ID.Hex(), err)
+			}
+		}
+	}
+}
+
+// fail gives up on a reminder.
+func (s *ReminderScheduler) fail(todo *models.Todo, reminder models.Reminder, now time.Time) {
+	log.Printf("Reminder scheduler: giving up on reminder %s of todo %s", reminder.ID.Hex(), todo.ID.Hex())
+	if err := s.todoRepo.FailReminder(todo.ID, reminder.ID, now); err != nil {
+		log.Printf("Reminder scheduler: failed to mark reminder %s as failed: %v", reminder.ID.Hex(), err)
+	}
+}
+
+// dueReminders returns the todo's reminders that should be delivered now.
+func dueReminders(todo *models.Todo, now time.Time) []models.Reminder {
+	var due []models.Reminder
+	for _, reminder := range todo.Reminders {
+		if reminder.SentAt != nil || reminder.FailedAt != nil || reminder.FireAt.After(now) {
+			continue
+		}
+		if reminder.RetryAt != nil && reminder.RetryAt.After(now) {
+			continue
+		}
+		due = append(due, reminder)
+	}
+	return due
+}
+
+// instanceID identifies this process as a lease owner.
+func instanceID() string {
+	host, _ := os.Hostname()
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return host + "-" + hex.EncodeToString(buf)
+}
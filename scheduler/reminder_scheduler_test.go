@@ -0,0 +1,332 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/notifier"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeLocks is an in-memory LockRepository with the same lease semantics as
+// the MongoDB implementation: a lease can be taken when it is free, expired
+// or already held by the caller.
+type fakeLocks struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	leases map[string]lease
+}
+
+type lease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+func newFakeLocks(now func() time.Time) *fakeLocks {
+	return &fakeLocks{now: now, leases: map[string]lease{}}
+}
+
+func (l *fakeLocks) Acquire(name, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	current, ok := l.leases[name]
+	if ok && current.owner != owner && current.expiresAt.After(now) {
+		return false, nil
+	}
+	l.leases[name] = lease{owner: owner, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (l *fakeLocks) Release(name, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.leases[name].owner == owner {
+		delete(l.leases, name)
+	}
+	return nil
+}
+
+func (l *fakeLocks) owner(name string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.leases[name].owner
+}
+
+// fakeTodos serves a fixed set of todos and tracks claimed reminders.
+type fakeTodos struct {
+	repository.TodoRepository
+	mu    sync.Mutex
+	todos []models.Todo
+}
+
+func (r *fakeTodos) FindDueReminders(now time.Time, limit int64) ([]models.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	todos := make([]models.Todo, len(r.todos))
+	for i, todo := range r.todos {
+		todo.Reminders = append([]models.Reminder(nil), todo.Reminders...)
+		todos[i] = todo
+	}
+	return todos, nil
+}
+
+func (r *fakeTodos) ClaimReminder(id, reminderID primitive.ObjectID, sentAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reminder := r.find(id, reminderID)
+	if reminder == nil || reminder.SentAt != nil {
+		return false, nil
+	}
+	reminder.SentAt = &sentAt
+	return true, nil
+}
+
+func (r *fakeTodos) RetryReminder(id, reminderID primitive.ObjectID, retryAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reminder := r.find(id, reminderID); reminder != nil {
+		reminder.SentAt = nil
+		reminder.RetryAt = &retryAt
+		reminder.Attempts++
+	}
+	return nil
+}
+
+func (r *fakeTodos) FailReminder(id, reminderID primitive.ObjectID, failedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reminder := r.find(id, reminderID); reminder != nil {
+		reminder.SentAt = nil
+		reminder.RetryAt = nil
+		reminder.FailedAt = &failedAt
+		reminder.Attempts++
+	}
+	return nil
+}
+
+func (r *fakeTodos) find(id, reminderID primitive.ObjectID) *models.Reminder {
+	for i := range r.todos {
+		if r.todos[i].ID != id {
+			continue
+		}
+		for j := range r.todos[i].Reminders {
+			if r.todos[i].Reminders[j].ID == reminderID {
+				return &r.todos[i].Reminders[j]
+			}
+		}
+	}
+	return nil
+}
+
+// fakeUsers returns user for every ID except those in missing.
+type fakeUsers struct {
+	repository.UserRepository
+	user    models.User
+	missing map[primitive.ObjectID]bool
+}
+
+func (r *fakeUsers) FindByID(id primitive.ObjectID) (*models.User, error) {
+	if r.missing[id] {
+		return nil, repository.ErrUserNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+// recordingNotifier remembers the reminders it was asked to send and fails
+// for the todos in failing.
+type recordingNotifier struct {
+	mu      sync.Mutex
+	sent    []string
+	failing map[string]bool
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification notifier.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.failing[notification.TodoID] {
+		return errors.New("mail server unavailable")
+	}
+	n.sent = append(n.sent, notification.ReminderID)
+	return nil
+}
+
+func (n *recordingNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.sent)
+}
+
+// newReplicas returns two schedulers sharing the same lease store and data,
+// each with its own notifier.
+func newReplicas(locks *fakeLocks, todos *fakeTodos) ([2]*ReminderScheduler, [2]*recordingNotifier) {
+	users := &fakeUsers{user: models.User{ID: primitive.NewObjectID(), Email: "ada@example.com"}}
+	var schedulers [2]*ReminderScheduler
+	var notifiers [2]*recordingNotifier
+	for i, owner := range []string{"replica-a", "replica-b"} {
+		notifiers[i] = &recordingNotifier{}
+		schedulers[i] = NewReminderScheduler(todos, users, locks, notifiers[i], time.Minute)
+		schedulers[i].owner = owner
+	}
+	return schedulers, notifiers
+}
+
+func dueTodo(now time.Time, reminders int) models.Todo {
+	todo := models.Todo{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Title: "Pay rent"}
+	for i := 0; i < reminders; i++ {
+		todo.Reminders = append(todo.Reminders, models.Reminder{ID: primitive.NewObjectID(), FireAt: now.Add(-time.Minute)})
+	}
+	return todo
+}
+
+func TestReminderSchedulerLeaseContention(t *testing.T) {
+	// Only the lease clock is faked; reminders fall due against the real one.
+	now := time.Now()
+	clock := func() time.Time { return now }
+	locks := newFakeLocks(clock)
+	todos := &fakeTodos{todos: []models.Todo{dueTodo(now, 2)}}
+	replicas, notifiers := newReplicas(locks, todos)
+	ctx := context.Background()
+
+	// The first replica to tick becomes the leader and sends the reminders.
+	replicas[0].tick(ctx)
+	replicas[1].tick(ctx)
+	if got := locks.owner(reminderLockName); got != "replica-a" {
+		t.Fatalf("lease owner = %q, want replica-a", got)
+	}
+	if notifiers[0].count() != 2 || notifiers[1].count() != 0 {
+		t.Fatalf("sent = %d/%d, want 2/0", notifiers[0].count(), notifiers[1].count())
+	}
+
+	// The leader renews its lease on every tick, so the other replica stays
+	// out even after the original TTL would have run out.
+	now = now.Add(2 * time.Minute)
+	replicas[0].tick(ctx)
+	todos.todos = append(todos.todos, dueTodo(time.Now(), 1))
+	now = now.Add(2 * time.Minute)
+	replicas[1].tick(ctx)
+	if got := locks.owner(reminderLockName); got != "replica-a" {
+		t.Fatalf("lease owner after renewal = %q, want replica-a", got)
+	}
+	if notifiers[1].count() != 0 {
+		t.Fatalf("follower sent %d reminders, want 0", notifiers[1].count())
+	}
+
+	// Once the leader stops renewing, the lease expires and the other
+	// replica takes over the pending reminder.
+	now = now.Add(3*time.Minute + time.Second)
+	replicas[1].tick(ctx)
+	if got := locks.owner(reminderLockName); got != "replica-b" {
+		t.Fatalf("lease owner after expiry = %q, want replica-b", got)
+	}
+	if notifiers[1].count() != 1 {
+		t.Fatalf("new leader sent %d reminders, want 1", notifiers[1].count())
+	}
+	if total := notifiers[0].count() + notifiers[1].count(); total != 3 {
+		t.Fatalf("sent %d reminders in total, want each of 3 once", total)
+	}
+}
+
+func TestReminderSchedulerReleasesLeaseOnStop(t *testing.T) {
+	locks := newFakeLocks(time.Now)
+	todos := &fakeTodos{}
+	replicas, _ := newReplicas(locks, todos)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		replicas[0].Start(ctx)
+		close(done)
+	}()
+
+	// Wait for the first tick to take the lease, then stop the leader.
+	deadline := time.Now().Add(time.Second)
+	for locks.owner(reminderLockName) != "replica-a" {
+		if time.Now().After(deadline) {
+			t.Fatal("leader never acquired the lease")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	leader, err := locks.Acquire(reminderLockName, "replica-b", time.Minute)
+	if err != nil || !leader {
+		t.Fatalf("Acquire after stop = %v, %v; want the released lease", leader, err)
+	}
+}
+
+func TestReminderSchedulerBacksOffAndGivesUp(t *testing.T) {
+	locks := newFakeLocks(time.Now)
+	todo := dueTodo(time.Now(), 1)
+	todos := &fakeTodos{todos: []models.Todo{todo}}
+	users := &fakeUsers{user: models.User{ID: todo.UserID, Email: "ada@example.com"}}
+	n := &recordingNotifier{failing: map[string]bool{todo.ID.Hex(): true}}
+	s := NewReminderScheduler(todos, users, locks, n, time.Minute)
+	ctx := context.Background()
+
+	reminder := func() models.Reminder { return todos.todos[0].Reminders[0] }
+	now := time.Now()
+	var waits []time.Duration
+	for attempt := 1; attempt <= maxReminderAttempts; attempt++ {
+		s.dispatch(ctx, &todos.todos[0], now)
+		got := reminder()
+		if got.Attempts != attempt || got.SentAt != nil {
+			t.Fatalf("attempt %d: attempts = %d, sent_at = %v", attempt, got.Attempts, got.SentAt)
+		}
+		if got.RetryAt == nil {
+			break
+		}
+		waits = append(waits, got.RetryAt.Sub(now))
+
+		// The reminder is not retried before its retry time.
+		s.dispatch(ctx, &todos.todos[0], now)
+		if reminder().Attempts != attempt {
+			t.Fatalf("attempt %d: retried before the backoff elapsed", attempt)
+		}
+		now = *got.RetryAt
+	}
+
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	if len(waits) != len(want) {
+		t.Fatalf("backoff = %v, want %v", waits, want)
+	}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("backoff = %v, want %v", waits, want)
+			break
+		}
+	}
+	if got := reminder(); got.FailedAt == nil || got.RetryAt != nil {
+		t.Fatalf("after %d failures: failed_at = %v, retry_at = %v; want the reminder given up", maxReminderAttempts, got.FailedAt, got.RetryAt)
+	}
+	s.dispatch(ctx, &todos.todos[0], now.Add(time.Hour))
+	if got := reminder().Attempts; got != maxReminderAttempts {
+		t.Errorf("failed reminder retried: %d attempts, want %d", got, maxReminderAttempts)
+	}
+}
+
+func TestReminderSchedulerGivesUpOnMissingUser(t *testing.T) {
+	locks := newFakeLocks(time.Now)
+	todo := dueTodo(time.Now(), 2)
+	todos := &fakeTodos{todos: []models.Todo{todo}}
+	users := &fakeUsers{missing: map[primitive.ObjectID]bool{todo.UserID: true}}
+	n := &recordingNotifier{}
+	s := NewReminderScheduler(todos, users, locks, n, time.Minute)
+
+	s.tick(context.Background())
+	for _, reminder := range todos.todos[0].Reminders {
+		if reminder.FailedAt == nil {
+			t.Errorf("reminder %s of a deleted user not given up", reminder.ID.Hex())
+		}
+	}
+	if n.count() != 0 {
+		t.Errorf("sent %d reminders to a deleted user", n.count())
+	}
+}
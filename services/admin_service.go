@@ -0,0 +1,297 @@
+package services
+
+import (
+	"log"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrCannotModifySelf is returned when an admin tries to disable, delete or
+// change the role of their own account, which could leave no admin behind.
+var ErrCannotModifySelf = apperrors.Forbidden("cannot_modify_self", "admins cannot disable, delete or change the role of their own account")
+
+// ErrInvalidRole is returned for a role that does not exist.
+var ErrInvalidRole = apperrors.Validation("invalid_role", "role must be user or admin")
+
+// AdminService handles user administration. Every method takes the ID of
+// the acting admin and records the action in the audit log. Changes are
+// recorded before they are made, and their outcome afterwards.
+type AdminService interface {
+	// ListUsers returns a page of users whose name or email contains query.
+	ListUsers(actorID, query string, page, limit int64) ([]models.User, int64, error)
+	GetUser(actorID, userID string) (*models.User, error)
+	GetTodoCounts(actorID, userID string) (*models.TodoCounts, error)
+	SetRole(actorID, userID, role string) (*models.User, error)
+	// DisableUser locks the user out: their tokens stop working and they
+	// cannot log in until enabled again.
+	DisableUser(actorID, userID string) (*models.User, error)
+	EnableUser(actorID, userID string) (*models.User, error)
+	// ForcePasswordReset revokes the user's tokens and emails them a reset
+	// link; they cannot log in until they use it.
+	ForcePasswordReset(actorID, userID string) error
+	// DeleteUser deletes the user and all of their data. If it fails partway
+	// the account stays disabled, and calling it again finishes the deletion.
+	DeleteUser(actorID, userID string) error
+	// GetAuditLog returns a page of the audit log, newest first, optionally
+	// only the entries about one user.
+	GetAuditLog(actorID, targetUserID string, page, limit int64) ([]models.AuditLogEntry, int64, error)
+}
+
+type adminService struct {
+	userRepo          repository.UserRepository
+	todoRepo          repository.TodoRepository
+	labelRepo         repository.LabelRepository
+	projectRepo       repository.ProjectRepository
+	smartListRepo     repository.SmartListRepository
+	refreshTokenRepo  repository.RefreshTokenRepository
+	accessTokenRepo   repository.PersonalAccessTokenRepository
+	passwordResetRepo repository.PasswordResetRepository
+	revocationRepo    repository.RevocationRepository
+	auditLogRepo      repository.AuditLogRepository
+	authService       AuthService
+}
+
+// NewAdminService returns a new instance of AdminService.
+func NewAdminService(userRepo repository.UserRepository, todoRepo repository.TodoRepository, labelRepo repository.LabelRepository, projectRepo repository.ProjectRepository, smartListRepo repository.SmartListRepository, refreshTokenRepo repository.RefreshTokenRepository, accessTokenRepo repository.PersonalAccessTokenRepository, passwordResetRepo repository.PasswordResetRepository, revocationRepo repository.RevocationRepository, auditLogRepo repository.AuditLogRepository, authService AuthService) AdminService {
+	return &adminService{userRepo, todoRepo, labelRepo, projectRepo, smartListRepo, refreshTokenRepo, accessTokenRepo, passwordResetRepo, revocationRepo, auditLogRepo, authService}
+}
+
+func (s *adminService) ListUsers(actorID, query string, page, limit int64) ([]models.User, int64, error) {
+	users, total, err := s.userRepo.Search(query, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	var details map[string]string
+	if query != "" {
+		details = map[string]string{"query": query}
+	}
+	if err := s.audit(actorID, models.AuditListUsers, nil, details); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (s *adminService) GetUser(actorID, userID string) (*models.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit(actorID, models.AuditViewUser, &user.ID, nil); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *adminService) GetTodoCounts(actorID, userID string) (*models.TodoCounts, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	byStatus, err := s.todoRepo.CountByStatus(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	counts := &models.TodoCounts{ByStatus: make(map[string]int64, len(models.TodoStatuses))}
+	for _, status := range models.TodoStatuses {
+		counts.ByStatus[status] = byStatus[status]
+	}
+	for _, n := range byStatus {
+		counts.Total += n
+	}
+	now := time.Now()
+	counts.Overdue, err = s.todoRepo.CountTodos(user.ID, models.TodoFilter{DueBefore: &now, Pending: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit(actorID, models.AuditViewTodoCounts, &user.ID, nil); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (s *adminService) SetRole(actorID, userID, role string) (*models.User, error) {
+	if role != models.RoleUser && role != models.RoleAdmin {
+		return nil, ErrInvalidRole
+	}
+	user, err := s.findOtherUser(actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.record(actorID, models.AuditSetRole, &user.ID, map[string]string{"role": role}, func() error {
+		return s.userRepo.SetRole(user.ID, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.Role = role
+	return user, nil
+}
+
+func (s *adminService) DisableUser(actorID, userID string) (*models.User, error) {
+	user, err := s.findOtherUser(actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.record(actorID, models.AuditDisableUser, &user.ID, nil, func() error {
+		if err := s.userRepo.SetDisabled(user.ID, true); err != nil {
+			return err
+		}
+		// The middleware rejects the user's access tokens from now on; revoking
+		// them as well keeps them rejected should the account be enabled again.
+		return s.authService.LogoutAll(user.ID.Hex())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(user.ID)
+}
+
+func (s *adminService) EnableUser(actorID, userID string) (*models.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.record(actorID, models.AuditEnableUser, &user.ID, nil, func() error {
+		return s.userRepo.SetDisabled(user.ID, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(user.ID)
+}
+
+func (s *adminService) ForcePasswordReset(actorID, userID string) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	return s.record(actorID, models.AuditForcePasswordReset, &user.ID, nil, func() error {
+		return s.authService.RequirePasswordReset(user.ID.Hex())
+	})
+}
+
+func (s *adminService) DeleteUser(actorID, userID string) error {
+	user, err := s.findOtherUser(actorID, userID)
+	if err != nil {
+		return err
+	}
+	// The entry keeps the email, since the user it refers to will be gone.
+	return s.record(actorID, models.AuditDeleteUser, &user.ID, map[string]string{"email": user.Email}, func() error {
+		// The account is disabled and its tokens revoked first, so that the
+		// user can neither use nor add to data that is being deleted. The
+		// user document goes last and every step can be repeated, so a
+		// deletion that failed partway leaves a locked account that a retry
+		// finishes deleting.
+		if err := s.userRepo.SetDisabled(user.ID, true); err != nil {
+			return err
+		}
+		if err := s.authService.LogoutAll(user.ID.Hex()); err != nil {
+			return err
+		}
+		deletes := []func(primitive.ObjectID) error{
+			s.todoRepo.DeleteByUser,
+			s.labelRepo.DeleteByUser,
+			s.projectRepo.DeleteByUser,
+			s.smartListRepo.DeleteByUser,
+			s.refreshTokenRepo.DeleteByUser,
+			s.accessTokenRepo.DeleteByUser,
+			s.passwordResetRepo.DeleteByUser,
+			// Without its revocations, a token of the user is still
+			// rejected because the account is disabled and then gone.
+			s.revocationRepo.DeleteByUser,
+		}
+		for _, deleteByUser := range deletes {
+			if err := deleteByUser(user.ID); err != nil {
+				return err
+			}
+		}
+		return s.userRepo.Delete(user.ID)
+	})
+}
+
+func (s *adminService) GetAuditLog(actorID, targetUserID string, page, limit int64) ([]models.AuditLogEntry, int64, error) {
+	var target *primitive.ObjectID
+	var details map[string]string
+	if targetUserID != "" {
+		targetID, err := ParseID(targetUserID, ErrUserNotFound)
+		if err != nil {
+			return nil, 0, err
+		}
+		target = &targetID
+		details = map[string]string{"target_user_id": targetUserID}
+	}
+	entries, total, err := s.auditLogRepo.GetEntries(target, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.audit(actorID, models.AuditViewAuditLog, nil, details); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// findUser looks up the user an admin action is about.
+func (s *adminService) findUser(userID string) (*models.User, error) {
+	id, err := ParseID(userID, ErrUserNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(id)
+}
+
+// findOtherUser looks up the user an admin action is about and rejects the
+// admin's own account.
+func (s *adminService) findOtherUser(actorID, userID string) (*models.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID.Hex() == actorID {
+		return nil, ErrCannotModifySelf
+	}
+	return user, nil
+}
+
+// audit records a read by the admin. It is written before the data is
+// returned, so a failure is logged and returned in place of the data.
+func (s *adminService) audit(actorID, action string, target *primitive.ObjectID, details map[string]string) error {
+	_, err := s.writeEntry(actorID, action, target, details, models.AuditCompleted)
+	return err
+}
+
+// record writes a pending audit entry for a change by the admin, makes the
+// change with run and marks the entry completed or failed. Nothing is
+// changed if the entry cannot be written, so no change goes unrecorded; if
+// the outcome cannot be recorded, the entry stays pending.
+func (s *adminService) record(actorID, action string, target *primitive.ObjectID, details map[string]string, run func() error) error {
+	entry, err := s.writeEntry(actorID, action, target, details, models.AuditPending)
+	if err != nil {
+		return err
+	}
+	status := models.AuditCompleted
+	runErr := run()
+	if runErr != nil {
+		status = models.AuditFailed
+	}
+	if err := s.auditLogRepo.SetStatus(entry.ID, status); err != nil {
+		log.Printf("Failed to mark audit log entry %s as %s: %v", entry.ID.Hex(), status, err)
+	}
+	return runErr
+}
+
+func (s *adminService) writeEntry(actorID, action string, target *primitive.ObjectID, details map[string]string, status string) (*models.AuditLogEntry, error) {
+	actorObjID, err := ParseUserID(actorID)
+	if err != nil {
+		return nil, err
+	}
+	entry := &models.AuditLogEntry{ActorID: actorObjID, Action: action, TargetUserID: target, Details: details, Status: status}
+	if err := s.auditLogRepo.Create(entry); err != nil {
+		log.Printf("Failed to write audit log entry %s by admin %s: %v", action, actorID, err)
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// adminRecorder logs the repository calls made by the admin service, in
+// order, and fails the ones listed in fail.
+type adminRecorder struct {
+	calls []string
+	fail  map[string]error
+}
+
+func (r *adminRecorder) call(name string) error {
+	r.calls = append(r.calls, name)
+	return r.fail[name]
+}
+
+type fakeAdminUsers struct {
+	repository.UserRepository
+	rec   *adminRecorder
+	users map[primitive.ObjectID]*models.User
+}
+
+func (r *fakeAdminUsers) FindByID(id primitive.ObjectID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	stored := *user
+	return &stored, nil
+}
+
+func (r *fakeAdminUsers) SetRole(id primitive.ObjectID, role string) error {
+	if err := r.rec.call("users.SetRole"); err != nil {
+		return err
+	}
+	r.users[id].Role = role
+	return nil
+}
+
+func (r *fakeAdminUsers) SetDisabled(id primitive.ObjectID, disabled bool) error {
+	if err := r.rec.call("users.SetDisabled"); err != nil {
+		return err
+	}
+	r.users[id].Disabled = disabled
+	return nil
+}
+
+func (r *fakeAdminUsers) Delete(id primitive.ObjectID) error {
+	if err := r.rec.call("users.Delete"); err != nil {
+		return err
+	}
+	delete(r.users, id)
+	return nil
+}
+
+type fakeAdminTodos struct {
+	repository.TodoRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminTodos) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("todos.DeleteByUser")
+}
+
+type fakeAdminLabels struct {
+	repository.LabelRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminLabels) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("labels.DeleteByUser")
+}
+
+type fakeAdminProjects struct {
+	repository.ProjectRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminProjects) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("projects.DeleteByUser")
+}
+
+type fakeAdminSmartLists struct {
+	repository.SmartListRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminSmartLists) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("smartLists.DeleteByUser")
+}
+
+type fakeAdminRefreshTokens struct {
+	repository.RefreshTokenRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminRefreshTokens) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("refreshTokens.DeleteByUser")
+}
+
+type fakeAdminAccessTokens struct {
+	repository.PersonalAccessTokenRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminAccessTokens) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("accessTokens.DeleteByUser")
+}
+
+type fakeAdminPasswordResets struct {
+	repository.PasswordResetRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminPasswordResets) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("passwordResets.DeleteByUser")
+}
+
+type fakeAdminRevocations struct {
+	repository.RevocationRepository
+	rec *adminRecorder
+}
+
+func (r fakeAdminRevocations) DeleteByUser(primitive.ObjectID) error {
+	return r.rec.call("revocations.DeleteByUser")
+}
+
+type fakeAuditLog struct {
+	repository.AuditLogRepository
+	rec     *adminRecorder
+	entries []*models.AuditLogEntry
+}
+
+func (r *fakeAuditLog) Create(entry *models.AuditLogEntry) error {
+	if err := r.rec.call("audit.Create:" + entry.Status); err != nil {
+		return err
+	}
+	entry.ID = primitive.NewObjectID()
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+func (r *fakeAuditLog) SetStatus(id primitive.ObjectID, status string) error {
+	if err := r.rec.call("audit.SetStatus:" + status); err != nil {
+		return err
+	}
+	for _, entry := range r.entries {
+		if entry.ID == id {
+			entry.Status = status
+		}
+	}
+	return nil
+}
+
+type fakeAdminAuth struct {
+	AuthService
+	rec *adminRecorder
+}
+
+func (a fakeAdminAuth) LogoutAll(string) error { return a.rec.call("auth.LogoutAll") }
+
+func (a fakeAdminAuth) RequirePasswordReset(string) error {
+	return a.rec.call("auth.RequirePasswordReset")
+}
+
+type adminFixture struct {
+	service AdminService
+	rec     *adminRecorder
+	users   *fakeAdminUsers
+	audit   *fakeAuditLog
+	admin   *models.User
+	target  *models.User
+}
+
+func newAdminFixture() *adminFixture {
+	rec := &adminRecorder{fail: map[string]error{}}
+	admin := &models.User{ID: primitive.NewObjectID(), Email: "admin@example.com", Role: models.RoleAdmin}
+	target := &models.User{ID: primitive.NewObjectID(), Email: "ada@example.com", Role: models.RoleUser}
+	users := &fakeAdminUsers{rec: rec, users: map[primitive.ObjectID]*models.User{admin.ID: admin, target.ID: target}}
+	audit := &fakeAuditLog{rec: rec}
+	service := NewAdminService(
+		users,
+		fakeAdminTodos{rec: rec},
+		fakeAdminLabels{rec: rec},
+		fakeAdminProjects{rec: rec},
+		fakeAdminSmartLists{rec: rec},
+		fakeAdminRefreshTokens{rec: rec},
+		fakeAdminAccessTokens{rec: rec},
+		fakeAdminPasswordResets{rec: rec},
+		fakeAdminRevocations{rec: rec},
+		audit,
+		fakeAdminAuth{rec: rec},
+	)
+	return &adminFixture{service, rec, users, audit, admin, target}
+}
+
+func TestAdminServiceRejectsSelfModification(t *testing.T) {
+	actions := map[string]func(s AdminService, actorID string) error{
+		"SetRole": func(s AdminService, actorID string) error {
+			_, err := s.SetRole(actorID, actorID, models.RoleUser)
+			return err
+		},
+		"DisableUser": func(s AdminService, actorID string) error {
+			_, err := s.DisableUser(actorID, actorID)
+			return err
+		},
+		"DeleteUser": func(s AdminService, actorID string) error {
+			return s.DeleteUser(actorID, actorID)
+		},
+	}
+	for name, action := range actions {
+		t.Run(name, func(t *testing.T) {
+			f := newAdminFixture()
+			if err := action(f.service, f.admin.ID.Hex()); !errors.Is(err, ErrCannotModifySelf) {
+				t.Fatalf("%s on own account = %v, want ErrCannotModifySelf", name, err)
+			}
+			if len(f.rec.calls) != 0 {
+				t.Errorf("%s on own account made calls %v, want none", name, f.rec.calls)
+			}
+			if admin := f.users.users[f.admin.ID]; admin.Role != models.RoleAdmin || admin.Disabled {
+				t.Errorf("%s changed the admin's own account: %+v", name, admin)
+			}
+		})
+	}
+}
+
+func TestAdminServiceSetRoleValidatesRole(t *testing.T) {
+	for _, role := range []string{"", "superuser", "Admin"} {
+		t.Run(role, func(t *testing.T) {
+			f := newAdminFixture()
+			_, err := f.service.SetRole(f.admin.ID.Hex(), f.target.ID.Hex(), role)
+			if !errors.Is(err, ErrInvalidRole) {
+				t.Fatalf("SetRole(%q) = %v, want ErrInvalidRole", role, err)
+			}
+			if len(f.rec.calls) != 0 {
+				t.Errorf("SetRole(%q) made calls %v, want none", role, f.rec.calls)
+			}
+		})
+	}
+}
+
+func TestAdminServiceRecordsChangesBeforeMakingThem(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  func(f *adminFixture) error
+		calls   []string
+		details map[string]string
+	}{
+		{
+			name: "SetRole",
+			action: func(f *adminFixture) error {
+				_, err := f.service.SetRole(f.admin.ID.Hex(), f.target.ID.Hex(), models.RoleAdmin)
+				return err
+			},
+			calls:   []string{"audit.Create:pending", "users.SetRole", "audit.SetStatus:completed"},
+			details: map[string]string{"role": models.RoleAdmin},
+		},
+		{
+			name: "DisableUser",
+			action: func(f *adminFixture) error {
+				_, err := f.service.DisableUser(f.admin.ID.Hex(), f.target.ID.Hex())
+				return err
+			},
+			calls: []string{"audit.Create:pending", "users.SetDisabled", "auth.LogoutAll", "audit.SetStatus:completed"},
+		},
+		{
+			name: "ForcePasswordReset",
+			action: func(f *adminFixture) error {
+				return f.service.ForcePasswordReset(f.admin.ID.Hex(), f.target.ID.Hex())
+			},
+			calls: []string{"audit.Create:pending", "auth.RequirePasswordReset", "audit.SetStatus:completed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAdminFixture()
+			if err := tt.action(f); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(f.rec.calls, tt.calls) {
+				t.Errorf("calls = %v, want %v", f.rec.calls, tt.calls)
+			}
+			if len(f.audit.entries) != 1 {
+				t.Fatalf("wrote %d audit entries, want 1", len(f.audit.entries))
+			}
+			entry := f.audit.entries[0]
+			if entry.ActorID != f.admin.ID || entry.TargetUserID == nil || *entry.TargetUserID != f.target.ID {
+				t.Errorf("entry actor/target = %s/%v, want %s/%s", entry.ActorID.Hex(), entry.TargetUserID, f.admin.ID.Hex(), f.target.ID.Hex())
+			}
+			if entry.Status != models.AuditCompleted || !reflect.DeepEqual(entry.Details, tt.details) {
+				t.Errorf("entry status/details = %s/%v, want completed/%v", entry.Status, entry.Details, tt.details)
+			}
+		})
+	}
+}
+
+func TestAdminServiceChangesNothingWithoutAuditEntry(t *testing.T) {
+	f := newAdminFixture()
+	f.rec.fail["audit.Create:pending"] = errors.New("audit log unavailable")
+	if _, err := f.service.SetRole(f.admin.ID.Hex(), f.target.ID.Hex(), models.RoleAdmin); err == nil {
+		t.Fatal("SetRole succeeded without an audit entry")
+	}
+	if f.users.users[f.target.ID].Role != models.RoleUser {
+		t.Error("role changed without an audit entry")
+	}
+	if err := f.service.DeleteUser(f.admin.ID.Hex(), f.target.ID.Hex()); err == nil {
+		t.Fatal("DeleteUser succeeded without an audit entry")
+	}
+	if got := strings.Join(f.rec.calls, ","); got != "audit.Create:pending,audit.Create:pending" {
+		t.Errorf("calls = %s, want only the failed audit writes", got)
+	}
+}
+
+func TestAdminServiceMarksFailedChanges(t *testing.T) {
+	f := newAdminFixture()
+	f.rec.fail["users.SetRole"] = errors.New("write failed")
+	if _, err := f.service.SetRole(f.admin.ID.Hex(), f.target.ID.Hex(), models.RoleAdmin); err == nil {
+		t.Fatal("SetRole succeeded although the write failed")
+	}
+	if len(f.audit.entries) != 1 || f.audit.entries[0].Status != models.AuditFailed {
+		t.Errorf("audit entries = %+v, want one failed entry", f.audit.entries)
+	}
+}
+
+func TestAdminServiceDeleteUserCanBeRetried(t *testing.T) {
+	f := newAdminFixture()
+	f.rec.fail["projects.DeleteByUser"] = errors.New("delete failed")
+	if err := f.service.DeleteUser(f.admin.ID.Hex(), f.target.ID.Hex()); err == nil {
+		t.Fatal("DeleteUser succeeded although a delete failed")
+	}
+	// The account is locked before any data is deleted and still exists, so
+	// the deletion can be repeated.
+	wantCalls := []string{"audit.Create:pending", "users.SetDisabled", "auth.LogoutAll", "todos.DeleteByUser", "labels.DeleteByUser", "projects.DeleteByUser", "audit.SetStatus:failed"}
+	if !reflect.DeepEqual(f.rec.calls, wantCalls) {
+		t.Errorf("calls = %v, want %v", f.rec.calls, wantCalls)
+	}
+	user, ok := f.users.users[f.target.ID]
+	if !ok || !user.Disabled {
+		t.Fatalf("user after failed deletion = %+v, want a disabled account", user)
+	}
+
+	delete(f.rec.fail, "projects.DeleteByUser")
+	f.rec.calls = nil
+	if err := f.service.DeleteUser(f.admin.ID.Hex(), f.target.ID.Hex()); err != nil {
+		t.Fatalf("retried DeleteUser: %v", err)
+	}
+	if _, ok := f.users.users[f.target.ID]; ok {
+		t.Error("user still exists after the retried deletion")
+	}
+	wantCalls = []string{"audit.Create:pending", "users.SetDisabled", "auth.LogoutAll", "todos.DeleteByUser", "labels.DeleteByUser", "projects.DeleteByUser", "smartLists.DeleteByUser", "refreshTokens.DeleteByUser", "accessTokens.DeleteByUser", "passwordResets.DeleteByUser", "revocations.DeleteByUser", "users.Delete", "audit.SetStatus:completed"}
+	if !reflect.DeepEqual(f.rec.calls, wantCalls) {
+		t.Errorf("retried calls = %v, want every user document deleted and the user last", f.rec.calls)
+	}
+
+	if len(f.audit.entries) != 2 {
+		t.Fatalf("wrote %d audit entries, want one per attempt", len(f.audit.entries))
+	}
+	for i, want := range []string{models.AuditFailed, models.AuditCompleted} {
+		entry := f.audit.entries[i]
+		if entry.Action != models.AuditDeleteUser || entry.Status != want || entry.Details["email"] != f.target.Email {
+			t.Errorf("entry %d = %+v, want a %s delete_user entry with the email", i, entry, want)
+		}
+	}
+}
+
+func TestAdminServiceAuditsReads(t *testing.T) {
+	f := newAdminFixture()
+	if _, err := f.service.GetUser(f.admin.ID.Hex(), f.target.ID.Hex()); err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if len(f.audit.entries) != 1 {
+		t.Fatalf("wrote %d audit entries, want 1", len(f.audit.entries))
+	}
+	if entry := f.audit.entries[0]; entry.Action != models.AuditViewUser || entry.Status != models.AuditCompleted {
+		t.Errorf("entry = %+v, want a completed view_user entry", entry)
+	}
+
+	f.rec.fail["audit.Create:completed"] = errors.New("audit log unavailable")
+	if _, err := f.service.GetUser(f.admin.ID.Hex(), f.target.ID.Hex()); err == nil {
+		t.Error("GetUser returned the user without an audit entry")
+	}
+}
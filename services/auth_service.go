@@ -3,89 +3,315 @@ package services
 import (
 	"errors"
 	"log"
-	"os"
 	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/mailer"
 	"todo-list-api/models"
 	"todo-list-api/repository"
 
-	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
 
-//var jwtSecret = []byte("your_secret_key") // default secret; override via env var
+// ErrUserExists is returned when registering an email that is already in use.
+var ErrUserExists = apperrors.Conflict("user_exists", "user already exists")
+
+// ErrInvalidCredentials is returned when the email or password is wrong. It
+// does not say which, so accounts cannot be enumerated.
+var ErrInvalidCredentials = apperrors.Unauthorized("invalid_credentials", "invalid email or password")
+
+// ErrWrongPassword is returned when changing the password with a wrong current password.
+var ErrWrongPassword = apperrors.Forbidden("wrong_password", "current password is incorrect")
+
+// ErrAccountDisabled is returned when a disabled user logs in or uses a token.
+var ErrAccountDisabled = apperrors.Forbidden("account_disabled", "this account has been disabled")
+
+// ErrPasswordResetRequired is returned when a user whose password reset was
+// forced by an admin logs in before setting a new password.
+var ErrPasswordResetRequired = apperrors.Forbidden("password_reset_required", "a password reset is required; use the link sent to your email")
 
 // AuthService handles authentication business logic.
 type AuthService interface {
-	Register(user *models.User) (string, error)
-	Login(email, password string) (string, error)
+	Register(user *models.User) (*models.TokenPair, error)
+	// Login checks the password. For users with MFA enabled it returns a
+	// challenge token for LoginMFA instead of a token pair.
+	Login(email, password string) (*models.LoginResult, error)
+	// LoginMFA completes a login with a TOTP or recovery code.
+	LoginMFA(mfaToken, code string) (*models.TokenPair, error)
+	// Refresh exchanges a refresh token for a new token pair. Each refresh
+	// token can be used once.
+	Refresh(refreshToken string) (*models.TokenPair, error)
+	// Logout revokes the access token with the given jti and, if given, the
+	// refresh token family it belongs to.
+	Logout(userID, tokenID string, expiresAt time.Time, refreshToken string) error
+	// LogoutAll revokes every access, refresh and personal access token of the user.
+	LogoutAll(userID string) error
+	// ChangePassword replaces the user's password and revokes all their tokens.
+	ChangePassword(userID, currentPassword, newPassword string) error
+	// ForgotPassword emails a password reset link if a user has the email.
+	// It succeeds whether or not the email is registered.
+	ForgotPassword(email string) error
+	// ResetPassword sets a new password using a reset token and revokes all
+	// of the user's tokens.
+	ResetPassword(token, newPassword string) error
+	// RequirePasswordReset stops the user from logging in until they reset
+	// their password, revokes all their tokens and emails them a reset link.
+	RequirePasswordReset(userID string) error
+	// VerifyEmail marks the user's email as verified using the token from a
+	// verification email.
+	VerifyEmail(token string) error
+	// ResendVerification sends a new verification email to the user.
+	ResendVerification(userID string) error
+	// GetUser returns the user a token was issued to.
+	GetUser(userID string) (*models.User, error)
+	// EnrollMFA starts enabling MFA by creating a TOTP secret; it takes effect
+	// once confirmed.
+	EnrollMFA(userID string) (*models.MFAEnrollment, error)
+	// ConfirmMFA enables MFA after checking a first code from the
+	// authenticator, and returns the recovery codes.
+	ConfirmMFA(userID, code string) ([]string, error)
+	// DisableMFA turns MFA off; it requires a current code or the password.
+	DisableMFA(userID, code, password string) error
 }
 
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo          repository.UserRepository
+	projectRepo       repository.ProjectRepository
+	refreshTokenRepo  repository.RefreshTokenRepository
+	accessTokenRepo   repository.PersonalAccessTokenRepository
+	passwordResetRepo repository.PasswordResetRepository
+	revocations       RevocationService
+	mailer            mailer.Mailer
+	tokenConfig       TokenConfig
 }
 
 // NewAuthService returns a new instance of AuthService.
-func NewAuthService(userRepo repository.UserRepository) AuthService {
-	return &authService{userRepo}
+func NewAuthService(userRepo repository.UserRepository, projectRepo repository.ProjectRepository, refreshTokenRepo repository.RefreshTokenRepository, accessTokenRepo repository.PersonalAccessTokenRepository, passwordResetRepo repository.PasswordResetRepository, revocations RevocationService, sender mailer.Mailer, tokenConfig TokenConfig) AuthService {
+	return &authService{userRepo, projectRepo, refreshTokenRepo, accessTokenRepo, passwordResetRepo, revocations, sender, tokenConfig}
 }
 
-// Register creates a user, hashes the password, and returns a JWT token.
-func (s *authService) Register(user *models.User) (string, error) {
+// Register creates a user, hashes the password, and returns a token pair.
+func (s *authService) Register(user *models.User) (*models.TokenPair, error) {
 	// Check if the user already exists.
-	existingUser, _ := s.userRepo.FindByEmail(user.Email)
+	existingUser, err := s.userRepo.FindByEmail(user.Email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, err
+	}
 	if existingUser != nil {
-		return "", errors.New("user already exists")
+		return nil, ErrUserExists
+	}
+
+	// Built-in smart lists resolve "today" in this timezone.
+	if _, err := LoadLocation(user.Timezone); err != nil {
+		return nil, err
 	}
 
 	// Hash the password.
 	hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	user.Password = string(hashed)
+	user.Role = models.RoleUser
 	//log.Printf("Hashed password for user %s: %s", user.Email, user.Password)
 
 	// Store the user in the database.
 	if err := s.userRepo.Create(user); err != nil {
-		return "", err
+		return nil, err
+	}
+
+	// Every user starts with an inbox project.
+	if _, err := ensureInbox(s.projectRepo, user.ID); err != nil {
+		return nil, err
 	}
 
-	// Generate a JWT token.
-	return generateToken(user.ID.Hex())
+	s.sendVerificationAsync(user)
+
+	return s.issueTokens(user, primitive.NewObjectID())
 }
 
-// Login verifies the user credentials and returns a JWT token.
-func (s *authService) Login(email, password string) (string, error) {
+// Login verifies the user credentials and returns a token pair that starts a
+// new refresh token family, or an MFA challenge.
+func (s *authService) Login(email, password string) (*models.LoginResult, error) {
 	user, err := s.userRepo.FindByEmail(email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		log.Printf("User with email %s not found", email)
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
 		log.Printf("Error finding user by email %s: %v", email, err)
-		return "", errors.New("invalid email or password")
+		return nil, err
 	}
 	// Ensure that a user with the given email exists.
 	if user == nil {
 		log.Printf("User with email %s not found", email)
-		return "", errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
 	}
 	log.Printf("Attempting password comparison for user %s", email)
 	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
 		log.Printf("Password comparison failed for user %s: %v", email, err)
-		return "", errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
+	}
+	if err := checkLoginAllowed(user); err != nil {
+		log.Printf("User %s passed the password check but may not log in: %v", email, err)
+		return nil, err
+	}
+	if user.MFAEnabled {
+		log.Printf("User %s passed the password check, MFA required", email)
+		return s.mfaChallenge(user)
 	}
 	log.Printf("User %s logged in successfully", email)
-	return generateToken(user.ID.Hex())
+	tokens, err := s.issueTokens(user, primitive.NewObjectID())
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{Tokens: tokens}, nil
+}
+
+// Refresh rotates the refresh token: it is marked as used and a new pair in
+// the same family is returned. Presenting a used token revokes the family.
+func (s *authService) Refresh(refreshToken string) (*models.TokenPair, error) {
+	token, err := s.refreshTokenRepo.FindByHash(hashToken(refreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token.RevokedAt != nil || !token.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+	rotated := false
+	if token.RotatedAt == nil {
+		if rotated, err = s.refreshTokenRepo.Rotate(token.ID); err != nil {
+			return nil, err
+		}
+	}
+	if !rotated {
+		log.Printf("Refresh token reuse detected for user %s, revoking family %s", token.UserID.Hex(), token.FamilyID.Hex())
+		if err := s.refreshTokenRepo.RevokeFamily(token.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	user, err := s.userRepo.FindByID(token.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, token.FamilyID)
+}
+
+func (s *authService) Logout(userID, tokenID string, expiresAt time.Time, refreshToken string) error {
+	if tokenID != "" {
+		if err := s.revocations.RevokeToken(tokenID, userID, expiresAt); err != nil {
+			return err
+		}
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	token, err := s.refreshTokenRepo.FindByHash(hashToken(refreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Another user's refresh token is ignored rather than revoked.
+	if token.UserID.Hex() != userID {
+		return nil
+	}
+	return s.refreshTokenRepo.RevokeFamily(token.FamilyID)
+}
+
+func (s *authService) LogoutAll(userID string) error {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.revocations.RevokeUser(userID); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeUser(userObjID); err != nil {
+		return err
+	}
+	// Personal access tokens cannot be revoked by issue time like JWTs, so
+	// they are deleted; a script keeping access after an account recovery
+	// would defeat it.
+	return s.accessTokenRepo.DeleteByUser(userObjID)
+}
+
+func (s *authService) GetUser(userID string) (*models.User, error) {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(userObjID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, ErrInvalidUserID
+	}
+	return user, err
+}
+
+func (s *authService) ChangePassword(userID, currentPassword, newPassword string) error {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return ErrWrongPassword
+	}
+	return s.setPassword(user.ID, newPassword)
+}
+
+// setPassword stores the bcrypt hash of password and revokes every token of
+// the user, since sessions started with the old password must not outlive it.
+func (s *authService) setPassword(userID primitive.ObjectID, password string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(userID, string(hashed)); err != nil {
+		return err
+	}
+	return s.LogoutAll(userID.Hex())
 }
 
-// generateToken creates a JWT token that expires in 72 hours.
-func generateToken(userID string) (string, error) {
-	// Use secret from environment if present.
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "your_secret_key"
+// checkLoginAllowed reports why the user may not get new tokens, if there is
+// a reason.
+func checkLoginAllowed(user *models.User) error {
+	if user.Disabled {
+		return ErrAccountDisabled
 	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	return nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(72 * time.Hour).Unix(),
-	})
-	return token.SignedString([]byte(secret))
+// issueTokens creates an access token with the scopes of the user's role and
+// a refresh token in the given family.
+func (s *authService) issueTokens(user *models.User, familyID primitive.ObjectID) (*models.TokenPair, error) {
+	if err := checkLoginAllowed(user); err != nil {
+		return nil, err
+	}
+	accessToken, err := generateAccessToken(user.ID.Hex(), models.ScopesForRole(user.Role), s.tokenConfig.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, record, err := newRefreshToken(user.ID, familyID, s.tokenConfig.RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.Create(record); err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.tokenConfig.AccessTokenTTL,
+	}, nil
 }
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed, was not
+// issued by this server or belongs to a listing with a different sort order.
+var ErrInvalidCursor = apperrors.Validation("invalid_cursor", "invalid cursor")
+
+// cursorPurpose separates the signing key of pagination cursors.
+const cursorPurpose = "cursor"
+
+// encodeCursor turns a cursor into an opaque signed token. A nil cursor
+// encodes to "".
+func encodeCursor(cursor *models.TodoCursor) (string, error) {
+	if cursor == nil {
+		return "", nil
+	}
+	return signToken(cursorPurpose, cursor)
+}
+
+// decodeCursor verifies and decodes a token produced by encodeCursor. The
+// signature keeps clients from forging positions or injecting query values.
+func decodeCursor(token string) (*models.TodoCursor, error) {
+	var cursor models.TodoCursor
+	if !parseSignedToken(cursorPurpose, token, &cursor) {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
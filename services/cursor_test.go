@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	cursor := &models.TodoCursor{
+		Sort:   models.TodoSort{Field: models.SortByTitle, Descending: true},
+		Value:  "Pay rent",
+		ID:     primitive.NewObjectID(),
+		Before: true,
+	}
+	token, err := encodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+	got, err := decodeCursor(token)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if got.Sort != cursor.Sort || got.Value != cursor.Value || got.ID != cursor.ID || got.Before != cursor.Before {
+		t.Errorf("decodeCursor = %+v, want %+v", got, cursor)
+	}
+
+	if token, err := encodeCursor(nil); err != nil || token != "" {
+		t.Errorf("encodeCursor(nil) = %q, %v; want an empty token", token, err)
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	cursor := &models.TodoCursor{Sort: models.TodoSort{Field: models.SortByCreatedAt}, ID: primitive.NewObjectID()}
+	token, err := encodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+	payload, sig, _ := strings.Cut(token, ".")
+
+	other, err := encodeCursor(&models.TodoCursor{Sort: models.TodoSort{Field: models.SortByCreatedAt}, ID: primitive.NewObjectID()})
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+	otherPayload, _, _ := strings.Cut(other, ".")
+
+	// A token signed for another purpose must not be accepted as a cursor.
+	foreign, err := signToken("verification", cursor)
+	if err != nil {
+		t.Fatalf("signToken: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"empty signature", payload + "."},
+		{"flipped payload byte", flip(payload) + "." + sig},
+		{"flipped signature byte", payload + "." + flip(sig)},
+		{"swapped payload", otherPayload + "." + sig},
+		{"invalid base64", payload + "!." + sig},
+		{"extra segment", token + ".x"},
+		{"other purpose", foreign},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.token)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) = %+v, %v; want ErrInvalidCursor", tt.token, got, err)
+			}
+		})
+	}
+}
+
+func TestGetTodosRejectsCursorFromOtherSort(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	token, err := encodeCursor(&models.TodoCursor{Sort: models.TodoSort{Field: models.SortByTitle}, ID: primitive.NewObjectID()})
+	if err != nil {
+		t.Fatalf("encodeCursor: %v", err)
+	}
+	s := &todoService{}
+	filter := models.TodoFilter{Sort: models.TodoSort{Field: models.SortByTitle, Descending: true}}
+	_, err = s.GetTodos(primitive.NewObjectID().Hex(), filter, models.PageRequest{Keyset: true, Cursor: token, Limit: 10})
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("GetTodos with a cursor from another sort order = %v, want ErrInvalidCursor", err)
+	}
+}
+
+// flip changes the middle character of a base64url string to another valid one.
+func flip(s string) string {
+	b := []byte(s)
+	i := len(b) / 2
+	if b[i] == 'A' {
+		b[i] = 'B'
+	} else {
+		b[i] = 'A'
+	}
+	return string(b)
+}
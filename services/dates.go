@@ -0,0 +1,52 @@
+package services
+
+import (
+	"time"
+	"todo-list-api/apperrors"
+)
+
+// ErrInvalidTimezone is returned when a timezone is not a known IANA location.
+var ErrInvalidTimezone = apperrors.Validation("invalid_timezone", "invalid timezone")
+
+// ErrInvalidDate is returned when a date or timestamp cannot be parsed.
+var ErrInvalidDate = apperrors.Validation("invalid_date", "invalid date, expected RFC 3339 timestamp or YYYY-MM-DD")
+
+// LoadLocation resolves an IANA timezone name, defaulting to UTC when empty.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// ParseDateTime parses an RFC 3339 timestamp or a plain date. Timestamps keep
+// their own offset; plain dates and timestamps without an offset are
+// interpreted in loc.
+func ParseDateTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidDate
+}
+
+// startOfDay returns midnight of t's calendar day in loc.
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// startOfWeek returns midnight of the Monday starting t's week in loc.
+func startOfWeek(t time.Time, loc *time.Location) time.Time {
+	day := startOfDay(t, loc)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
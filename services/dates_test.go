@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2026-03-01T09:30:00Z", time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{"2026-03-01T09:30:00+05:00", time.Date(2026, 3, 1, 4, 30, 0, 0, time.UTC)},
+		{"2026-03-01T09:30:00", time.Date(2026, 3, 1, 9, 30, 0, 0, berlin)},
+		{"2026-03-01T09:30", time.Date(2026, 3, 1, 9, 30, 0, 0, berlin)},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDateTime(tt.value, berlin)
+			if err != nil {
+				t.Fatalf("ParseDateTime(%q) error = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDateTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "tomorrow", "01/03/2026", "2026-13-01"} {
+		if _, err := ParseDateTime(value, berlin); !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("ParseDateTime(%q) error = %v, want ErrInvalidDate", value, err)
+		}
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	if loc, err := LoadLocation(""); err != nil || loc != time.UTC {
+		t.Errorf("LoadLocation(\"\") = %v, %v; want UTC", loc, err)
+	}
+	if _, err := LoadLocation("Mars/Olympus_Mons"); !errors.Is(err, ErrInvalidTimezone) {
+		t.Errorf("LoadLocation(unknown) error = %v, want ErrInvalidTimezone", err)
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},   // Monday
+		{time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},  // Wednesday
+		{time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}, // Sunday
+	}
+	for _, tt := range tests {
+		if got := startOfWeek(tt.now, time.UTC); !got.Equal(tt.want) {
+			t.Errorf("startOfWeek(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/mailer"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidVerificationToken is returned when an email verification token
+// is malformed, forged, expired or for an address the user no longer has.
+var ErrInvalidVerificationToken = apperrors.Validation("invalid_verification_token", "invalid or expired email verification token")
+
+// ErrEmailAlreadyVerified is returned when asking for a verification email
+// for an address that is already verified.
+var ErrEmailAlreadyVerified = apperrors.Conflict("email_already_verified", "email address is already verified")
+
+// verificationClaims is the signed payload of an email verification token.
+// The email is included so that a link stops working if the address changes.
+type verificationClaims struct {
+	UserID    primitive.ObjectID `bson:"u"`
+	Email     string             `bson:"e"`
+	ExpiresAt int64              `bson:"x"`
+}
+
+// verificationPurpose separates the signing key of verification tokens.
+const verificationPurpose = "email-verification"
+
+// decodeVerificationToken verifies a verification token and checks that it
+// has not expired.
+func decodeVerificationToken(token string) (*verificationClaims, error) {
+	var claims verificationClaims
+	if !parseSignedToken(verificationPurpose, token, &claims) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidVerificationToken
+	}
+	return &claims, nil
+}
+
+func (s *authService) VerifyEmail(token string) error {
+	claims, err := decodeVerificationToken(token)
+	if err != nil {
+		return err
+	}
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return err
+	}
+	if user.Email != claims.Email {
+		return ErrInvalidVerificationToken
+	}
+	// Opening the link twice is harmless.
+	if user.EmailVerified {
+		return nil
+	}
+	return s.userRepo.MarkEmailVerified(user.ID, time.Now())
+}
+
+func (s *authService) ResendVerification(userID string) error {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+	return s.sendVerification(user)
+}
+
+// sendVerificationAsync sends the verification email in the background and
+// logs failures; registration succeeds even if the email cannot be sent.
+func (s *authService) sendVerificationAsync(user *models.User) {
+	go func() {
+		if err := s.sendVerification(user); err != nil {
+			log.Printf("Failed to send verification email to user %s: %v", user.ID.Hex(), err)
+		}
+	}()
+}
+
+func (s *authService) sendVerification(user *models.User) error {
+	expiresAt := time.Now().Add(s.tokenConfig.EmailVerificationTTL)
+	token, err := signToken(verificationPurpose, verificationClaims{UserID: user.ID, Email: user.Email, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return err
+	}
+	link, err := linkWithToken(s.tokenConfig.EmailVerificationURL, token)
+	if err != nil {
+		return err
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", displayName(user))
+	body.WriteString("Please confirm your email address by opening this link:\n\n")
+	fmt.Fprintf(&body, "%s\n\n", link)
+	fmt.Fprintf(&body, "The link expires at %s.\n\n", expiresAt.UTC().Format(time.RFC1123))
+	body.WriteString("If you did not create an account, ignore this email.\n")
+	return s.mailer.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    body.String(),
+	})
+}
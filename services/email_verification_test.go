@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeVerifyUsers stores one user.
+type fakeVerifyUsers struct {
+	repository.UserRepository
+	user models.User
+}
+
+func (r *fakeVerifyUsers) FindByID(id primitive.ObjectID) (*models.User, error) {
+	if id != r.user.ID {
+		return nil, repository.ErrUserNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *fakeVerifyUsers) MarkEmailVerified(id primitive.ObjectID, at time.Time) error {
+	r.user.EmailVerified = true
+	r.user.VerifiedAt = &at
+	return nil
+}
+
+func TestVerifyEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	user := models.User{ID: primitive.NewObjectID(), Email: "john@doe.com"}
+	sign := func(claims verificationClaims) string {
+		token, err := signToken(verificationPurpose, claims)
+		if err != nil {
+			t.Fatalf("signToken() error = %v", err)
+		}
+		return token
+	}
+	valid := sign(verificationClaims{UserID: user.ID, Email: user.Email, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	cursor, err := signToken("cursor", verificationClaims{UserID: user.ID, Email: user.Email, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("signToken() error = %v", err)
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", sign(verificationClaims{UserID: user.ID, Email: user.Email, ExpiresAt: time.Now().Add(-time.Minute).Unix()})},
+		{"old email", sign(verificationClaims{UserID: user.ID, Email: "old@doe.com", ExpiresAt: time.Now().Add(time.Hour).Unix()})},
+		{"unknown user", sign(verificationClaims{UserID: primitive.NewObjectID(), Email: user.Email, ExpiresAt: time.Now().Add(time.Hour).Unix()})},
+		{"tampered", valid[:len(valid)-2] + "xx"},
+		{"other purpose", cursor},
+	}
+	users := &fakeVerifyUsers{user: user}
+	s := &authService{userRepo: users}
+	for _, tt := range tests {
+		if err := s.VerifyEmail(tt.token); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Errorf("VerifyEmail(%s) error = %v, want ErrInvalidVerificationToken", tt.name, err)
+		}
+	}
+	if users.user.EmailVerified {
+		t.Fatal("an invalid token verified the email")
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.VerifyEmail(valid); err != nil {
+			t.Fatalf("VerifyEmail(valid) attempt %d error = %v", i+1, err)
+		}
+	}
+	if !users.user.EmailVerified || users.user.VerifiedAt == nil {
+		t.Errorf("user after verification = %+v, want the email verified", users.user)
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"todo-list-api/apperrors"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Errors for resources that do not exist or belong to another user.
+var (
+	ErrTodoNotFound        = repository.ErrTodoNotFound
+	ErrLabelNotFound       = repository.ErrLabelNotFound
+	ErrProjectNotFound     = repository.ErrProjectNotFound
+	ErrSmartListNotFound   = repository.ErrSmartListNotFound
+	ErrAccessTokenNotFound = repository.ErrAccessTokenNotFound
+	ErrUserNotFound        = repository.ErrUserNotFound
+)
+
+// ErrInvalidUserID is returned when the authenticated user id is not a valid
+// ObjectID, which means the token was not issued by this API.
+var ErrInvalidUserID = apperrors.Unauthorized("invalid_token", "token does not identify a user")
+
+// ParseID parses a resource id taken from a request. A malformed id cannot
+// match any document, so it is reported as notFound.
+func ParseID(id string, notFound error) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, notFound
+	}
+	return objID, nil
+}
+
+// ParseUserID parses the id of the authenticated user.
+func ParseUserID(userID string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidUserID
+	}
+	return objID, nil
+}
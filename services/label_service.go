@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"todo-list-api/query"
+	"todo-list-api/repository"
+)
+
+// ErrInvalidLabelName is returned when a label name is empty or contains a comma.
+var ErrInvalidLabelName = apperrors.Validation("invalid_label_name", "label name must be non-empty and must not contain commas")
+
+// ErrInvalidLabelColor is returned when a label color is not a #rrggbb hex value.
+var ErrInvalidLabelColor = apperrors.Validation("invalid_label_color", "label color must be a hex value such as #1e90ff")
+
+// ErrLabelExists is returned when the user already has a label with the same name.
+var ErrLabelExists = apperrors.Conflict("label_exists", "label already exists")
+
+// ErrUnknownLabel is returned when a to-do item references a label that is not in the user's catalogue.
+var ErrUnknownLabel = apperrors.Validation("unknown_label", "unknown label")
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// LabelService is the business logic layer for managing a user's labels.
+type LabelService interface {
+	CreateLabel(label *models.Label) error
+	UpdateLabel(label *models.Label) error
+	DeleteLabel(id string, userID string) error
+	GetLabels(userID string) ([]models.Label, error)
+	GetLabelByID(id string, userID string) (*models.Label, error)
+}
+
+type labelService struct {
+	labelRepo     repository.LabelRepository
+	todoRepo      repository.TodoRepository
+	smartListRepo repository.SmartListRepository
+}
+
+// NewLabelService returns a new instance of LabelService.
+func NewLabelService(labelRepo repository.LabelRepository, todoRepo repository.TodoRepository, smartListRepo repository.SmartListRepository) LabelService {
+	return &labelService{labelRepo, todoRepo, smartListRepo}
+}
+
+func (s *labelService) CreateLabel(label *models.Label) error {
+	if err := normalizeLabel(label); err != nil {
+		return err
+	}
+	if err := s.ensureUniqueName(label); err != nil {
+		return err
+	}
+	// A concurrent request may have created the same label.
+	if err := s.labelRepo.Create(label); errors.Is(err, repository.ErrDuplicate) {
+		return ErrLabelExists
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateLabel saves the label and, when it was renamed, renames it on every
+// todo and in every smart list filter and filter expression that uses it.
+func (s *labelService) UpdateLabel(label *models.Label) error {
+	if err := normalizeLabel(label); err != nil {
+		return err
+	}
+	existing, err := s.labelRepo.GetByID(label.ID, label.UserID)
+	if err != nil {
+		return err
+	}
+	if existing.Name != label.Name {
+		if err := s.ensureUniqueName(label); err != nil {
+			return err
+		}
+	}
+	label.CreatedAt = existing.CreatedAt
+	if err := s.labelRepo.Update(label); errors.Is(err, repository.ErrDuplicate) {
+		return ErrLabelExists
+	} else if err != nil {
+		return err
+	}
+	if existing.Name != label.Name {
+		if err := s.todoRepo.RenameLabel(label.UserID, existing.Name, label.Name); err != nil {
+			return err
+		}
+		if err := s.smartListRepo.RenameLabel(label.UserID, existing.Name, label.Name); err != nil {
+			return err
+		}
+		return rewriteSmartListQueries(s.smartListRepo, label.UserID, func(c *query.Comparison) bool {
+			if c.Key() == "labels" && c.Value == existing.Name {
+				c.Value = label.Name
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// DeleteLabel removes the label from the catalogue and from every todo and
+// smart list filter that uses it. Comparisons with the label are dropped from
+// smart list filter expressions.
+func (s *labelService) DeleteLabel(id string, userID string) error {
+	labelID, err := ParseID(id, ErrLabelNotFound)
+	if err != nil {
+		return err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return err
+	}
+	label, err := s.labelRepo.GetByID(labelID, userObjID)
+	if err != nil {
+		return err
+	}
+	if err := s.labelRepo.Delete(labelID, userObjID); err != nil {
+		return err
+	}
+	if err := s.todoRepo.RemoveLabel(userObjID, label.Name); err != nil {
+		return err
+	}
+	if err := s.smartListRepo.RemoveLabel(userObjID, label.Name); err != nil {
+		return err
+	}
+	return rewriteSmartListQueries(s.smartListRepo, userObjID, func(c *query.Comparison) bool {
+		return c.Key() != "labels" || c.Value != label.Name
+	})
+}
+
+func (s *labelService) GetLabels(userID string) ([]models.Label, error) {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.labelRepo.GetLabels(userObjID)
+}
+
+func (s *labelService) GetLabelByID(id string, userID string) (*models.Label, error) {
+	labelID, err := ParseID(id, ErrLabelNotFound)
+	if err != nil {
+		return nil, err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.labelRepo.GetByID(labelID, userObjID)
+}
+
+func (s *labelService) ensureUniqueName(label *models.Label) error {
+	existing, err := s.labelRepo.FindByNames(label.UserID, []string{label.Name})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return ErrLabelExists
+	}
+	return nil
+}
+
+// normalizeLabel trims the label name and validates its name and color.
+func normalizeLabel(label *models.Label) error {
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" || strings.Contains(label.Name, ",") {
+		return ErrInvalidLabelName
+	}
+	if label.Color != "" && !labelColorPattern.MatchString(label.Color) {
+		return ErrInvalidLabelColor
+	}
+	label.Color = strings.ToLower(label.Color)
+	return nil
+}
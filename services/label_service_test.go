@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeLabels keeps a user's label catalogue in memory.
+type fakeLabels struct {
+	repository.LabelRepository
+	labels []models.Label
+}
+
+func (r *fakeLabels) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Label, error) {
+	for _, label := range r.labels {
+		if label.ID == id && label.UserID == userID {
+			return &label, nil
+		}
+	}
+	return nil, repository.ErrLabelNotFound
+}
+
+func (r *fakeLabels) FindByNames(userID primitive.ObjectID, names []string) ([]models.Label, error) {
+	var found []models.Label
+	for _, label := range r.labels {
+		for _, name := range names {
+			if label.UserID == userID && label.Name == name {
+				found = append(found, label)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeLabels) Create(label *models.Label) error {
+	r.labels = append(r.labels, *label)
+	return nil
+}
+
+func (r *fakeLabels) Update(label *models.Label) error {
+	for i := range r.labels {
+		if r.labels[i].ID == label.ID {
+			r.labels[i] = *label
+		}
+	}
+	return nil
+}
+
+func (r *fakeLabels) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
+	for i := range r.labels {
+		if r.labels[i].ID == id {
+			r.labels = append(r.labels[:i], r.labels[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrLabelNotFound
+}
+
+// fakeLabelTodos records the label changes cascaded to a user's todos.
+type fakeLabelTodos struct {
+	repository.TodoRepository
+	renamed [][2]string
+	removed []string
+}
+
+func (r *fakeLabelTodos) RenameLabel(userID primitive.ObjectID, oldName, newName string) error {
+	r.renamed = append(r.renamed, [2]string{oldName, newName})
+	return nil
+}
+
+func (r *fakeLabelTodos) RemoveLabel(userID primitive.ObjectID, name string) error {
+	r.removed = append(r.removed, name)
+	return nil
+}
+
+func TestNormalizeLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		label     models.Label
+		wantName  string
+		wantColor string
+		wantErr   error
+	}{
+		{name: "trimmed", label: models.Label{Name: "  work "}, wantName: "work"},
+		{name: "color lowercased", label: models.Label{Name: "home", Color: "#1E90FF"}, wantName: "home", wantColor: "#1e90ff"},
+		{name: "blank", label: models.Label{Name: "   "}, wantErr: ErrInvalidLabelName},
+		{name: "comma", label: models.Label{Name: "a,b"}, wantErr: ErrInvalidLabelName},
+		{name: "short color", label: models.Label{Name: "x", Color: "#fff"}, wantErr: ErrInvalidLabelColor},
+		{name: "named color", label: models.Label{Name: "x", Color: "red"}, wantErr: ErrInvalidLabelColor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label := tt.label
+			err := normalizeLabel(&label)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeLabel() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (label.Name != tt.wantName || label.Color != tt.wantColor) {
+				t.Errorf("normalizeLabel() = %q %q, want %q %q", label.Name, label.Color, tt.wantName, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestLabelServiceCascadesToTodos(t *testing.T) {
+	userID := primitive.NewObjectID()
+	work := models.Label{ID: primitive.NewObjectID(), Name: "work", UserID: userID}
+	home := models.Label{ID: primitive.NewObjectID(), Name: "home", UserID: userID}
+	labels := &fakeLabels{labels: []models.Label{work, home}}
+	todos := &fakeLabelTodos{}
+	s := &labelService{labelRepo: labels, todoRepo: todos, smartListRepo: &fakeSmartLists{}}
+
+	recolored := work
+	recolored.Color = "#000000"
+	if err := s.UpdateLabel(&recolored); err != nil {
+		t.Fatalf("UpdateLabel(recolor) error = %v", err)
+	}
+	if len(todos.renamed) != 0 {
+		t.Errorf("recoloring renamed labels on todos: %v", todos.renamed)
+	}
+
+	taken := work
+	taken.Name = "home"
+	if err := s.UpdateLabel(&taken); !errors.Is(err, ErrLabelExists) {
+		t.Errorf("UpdateLabel(duplicate name) error = %v, want ErrLabelExists", err)
+	}
+
+	renamed := work
+	renamed.Name = "office"
+	if err := s.UpdateLabel(&renamed); err != nil {
+		t.Fatalf("UpdateLabel(rename) error = %v", err)
+	}
+	if want := [][2]string{{"work", "office"}}; !reflect.DeepEqual(todos.renamed, want) {
+		t.Errorf("renamed on todos = %v, want %v", todos.renamed, want)
+	}
+
+	if err := s.DeleteLabel(home.ID.Hex(), userID.Hex()); err != nil {
+		t.Fatalf("DeleteLabel() error = %v", err)
+	}
+	if want := []string{"home"}; !reflect.DeepEqual(todos.removed, want) {
+		t.Errorf("removed from todos = %v, want %v", todos.removed, want)
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	userID := primitive.NewObjectID()
+	labels := &fakeLabels{labels: []models.Label{
+		{ID: primitive.NewObjectID(), Name: "work", UserID: userID},
+		{ID: primitive.NewObjectID(), Name: "home", UserID: userID},
+		{ID: primitive.NewObjectID(), Name: "secret", UserID: primitive.NewObjectID()},
+	}}
+	s := &todoService{labelRepo: labels}
+
+	tests := []struct {
+		labels  []string
+		want    []string
+		wantErr error
+	}{
+		{labels: nil, want: nil},
+		{labels: []string{"work"}, want: []string{"work"}},
+		{labels: []string{"work", "home", "work"}, want: []string{"work", "home"}},
+		{labels: []string{"work", "errands"}, wantErr: ErrUnknownLabel},
+		{labels: []string{"secret"}, wantErr: ErrUnknownLabel},
+	}
+	for _, tt := range tests {
+		todo := &models.Todo{UserID: userID, Labels: tt.labels}
+		err := s.validateLabels(todo)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("validateLabels(%v) error = %v, want %v", tt.labels, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(todo.Labels, tt.want) {
+			t.Errorf("validateLabels(%v) labels = %v, want %v", tt.labels, todo.Labels, tt.want)
+		}
+	}
+}
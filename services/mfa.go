@@ -0,0 +1,298 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Errors returned by the MFA endpoints and the second login step.
+var (
+	ErrInvalidMFAToken   = apperrors.Unauthorized("invalid_mfa_token", "invalid or expired MFA challenge; log in again")
+	ErrInvalidMFACode    = apperrors.Unauthorized("invalid_mfa_code", "invalid MFA code")
+	ErrWrongMFACode      = apperrors.Forbidden("wrong_mfa_code", "MFA code is incorrect")
+	ErrMFAAlreadyEnabled = apperrors.Conflict("mfa_already_enabled", "MFA is already enabled")
+	ErrMFANotEnabled     = apperrors.Conflict("mfa_not_enabled", "MFA is not enabled")
+	ErrMFANotEnrolling   = apperrors.Conflict("mfa_not_enrolling", "start an MFA enrollment first")
+	ErrMFALocked         = apperrors.TooManyRequests("too_many_mfa_attempts", "too many wrong MFA codes; try again later")
+)
+
+const (
+	// mfaChallengePurpose separates the signing key of MFA challenge tokens.
+	mfaChallengePurpose = "mfa-challenge"
+	// totpPeriod is the RFC 6238 time step in seconds.
+	totpPeriod = 30
+	// recoveryCodeCount is the number of recovery codes issued when MFA is enabled.
+	recoveryCodeCount = 10
+	qrCodeSize        = 256
+	// maxMFAAttempts is the number of codes that may be tried before MFA
+	// logins and disabling MFA are locked for mfaLockout. The count carries
+	// over between challenges, so logging in again does not grant more
+	// guesses, and is only reset by a successful MFA login.
+	maxMFAAttempts = 5
+	mfaLockout     = 15 * time.Minute
+)
+
+var totpOpts = totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+// mfaChallengeClaims is the signed payload of an MFA challenge token.
+type mfaChallengeClaims struct {
+	UserID      primitive.ObjectID `bson:"u"`
+	ChallengeID string             `bson:"c"`
+	ExpiresAt   int64              `bson:"x"`
+}
+
+func (s *authService) EnrollMFA(userID string) (*models.MFAEnrollment, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.tokenConfig.MFAIssuer,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetMFAPendingSecret(user.ID, key.Secret()); err != nil {
+		return nil, err
+	}
+	return &models.MFAEnrollment{Secret: key.Secret(), URL: key.URL(), QRCode: qr.Bytes()}, nil
+}
+
+func (s *authService) ConfirmMFA(userID, code string) ([]string, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFAPendingSecret == "" {
+		return nil, ErrMFANotEnrolling
+	}
+	step, ok := matchTOTP(user.MFAPendingSecret, normalizeMFACode(code), time.Now())
+	if !ok {
+		return nil, ErrWrongMFACode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.EnableMFA(user.ID, user.MFAPendingSecret, hashes); err != nil {
+		return nil, err
+	}
+	// The confirmation code must not also work for logging in.
+	if _, err := s.userRepo.UseMFAStep(user.ID, step); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *authService) DisableMFA(userID, code, password string) error {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+	// Guessing a code or the password here is limited like MFA logins.
+	attempts, err := s.claimMFAAttempt(user, "", ErrMFANotEnabled)
+	if err != nil {
+		return err
+	}
+	if code != "" {
+		ok, err := s.verifySecondFactor(user, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return s.failMFAAttempt(user.ID, attempts, ErrWrongMFACode)
+		}
+	} else if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return s.failMFAAttempt(user.ID, attempts, ErrWrongPassword)
+	}
+	return s.userRepo.DisableMFA(user.ID)
+}
+
+func (s *authService) LoginMFA(mfaToken, code string) (*models.TokenPair, error) {
+	var claims mfaChallengeClaims
+	if !parseSignedToken(mfaChallengePurpose, mfaToken, &claims) || time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidMFAToken
+	}
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, ErrInvalidMFAToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mfaLocked(user) {
+		return nil, ErrMFALocked
+	}
+	// MFA was disabled, the user logged in again or the challenge was closed
+	// since the password was checked.
+	if !user.MFAEnabled || user.MFAChallengeID == "" || user.MFAChallengeID != claims.ChallengeID {
+		return nil, ErrInvalidMFAToken
+	}
+	attempts, err := s.claimMFAAttempt(user, claims.ChallengeID, ErrInvalidMFAToken)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.verifySecondFactor(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.failMFAAttempt(user.ID, attempts, ErrInvalidMFACode)
+	}
+	// Another request may have completed or closed the challenge meanwhile.
+	ended, err := s.userRepo.EndMFAChallenge(user.ID, claims.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, ErrInvalidMFAToken
+	}
+	return s.issueTokens(user, primitive.NewObjectID())
+}
+
+// claimMFAAttempt counts an attempt at the user's second factor before it is
+// checked and returns the number of attempts so far. It fails with
+// ErrMFALocked when no attempts are left, and with notClaimed when the
+// attempt could not be claimed for another reason, such as a closed challenge.
+func (s *authService) claimMFAAttempt(user *models.User, challengeID string, notClaimed error) (int, error) {
+	if mfaLocked(user) {
+		return 0, ErrMFALocked
+	}
+	attempts, claimed, err := s.userRepo.ClaimMFAAttempt(user.ID, challengeID, maxMFAAttempts)
+	if err != nil {
+		return 0, err
+	}
+	if claimed {
+		return attempts, nil
+	}
+	current, err := s.userRepo.FindByID(user.ID)
+	if err != nil {
+		return 0, err
+	}
+	if mfaLocked(current) || current.MFAFailedAttempts >= maxMFAAttempts {
+		return 0, ErrMFALocked
+	}
+	return 0, notClaimed
+}
+
+// failMFAAttempt returns err for a wrong code or password, first locking MFA
+// for mfaLockout if it was the last attempt allowed.
+func (s *authService) failMFAAttempt(userID primitive.ObjectID, attempts int, err error) error {
+	if attempts < maxMFAAttempts {
+		return err
+	}
+	if err := s.userRepo.LockMFA(userID, time.Now().Add(mfaLockout)); err != nil {
+		return err
+	}
+	return ErrMFALocked
+}
+
+// mfaLocked reports whether the user's MFA logins are locked after too many wrong codes.
+func mfaLocked(user *models.User) bool {
+	return user.MFALockedUntil != nil && time.Now().Before(*user.MFALockedUntil)
+}
+
+// mfaChallenge returns the login result for a user with MFA enabled whose
+// password was correct. The new challenge replaces any open one.
+func (s *authService) mfaChallenge(user *models.User) (*models.LoginResult, error) {
+	if mfaLocked(user) {
+		return nil, ErrMFALocked
+	}
+	challengeID := primitive.NewObjectID().Hex()
+	if err := s.userRepo.StartMFAChallenge(user.ID, challengeID); err != nil {
+		return nil, err
+	}
+	token, err := signToken(mfaChallengePurpose, mfaChallengeClaims{
+		UserID:      user.ID,
+		ChallengeID: challengeID,
+		ExpiresAt:   time.Now().Add(s.tokenConfig.MFAChallengeTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.LoginResult{MFAToken: token, MFAExpiresIn: s.tokenConfig.MFAChallengeTTL}, nil
+}
+
+// verifySecondFactor accepts a TOTP code that was not used before or an
+// unused recovery code, which is then consumed.
+func (s *authService) verifySecondFactor(user *models.User, code string) (bool, error) {
+	code = normalizeMFACode(code)
+	if len(code) == int(totpOpts.Digits) {
+		step, ok := matchTOTP(user.MFASecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return s.userRepo.UseMFAStep(user.ID, step)
+	}
+	return s.userRepo.UseRecoveryCode(user.ID, hashToken(code))
+}
+
+// matchTOTP checks code against the current time step and its neighbours, to
+// allow for clock drift, and returns the matching step.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current, current - 1, current + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err == nil && subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes returns recovery codes such as "k3xq7-mzp2a" and their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(buf))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashToken(raw)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeMFACode strips the separators users may type or paste.
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
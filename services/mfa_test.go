@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"github.com/pquerna/otp/totp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeMFAUsers keeps one user in memory and implements the repository
+// methods used by the MFA login step. It is safe for concurrent use.
+type fakeMFAUsers struct {
+	repository.UserRepository
+	mu   sync.Mutex
+	user models.User
+}
+
+func (r *fakeMFAUsers) FindByID(id primitive.ObjectID) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id != r.user.ID {
+		return nil, repository.ErrUserNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *fakeMFAUsers) StartMFAChallenge(id primitive.ObjectID, challengeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.user.MFAChallengeID = challengeID
+	return nil
+}
+
+func (r *fakeMFAUsers) ClaimMFAAttempt(id primitive.ObjectID, challengeID string, maxAttempts int) (int, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := &r.user
+	if !u.MFAEnabled || u.MFAFailedAttempts >= maxAttempts || mfaLocked(u) || (challengeID != "" && u.MFAChallengeID != challengeID) {
+		return 0, false, nil
+	}
+	u.MFAFailedAttempts++
+	return u.MFAFailedAttempts, true, nil
+}
+
+func (r *fakeMFAUsers) LockMFA(id primitive.ObjectID, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.user.MFALockedUntil = &until
+	r.user.MFAChallengeID = ""
+	r.user.MFAFailedAttempts = 0
+	return nil
+}
+
+func (r *fakeMFAUsers) EndMFAChallenge(id primitive.ObjectID, challengeID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.user.MFAChallengeID != challengeID {
+		return false, nil
+	}
+	r.user.MFAChallengeID = ""
+	r.user.MFAFailedAttempts = 0
+	r.user.MFALockedUntil = nil
+	return true, nil
+}
+
+func (r *fakeMFAUsers) UseMFAStep(id primitive.ObjectID, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if step <= r.user.MFALastStep {
+		return false, nil
+	}
+	r.user.MFALastStep = step
+	return true, nil
+}
+
+func (r *fakeMFAUsers) UseRecoveryCode(id primitive.ObjectID, hash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, h := range r.user.MFARecoveryCodes {
+		if h == hash {
+			r.user.MFARecoveryCodes = append(r.user.MFARecoveryCodes[:i], r.user.MFARecoveryCodes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeMFAUsers) DisableMFA(id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.user.MFAEnabled = false
+	r.user.MFASecret = ""
+	return nil
+}
+
+func newMFATestService(t *testing.T) (*authService, *fakeMFAUsers, string) {
+	t.Helper()
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "ada@example.com"})
+	if err != nil {
+		t.Fatalf("generate secret: %v", err)
+	}
+	t.Setenv("JWT_SECRET", "test-secret")
+	users := &fakeMFAUsers{user: models.User{ID: primitive.NewObjectID(), MFAEnabled: true, MFASecret: key.Secret()}}
+	s := &authService{userRepo: users, refreshTokenRepo: &fakeRefreshTokens{}, tokenConfig: DefaultTokenConfig}
+	return s, users, wrongTOTPCode(t, key.Secret())
+}
+
+// wrongTOTPCode returns a six digit code that is not accepted right now.
+func wrongTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	for i := 0; i < 10; i++ {
+		code := fmt.Sprintf("%06d", i*111111)
+		if _, ok := matchTOTP(secret, code, time.Now()); !ok {
+			return code
+		}
+	}
+	t.Fatal("no wrong code found")
+	return ""
+}
+
+func challenge(t *testing.T, s *authService, user *models.User) string {
+	t.Helper()
+	result, err := s.mfaChallenge(user)
+	if err != nil {
+		t.Fatalf("mfaChallenge: %v", err)
+	}
+	return result.MFAToken
+}
+
+func TestLoginMFALocksAfterTooManyWrongCodes(t *testing.T) {
+	s, users, wrong := newMFATestService(t)
+
+	// Failures carry over to a new challenge, so logging in again does not
+	// grant more guesses.
+	token := challenge(t, s, &users.user)
+	for i := 0; i < maxMFAAttempts-2; i++ {
+		if _, err := s.LoginMFA(token, wrong); !errors.Is(err, ErrInvalidMFACode) {
+			t.Fatalf("attempt %d: LoginMFA = %v, want ErrInvalidMFACode", i+1, err)
+		}
+	}
+	token = challenge(t, s, &users.user)
+	if _, err := s.LoginMFA(token, wrong); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("attempt %d: LoginMFA = %v, want ErrInvalidMFACode", maxMFAAttempts-1, err)
+	}
+	if _, err := s.LoginMFA(token, wrong); !errors.Is(err, ErrMFALocked) {
+		t.Fatalf("attempt %d: LoginMFA = %v, want ErrMFALocked", maxMFAAttempts, err)
+	}
+	if users.user.MFAChallengeID != "" {
+		t.Error("challenge still open after the lockout")
+	}
+
+	// While locked, even the right code is refused and no new challenge is issued.
+	code, err := totp.GenerateCodeCustom(users.user.MFASecret, time.Now(), totpOpts)
+	if err != nil {
+		t.Fatalf("generate code: %v", err)
+	}
+	if _, err := s.LoginMFA(token, code); !errors.Is(err, ErrMFALocked) {
+		t.Errorf("LoginMFA while locked = %v, want ErrMFALocked", err)
+	}
+	if _, err := s.mfaChallenge(&users.user); !errors.Is(err, ErrMFALocked) {
+		t.Errorf("mfaChallenge while locked = %v, want ErrMFALocked", err)
+	}
+
+	// Once the lockout has passed, a new login can be completed again.
+	past := time.Now().Add(-time.Second)
+	users.user.MFALockedUntil = &past
+	token = challenge(t, s, &users.user)
+	if _, err := s.LoginMFA(token, wrong); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("LoginMFA after the lockout = %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestLoginMFARejectsReplacedChallenge(t *testing.T) {
+	s, users, wrong := newMFATestService(t)
+
+	first := challenge(t, s, &users.user)
+	challenge(t, s, &users.user)
+	if _, err := s.LoginMFA(first, wrong); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("LoginMFA with a replaced challenge = %v, want ErrInvalidMFAToken", err)
+	}
+	if users.user.MFAFailedAttempts != 0 {
+		t.Errorf("replaced challenge counted %d failures, want 0", users.user.MFAFailedAttempts)
+	}
+}
+
+func TestLoginMFALimitsParallelAttempts(t *testing.T) {
+	s, users, wrong := newMFATestService(t)
+	token := challenge(t, s, &users.user)
+
+	const requests = 20
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.LoginMFA(token, wrong)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	wrongCodes := 0
+	for err := range errs {
+		switch {
+		case errors.Is(err, ErrInvalidMFACode):
+			wrongCodes++
+		case !errors.Is(err, ErrMFALocked):
+			t.Errorf("LoginMFA = %v, want ErrInvalidMFACode or ErrMFALocked", err)
+		}
+	}
+	if wrongCodes != maxMFAAttempts-1 {
+		t.Errorf("%d parallel requests had their code checked, want %d before the lockout", wrongCodes+1, maxMFAAttempts)
+	}
+	if !mfaLocked(&users.user) {
+		t.Error("MFA not locked after the parallel attempts")
+	}
+}
+
+func TestLoginMFACompletesChallengeOnce(t *testing.T) {
+	s, users, _ := newMFATestService(t)
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatalf("newRecoveryCodes: %v", err)
+	}
+	users.user.MFARecoveryCodes = hashes
+	token := challenge(t, s, &users.user)
+
+	if _, err := s.LoginMFA(token, codes[0]); err != nil {
+		t.Fatalf("LoginMFA with a recovery code = %v", err)
+	}
+	if _, err := s.LoginMFA(token, codes[1]); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("second LoginMFA with the same challenge = %v, want ErrInvalidMFAToken", err)
+	}
+	if len(users.user.MFARecoveryCodes) != recoveryCodeCount-1 {
+		t.Errorf("%d recovery codes left, want only the first one used", len(users.user.MFARecoveryCodes))
+	}
+}
+
+func TestDisableMFAIsRateLimited(t *testing.T) {
+	s, users, wrong := newMFATestService(t)
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse battery"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	users.user.Password = string(hash)
+	userID := users.user.ID.Hex()
+
+	// Wrong codes and wrong passwords share one attempt count.
+	for i := 1; i < maxMFAAttempts; i++ {
+		if i%2 == 0 {
+			if err := s.DisableMFA(userID, wrong, ""); !errors.Is(err, ErrWrongMFACode) {
+				t.Fatalf("attempt %d: DisableMFA(wrong code) = %v, want ErrWrongMFACode", i, err)
+			}
+			continue
+		}
+		if err := s.DisableMFA(userID, "", "guess"); !errors.Is(err, ErrWrongPassword) {
+			t.Fatalf("attempt %d: DisableMFA(wrong password) = %v, want ErrWrongPassword", i, err)
+		}
+	}
+	if err := s.DisableMFA(userID, "", "guess"); !errors.Is(err, ErrMFALocked) {
+		t.Fatalf("attempt %d: DisableMFA = %v, want ErrMFALocked", maxMFAAttempts, err)
+	}
+	if err := s.DisableMFA(userID, "", "correct horse battery"); !errors.Is(err, ErrMFALocked) {
+		t.Errorf("DisableMFA(right password) while locked = %v, want ErrMFALocked", err)
+	}
+	if !users.user.MFAEnabled {
+		t.Error("MFA was disabled while locked")
+	}
+}
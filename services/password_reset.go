@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/mailer"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+)
+
+// ErrInvalidResetToken is returned when a password reset token is unknown,
+// expired or already used.
+var ErrInvalidResetToken = apperrors.Validation("invalid_reset_token", "invalid or expired password reset token")
+
+func (s *authService) ForgotPassword(email string) error {
+	user, err := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Creating and sending the token happens in the background so that the
+	// response time does not reveal whether the email is registered.
+	go func() {
+		if err := s.sendPasswordReset(user, "Someone asked to reset the password of your account."); err != nil {
+			log.Printf("Failed to send password reset email to user %s: %v", user.ID.Hex(), err)
+		}
+	}()
+	return nil
+}
+
+// sendPasswordReset emails the user a new reset link; reason opens the email.
+func (s *authService) sendPasswordReset(user *models.User, reason string) error {
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	reset := &models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(s.tokenConfig.PasswordResetTTL),
+	}
+	if err := s.passwordResetRepo.Create(reset); err != nil {
+		return err
+	}
+	link, err := linkWithToken(s.tokenConfig.PasswordResetURL, raw)
+	if err != nil {
+		return err
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", displayName(user))
+	fmt.Fprintf(&body, "%s Open this link to choose a new password:\n\n", reason)
+	fmt.Fprintf(&body, "%s\n\n", link)
+	fmt.Fprintf(&body, "The link can be used once and expires at %s.\n\n", reset.ExpiresAt.UTC().Format(time.RFC1123))
+	body.WriteString("If you did not ask for this, ignore this email; your password stays the same.\n")
+	return s.mailer.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    body.String(),
+	})
+}
+
+func (s *authService) ResetPassword(token, newPassword string) error {
+	reset, err := s.passwordResetRepo.FindByHash(hashToken(token))
+	if errors.Is(err, repository.ErrPasswordResetNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if reset.UsedAt != nil || !reset.ExpiresAt.After(time.Now()) {
+		return ErrInvalidResetToken
+	}
+	used, err := s.passwordResetRepo.MarkUsed(reset.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+	if err := s.setPassword(reset.UserID, newPassword); err != nil {
+		// The link was not redeemed, so the user can try it again.
+		if releaseErr := s.passwordResetRepo.Release(reset.ID); releaseErr != nil {
+			log.Printf("Failed to release password reset %s: %v", reset.ID.Hex(), releaseErr)
+		}
+		return err
+	}
+	// Other links sent before the reset must not work afterwards.
+	return s.passwordResetRepo.InvalidateUser(reset.UserID)
+}
+
+func (s *authService) RequirePasswordReset(userID string) error {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	// The link goes out first so that a failure leaves the account unchanged.
+	if err := s.sendPasswordReset(user, "An administrator requires you to choose a new password before you log in again."); err != nil {
+		return err
+	}
+	if err := s.userRepo.RequirePasswordReset(user.ID); err != nil {
+		return err
+	}
+	return s.LogoutAll(userID)
+}
+
+// linkWithToken adds token as the "token" query parameter of base.
+func linkWithToken(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// displayName returns the user's name, or their email if they have none.
+func displayName(user *models.User) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Email
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+	"todo-list-api/mailer"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakePasswordResets keeps password resets in memory.
+type fakePasswordResets struct {
+	repository.PasswordResetRepository
+	resets []models.PasswordReset
+}
+
+func (r *fakePasswordResets) Create(reset *models.PasswordReset) error {
+	reset.ID = primitive.NewObjectID()
+	r.resets = append(r.resets, *reset)
+	return nil
+}
+
+func (r *fakePasswordResets) FindByHash(hash string) (*models.PasswordReset, error) {
+	for _, reset := range r.resets {
+		if reset.TokenHash == hash {
+			return &reset, nil
+		}
+	}
+	return nil, repository.ErrPasswordResetNotFound
+}
+
+func (r *fakePasswordResets) MarkUsed(id primitive.ObjectID) (bool, error) {
+	for i := range r.resets {
+		if reset := &r.resets[i]; reset.ID == id && reset.UsedAt == nil {
+			now := time.Now()
+			reset.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePasswordResets) Release(id primitive.ObjectID) error {
+	for i := range r.resets {
+		if r.resets[i].ID == id {
+			r.resets[i].UsedAt = nil
+		}
+	}
+	return nil
+}
+
+func (r *fakePasswordResets) InvalidateUser(userID primitive.ObjectID) error {
+	now := time.Now()
+	for i := range r.resets {
+		if reset := &r.resets[i]; reset.UserID == userID && reset.UsedAt == nil {
+			reset.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+// fakeResetUsers stores one user. UpdatePassword fails while failUpdates is set.
+type fakeResetUsers struct {
+	repository.UserRepository
+	user        models.User
+	failUpdates bool
+}
+
+func (r *fakeResetUsers) FindByEmail(email string) (*models.User, error) {
+	if email != r.user.Email {
+		return nil, repository.ErrUserNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *fakeResetUsers) UpdatePassword(id primitive.ObjectID, hash string) error {
+	if r.failUpdates {
+		return errors.New("write failed")
+	}
+	r.user.Password = hash
+	return nil
+}
+
+// sentMail collects the messages a mailer is asked to send.
+type sentMail chan mailer.Message
+
+func (m sentMail) Send(_ context.Context, msg mailer.Message) error {
+	m <- msg
+	return nil
+}
+
+var resetTokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+func newResetTestService(t *testing.T) (*authService, *fakeResetUsers, *fakePasswordResets, sentMail) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	users := &fakeResetUsers{user: models.User{ID: primitive.NewObjectID(), Email: "john@doe.com", Password: "old hash"}}
+	resets := &fakePasswordResets{}
+	mail := make(sentMail, 1)
+	s := &authService{
+		userRepo:          users,
+		refreshTokenRepo:  &fakeRefreshTokens{},
+		accessTokenRepo:   newFakeAccessTokens(),
+		passwordResetRepo: resets,
+		revocations:       NewRevocationService(newFakeRevocations(), DefaultTokenConfig),
+		mailer:            mail,
+		tokenConfig:       DefaultTokenConfig,
+	}
+	return s, users, resets, mail
+}
+
+// requestReset asks for a reset link for the user and returns its token.
+func requestReset(t *testing.T, s *authService, mail sentMail) string {
+	t.Helper()
+	if err := s.ForgotPassword(" john@doe.com "); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+	select {
+	case msg := <-mail:
+		m := resetTokenPattern.FindStringSubmatch(msg.Body)
+		if msg.To != "john@doe.com" || m == nil {
+			t.Fatalf("reset email = %+v, want a link with a token to john@doe.com", msg)
+		}
+		return m[1]
+	case <-time.After(time.Second):
+		t.Fatal("no reset email was sent")
+		return ""
+	}
+}
+
+func TestForgotPasswordHidesUnknownEmails(t *testing.T) {
+	s, _, resets, mail := newResetTestService(t)
+	if err := s.ForgotPassword("nobody@doe.com"); err != nil {
+		t.Errorf("ForgotPassword(unknown) error = %v, want the same answer as for a registered email", err)
+	}
+	token := requestReset(t, s, mail)
+	select {
+	case msg := <-mail:
+		t.Errorf("unexpected email %+v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if len(resets.resets) != 1 || resets.resets[0].TokenHash != hashToken(token) {
+		t.Errorf("stored resets = %+v, want only the hash of the emailed token", resets.resets)
+	}
+}
+
+func TestResetPasswordIsSingleUse(t *testing.T) {
+	s, users, _, mail := newResetTestService(t)
+	token := requestReset(t, s, mail)
+	other := requestReset(t, s, mail)
+
+	if err := s.ResetPassword(token, "new password"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(users.user.Password), []byte("new password")) != nil {
+		t.Error("ResetPassword() did not store the new password")
+	}
+	for name, tok := range map[string]string{"used": token, "other outstanding": other, "unknown": "nope"} {
+		if err := s.ResetPassword(tok, "another password"); !errors.Is(err, ErrInvalidResetToken) {
+			t.Errorf("ResetPassword(%s token) error = %v, want ErrInvalidResetToken", name, err)
+		}
+	}
+}
+
+func TestResetPasswordRejectsExpiredToken(t *testing.T) {
+	s, users, resets, mail := newResetTestService(t)
+	token := requestReset(t, s, mail)
+	resets.resets[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	if err := s.ResetPassword(token, "new password"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("ResetPassword(expired) error = %v, want ErrInvalidResetToken", err)
+	}
+	if users.user.Password != "old hash" {
+		t.Error("an expired token changed the password")
+	}
+}
+
+func TestResetPasswordReleasesTokenOnFailure(t *testing.T) {
+	s, users, _, mail := newResetTestService(t)
+	token := requestReset(t, s, mail)
+
+	users.failUpdates = true
+	if err := s.ResetPassword(token, "new password"); err == nil || errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("ResetPassword() with a failing write error = %v, want the write error", err)
+	}
+	users.failUpdates = false
+	if err := s.ResetPassword(token, "new password"); err != nil {
+		t.Errorf("ResetPassword() retry error = %v, want the token still usable", err)
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+)
+
+// ErrUnknownScope is returned when a token is requested with a scope that does not exist.
+var ErrUnknownScope = apperrors.Validation("unknown_scope", "unknown scope; use "+strings.Join(models.AllScopes, ", "))
+
+// ErrScopeNotGranted is returned when a token is requested with scopes that
+// the token used for the request does not have.
+var ErrScopeNotGranted = apperrors.Forbidden("insufficient_scope", "a token cannot have scopes that the token creating it lacks")
+
+// ErrInvalidTokenExpiry is returned when a token would expire in the past.
+var ErrInvalidTokenExpiry = apperrors.Validation("invalid_token_expiry", "expires_at must be in the future")
+
+// ErrCreatedWithAccessToken is returned when a personal access token is used
+// to create another one, which would let a leaked token outlive its revocation.
+var ErrCreatedWithAccessToken = apperrors.Forbidden("access_token_not_allowed", "personal access tokens cannot create personal access tokens; log in with a password")
+
+// Errors for personal access tokens presented as bearer tokens. They use the
+// same codes as for JWTs so that clients handle both alike.
+var (
+	ErrInvalidAccessToken = apperrors.Unauthorized("invalid_token", "invalid token")
+	ErrAccessTokenExpired = apperrors.Unauthorized("token_expired", "token expired")
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, which tells
+// them apart from JWTs and makes leaked tokens easy to scan for.
+const PersonalAccessTokenPrefix = "pat_"
+
+// lastUsedResolution is how stale last_used_at may be, which saves a write
+// on most requests.
+const lastUsedResolution = time.Minute
+
+// PersonalAccessTokenService manages personal access tokens for scripts and CI.
+type PersonalAccessTokenService interface {
+	// CreateToken stores the token and returns its secret value, which is
+	// not retrievable later. The token may only have scopes within granted,
+	// the scopes of the token used to create it.
+	CreateToken(token *models.PersonalAccessToken, granted []string) (string, error)
+	GetTokens(userID string) ([]models.PersonalAccessToken, error)
+	GetToken(id string, userID string) (*models.PersonalAccessToken, error)
+	RevokeToken(id string, userID string) error
+	// Authenticate returns the token with the given secret value if it is
+	// valid, and records that it was used.
+	Authenticate(raw string) (*models.PersonalAccessToken, error)
+}
+
+type personalAccessTokenService struct {
+	tokenRepo repository.PersonalAccessTokenRepository
+}
+
+// NewPersonalAccessTokenService returns a new instance of PersonalAccessTokenService.
+func NewPersonalAccessTokenService(tokenRepo repository.PersonalAccessTokenRepository) PersonalAccessTokenService {
+	return &personalAccessTokenService{tokenRepo}
+}
+
+func (s *personalAccessTokenService) CreateToken(token *models.PersonalAccessToken, granted []string) (string, error) {
+	token.Name = strings.TrimSpace(token.Name)
+	scopes, err := normalizeScopes(token.Scopes)
+	if err != nil {
+		return "", err
+	}
+	if !models.HasScopes(granted, models.EffectiveScopes(scopes)...) {
+		return "", ErrScopeNotGranted
+	}
+	token.Scopes = scopes
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		return "", ErrInvalidTokenExpiry
+	}
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	raw := PersonalAccessTokenPrefix + secret
+	token.Prefix = raw[:len(PersonalAccessTokenPrefix)+6]
+	token.TokenHash = hashToken(raw)
+	if err := s.tokenRepo.Create(token); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (s *personalAccessTokenService) GetTokens(userID string) ([]models.PersonalAccessToken, error) {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.GetTokens(userObjID)
+}
+
+func (s *personalAccessTokenService) GetToken(id string, userID string) (*models.PersonalAccessToken, error) {
+	tokenID, err := ParseID(id, ErrAccessTokenNotFound)
+	if err != nil {
+		return nil, err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenRepo.GetByID(tokenID, userObjID)
+}
+
+func (s *personalAccessTokenService) RevokeToken(id string, userID string) error {
+	tokenID, err := ParseID(id, ErrAccessTokenNotFound)
+	if err != nil {
+		return err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return err
+	}
+	return s.tokenRepo.Delete(tokenID, userObjID)
+}
+
+func (s *personalAccessTokenService) Authenticate(raw string) (*models.PersonalAccessToken, error) {
+	token, err := s.tokenRepo.FindByHash(hashToken(raw))
+	if errors.Is(err, repository.ErrAccessTokenNotFound) {
+		return nil, ErrInvalidAccessToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(now) {
+		return nil, ErrAccessTokenExpired
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		// Failing to record usage must not fail the request.
+		if err := s.tokenRepo.TouchLastUsed(token.ID, now, now.Add(-lastUsedResolution)); err != nil {
+			log.Printf("Failed to record use of access token %s: %v", token.ID.Hex(), err)
+		}
+	}
+	return token, nil
+}
+
+// normalizeScopes checks that every scope exists and returns them sorted
+// without duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	var result []string
+	for _, scope := range scopes {
+		if !models.HasScopes(models.AllScopes, scope) {
+			return nil, ErrUnknownScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeAccessTokens stores personal access tokens in memory and counts
+// last_used_at writes.
+type fakeAccessTokens struct {
+	repository.PersonalAccessTokenRepository
+	tokens  map[primitive.ObjectID]*models.PersonalAccessToken
+	touches int
+}
+
+func newFakeAccessTokens() *fakeAccessTokens {
+	return &fakeAccessTokens{tokens: map[primitive.ObjectID]*models.PersonalAccessToken{}}
+}
+
+func (r *fakeAccessTokens) Create(token *models.PersonalAccessToken) error {
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *fakeAccessTokens) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
+	if token, ok := r.tokens[id]; !ok || token.UserID != userID {
+		return repository.ErrAccessTokenNotFound
+	}
+	delete(r.tokens, id)
+	return nil
+}
+
+func (r *fakeAccessTokens) FindByHash(hash string) (*models.PersonalAccessToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrAccessTokenNotFound
+}
+
+func (r *fakeAccessTokens) TouchLastUsed(id primitive.ObjectID, at, notBefore time.Time) error {
+	token := r.tokens[id]
+	if token.LastUsedAt == nil || token.LastUsedAt.Before(notBefore) {
+		token.LastUsedAt = &at
+		r.touches++
+	}
+	return nil
+}
+
+func (r *fakeAccessTokens) DeleteByUser(userID primitive.ObjectID) error {
+	for id, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, id)
+		}
+	}
+	return nil
+}
+
+func TestCreateTokenStoresOnlyHash(t *testing.T) {
+	tokens := newFakeAccessTokens()
+	s := NewPersonalAccessTokenService(tokens)
+	token := &models.PersonalAccessToken{UserID: primitive.NewObjectID(), Name: " CI ", Scopes: []string{models.ScopeTodosWrite, models.ScopeTodosRead, models.ScopeTodosWrite}}
+
+	raw, err := s.CreateToken(token, models.AllScopes)
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if !strings.HasPrefix(raw, PersonalAccessTokenPrefix) || !strings.HasPrefix(raw, token.Prefix) || len(token.Prefix) != len(PersonalAccessTokenPrefix)+6 {
+		t.Errorf("token %q with prefix %q, want %q followed by a secret", raw, token.Prefix, PersonalAccessTokenPrefix)
+	}
+	stored := tokens.tokens[token.ID]
+	if stored.TokenHash != hashToken(raw) || strings.Contains(stored.TokenHash, raw[len(PersonalAccessTokenPrefix):]) {
+		t.Errorf("stored hash %q, want the SHA-256 hash of the token", stored.TokenHash)
+	}
+	if stored.Name != "CI" || !reflect.DeepEqual(stored.Scopes, []string{models.ScopeTodosRead, models.ScopeTodosWrite}) {
+		t.Errorf("stored name %q and scopes %v, want trimmed name and sorted unique scopes", stored.Name, stored.Scopes)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	for name, tt := range map[string]struct {
+		token *models.PersonalAccessToken
+		want  error
+	}{
+		"unknown scope": {&models.PersonalAccessToken{Name: "x", Scopes: []string{"todos:delete"}}, ErrUnknownScope},
+		"past expiry":   {&models.PersonalAccessToken{Name: "x", ExpiresAt: &past}, ErrInvalidTokenExpiry},
+	} {
+		if _, err := s.CreateToken(tt.token, models.AllScopes); !errors.Is(err, tt.want) {
+			t.Errorf("CreateToken(%s) error = %v, want %v", name, err, tt.want)
+		}
+	}
+}
+
+func TestCreateTokenLimitsScopesToGranted(t *testing.T) {
+	s := NewPersonalAccessTokenService(newFakeAccessTokens())
+	granted := []string{models.ScopeTodosRead, models.ScopeTodosWrite, models.ScopeProfile}
+	tests := []struct {
+		scopes  []string
+		granted []string
+		want    error
+	}{
+		{[]string{models.ScopeTodosRead}, granted, nil},
+		{nil, granted, nil},
+		{[]string{models.ScopeAdmin}, granted, ErrScopeNotGranted},
+		{nil, []string{models.ScopeTodosRead}, ErrScopeNotGranted},
+		{[]string{models.ScopeTodosRead, models.ScopeProfile}, []string{models.ScopeTodosRead}, ErrScopeNotGranted},
+	}
+	for _, tt := range tests {
+		token := &models.PersonalAccessToken{UserID: primitive.NewObjectID(), Name: "CI", Scopes: tt.scopes}
+		if _, err := s.CreateToken(token, tt.granted); !errors.Is(err, tt.want) {
+			t.Errorf("CreateToken(scopes %v) with %v granted error = %v, want %v", tt.scopes, tt.granted, err, tt.want)
+		}
+	}
+}
+
+func TestAuthenticateAccessToken(t *testing.T) {
+	tokens := newFakeAccessTokens()
+	s := NewPersonalAccessTokenService(tokens)
+	userID := primitive.NewObjectID()
+	create := func(expiresAt *time.Time) (*models.PersonalAccessToken, string) {
+		token := &models.PersonalAccessToken{UserID: userID, Name: "CI"}
+		raw, err := s.CreateToken(token, models.AllScopes)
+		if err != nil {
+			t.Fatalf("CreateToken() error = %v", err)
+		}
+		token.ExpiresAt = expiresAt
+		tokens.tokens[token.ID].ExpiresAt = expiresAt
+		return token, raw
+	}
+
+	token, raw := create(nil)
+	got, err := s.Authenticate(raw)
+	if err != nil || got.ID != token.ID {
+		t.Fatalf("Authenticate() = %v, %v; want the token", got, err)
+	}
+	if _, err := s.Authenticate(raw); err != nil {
+		t.Fatalf("second Authenticate() error = %v", err)
+	}
+	if tokens.touches != 1 || tokens.tokens[token.ID].LastUsedAt == nil {
+		t.Errorf("last_used_at written %d times, want once within a minute", tokens.touches)
+	}
+	stale := time.Now().Add(-2 * lastUsedResolution)
+	tokens.tokens[token.ID].LastUsedAt = &stale
+	if _, err := s.Authenticate(raw); err != nil || tokens.touches != 2 {
+		t.Errorf("Authenticate() after a minute = %v with %d writes, want last_used_at updated", err, tokens.touches)
+	}
+
+	if _, err := s.Authenticate(raw + "x"); !errors.Is(err, ErrInvalidAccessToken) {
+		t.Errorf("Authenticate(unknown) error = %v, want ErrInvalidAccessToken", err)
+	}
+	past := time.Now().Add(-time.Second)
+	_, expired := create(&past)
+	if _, err := s.Authenticate(expired); !errors.Is(err, ErrAccessTokenExpired) {
+		t.Errorf("Authenticate(expired) error = %v, want ErrAccessTokenExpired", err)
+	}
+
+	if err := s.RevokeToken(token.ID.Hex(), primitive.NewObjectID().Hex()); !errors.Is(err, repository.ErrAccessTokenNotFound) {
+		t.Errorf("RevokeToken(another user's token) error = %v, want ErrAccessTokenNotFound", err)
+	}
+	if err := s.RevokeToken(token.ID.Hex(), userID.Hex()); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+	if _, err := s.Authenticate(raw); !errors.Is(err, ErrInvalidAccessToken) {
+		t.Errorf("Authenticate(revoked) error = %v, want ErrInvalidAccessToken", err)
+	}
+}
+
+func TestAccountRecoveryDeletesAccessTokens(t *testing.T) {
+	for _, flow := range []string{"logout everywhere", "password reset"} {
+		t.Run(flow, func(t *testing.T) {
+			s, users, _, mail := newResetTestService(t)
+			tokens := s.accessTokenRepo.(*fakeAccessTokens)
+			accessTokens := NewPersonalAccessTokenService(tokens)
+			raw, err := accessTokens.CreateToken(&models.PersonalAccessToken{UserID: users.user.ID, Name: "CI"}, models.DefaultScopes)
+			if err != nil {
+				t.Fatalf("CreateToken() error = %v", err)
+			}
+			other, err := accessTokens.CreateToken(&models.PersonalAccessToken{UserID: primitive.NewObjectID(), Name: "CI"}, models.DefaultScopes)
+			if err != nil {
+				t.Fatalf("CreateToken() error = %v", err)
+			}
+
+			if flow == "logout everywhere" {
+				err = s.LogoutAll(users.user.ID.Hex())
+			} else {
+				err = s.ResetPassword(requestReset(t, s, mail), "new password")
+			}
+			if err != nil {
+				t.Fatalf("%s error = %v", flow, err)
+			}
+			if _, err := accessTokens.Authenticate(raw); !errors.Is(err, ErrInvalidAccessToken) {
+				t.Errorf("Authenticate() after %s error = %v, want ErrInvalidAccessToken", flow, err)
+			}
+			if _, err := accessTokens.Authenticate(other); err != nil {
+				t.Errorf("another user's token after %s error = %v, want it kept", flow, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"todo-list-api/query"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidProjectName is returned when a project is given an empty name.
+var ErrInvalidProjectName = apperrors.Validation("invalid_project_name", "project name must not be empty")
+
+// ErrInboxImmutable is returned when trying to rename or delete the inbox project.
+var ErrInboxImmutable = apperrors.Forbidden("inbox_immutable", "the inbox project cannot be renamed or deleted")
+
+// ErrUnknownProject is returned when a to-do item references a project the user does not own.
+var ErrUnknownProject = apperrors.Validation("unknown_project", "unknown project")
+
+// ErrInvalidDeleteMode is returned when an unknown project delete mode is requested.
+var ErrInvalidDeleteMode = apperrors.Validation("invalid_delete_mode", "invalid mode, expected move or cascade")
+
+// Project delete modes.
+const (
+	// ProjectDeleteMove moves the project's todos to the inbox before deleting it.
+	ProjectDeleteMove = "move"
+	// ProjectDeleteCascade deletes the project's todos along with it.
+	ProjectDeleteCascade = "cascade"
+)
+
+// ProjectService is the business logic layer for managing projects.
+type ProjectService interface {
+	CreateProject(project *models.Project) error
+	UpdateProject(project *models.Project) error
+	DeleteProject(id string, userID string, mode string) error
+	GetProjects(userID string) ([]models.Project, error)
+	GetProjectByID(id string, userID string) (*models.Project, error)
+}
+
+type projectService struct {
+	projectRepo   repository.ProjectRepository
+	todoRepo      repository.TodoRepository
+	smartListRepo repository.SmartListRepository
+}
+
+// NewProjectService returns a new instance of ProjectService.
+func NewProjectService(projectRepo repository.ProjectRepository, todoRepo repository.TodoRepository, smartListRepo repository.SmartListRepository) ProjectService {
+	return &projectService{projectRepo, todoRepo, smartListRepo}
+}
+
+func (s *projectService) CreateProject(project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return ErrInvalidProjectName
+	}
+	project.Inbox = false
+	return s.projectRepo.Create(project)
+}
+
+func (s *projectService) UpdateProject(project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return ErrInvalidProjectName
+	}
+	existing, err := s.projectRepo.GetByID(project.ID, project.UserID)
+	if err != nil {
+		return err
+	}
+	if existing.Inbox {
+		return ErrInboxImmutable
+	}
+	project.Inbox = existing.Inbox
+	project.CreatedAt = existing.CreatedAt
+	return s.projectRepo.Update(project)
+}
+
+// DeleteProject deletes a project and either moves its todos to the inbox or
+// deletes them too. Smart lists filtering on the project follow its todos to
+// the inbox; when the todos are deleted, the project is dropped from their
+// filters instead.
+func (s *projectService) DeleteProject(id string, userID string, mode string) error {
+	if mode == "" {
+		mode = ProjectDeleteMove
+	}
+	if mode != ProjectDeleteMove && mode != ProjectDeleteCascade {
+		return ErrInvalidDeleteMode
+	}
+	projectID, err := ParseID(id, ErrProjectNotFound)
+	if err != nil {
+		return err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return err
+	}
+	project, err := s.projectRepo.GetByID(projectID, userObjID)
+	if err != nil {
+		return err
+	}
+	if project.Inbox {
+		return ErrInboxImmutable
+	}
+
+	var target *primitive.ObjectID
+	if mode == ProjectDeleteCascade {
+		if err := s.todoRepo.DeleteByProject(userObjID, projectID); err != nil {
+			return err
+		}
+	} else {
+		inbox, err := ensureInbox(s.projectRepo, userObjID)
+		if err != nil {
+			return err
+		}
+		if err := s.todoRepo.MoveToProject(userObjID, projectID, inbox.ID); err != nil {
+			return err
+		}
+		target = &inbox.ID
+	}
+	if err := s.projectRepo.Delete(projectID, userObjID); err != nil {
+		return err
+	}
+	if err := s.smartListRepo.ReplaceProject(userObjID, projectID, target); err != nil {
+		return err
+	}
+	return rewriteSmartListQueries(s.smartListRepo, userObjID, func(c *query.Comparison) bool {
+		if id, err := primitive.ObjectIDFromHex(c.Value); c.Key() != "project_id" || err != nil || id != projectID {
+			return true
+		}
+		if target == nil {
+			return false
+		}
+		c.Value = target.Hex()
+		return true
+	})
+}
+
+func (s *projectService) GetProjects(userID string) ([]models.Project, error) {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	// Users registered before projects existed get their inbox on first use.
+	if _, err := ensureInbox(s.projectRepo, userObjID); err != nil {
+		return nil, err
+	}
+	return s.projectRepo.GetProjects(userObjID)
+}
+
+func (s *projectService) GetProjectByID(id string, userID string) (*models.Project, error) {
+	projectID, err := ParseID(id, ErrProjectNotFound)
+	if err != nil {
+		return nil, err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.projectRepo.GetByID(projectID, userObjID)
+}
+
+// ensureInbox returns the user's inbox project, creating it if it does not exist yet.
+func ensureInbox(projectRepo repository.ProjectRepository, userID primitive.ObjectID) (*models.Project, error) {
+	inbox, err := projectRepo.FindInbox(userID)
+	if err == nil {
+		return inbox, nil
+	}
+	if !errors.Is(err, repository.ErrProjectNotFound) {
+		return nil, err
+	}
+	inbox = &models.Project{Name: models.InboxProjectName, Inbox: true, UserID: userID}
+	if err := projectRepo.Create(inbox); err != nil {
+		// Another request created the inbox concurrently.
+		if errors.Is(err, repository.ErrDuplicate) {
+			return projectRepo.FindInbox(userID)
+		}
+		return nil, err
+	}
+	return inbox, nil
+}
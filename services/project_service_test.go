@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errProjectMissing is what the project repository returns for an unknown project.
+var errProjectMissing = repository.ErrProjectNotFound
+
+// fakeProjects keeps projects in memory.
+type fakeProjects struct {
+	repository.ProjectRepository
+	projects []models.Project
+}
+
+func (r *fakeProjects) Create(project *models.Project) error {
+	project.ID = primitive.NewObjectID()
+	r.projects = append(r.projects, *project)
+	return nil
+}
+
+func (r *fakeProjects) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Project, error) {
+	for _, project := range r.projects {
+		if project.ID == id && project.UserID == userID {
+			return &project, nil
+		}
+	}
+	return nil, errProjectMissing
+}
+
+func (r *fakeProjects) FindInbox(userID primitive.ObjectID) (*models.Project, error) {
+	for _, project := range r.projects {
+		if project.Inbox && project.UserID == userID {
+			return &project, nil
+		}
+	}
+	return nil, errProjectMissing
+}
+
+func (r *fakeProjects) Delete(id primitive.ObjectID, userID primitive.ObjectID) error {
+	for i, project := range r.projects {
+		if project.ID == id && project.UserID == userID {
+			r.projects = append(r.projects[:i], r.projects[i+1:]...)
+			return nil
+		}
+	}
+	return errProjectMissing
+}
+
+// fakeProjectTodos records what happens to a deleted project's todos.
+type fakeProjectTodos struct {
+	repository.TodoRepository
+	movedTo   *primitive.ObjectID
+	cascaded  bool
+	deletedOf primitive.ObjectID
+}
+
+func (r *fakeProjectTodos) MoveToProject(userID, from, to primitive.ObjectID) error {
+	r.movedTo = &to
+	return nil
+}
+
+func (r *fakeProjectTodos) DeleteByProject(userID, projectID primitive.ObjectID) error {
+	r.cascaded = true
+	r.deletedOf = projectID
+	return nil
+}
+
+func TestProjectServiceDeleteProject(t *testing.T) {
+	tests := []struct {
+		mode      string
+		wantErr   error
+		wantMoved bool
+		wantGone  bool
+	}{
+		{mode: "", wantMoved: true, wantGone: true},
+		{mode: ProjectDeleteMove, wantMoved: true, wantGone: true},
+		{mode: ProjectDeleteCascade, wantGone: true},
+		{mode: "archive", wantErr: ErrInvalidDeleteMode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			userID := primitive.NewObjectID()
+			project := models.Project{ID: primitive.NewObjectID(), Name: "Garden", UserID: userID}
+			projects := &fakeProjects{projects: []models.Project{project}}
+			todos := &fakeProjectTodos{}
+			s := &projectService{projectRepo: projects, todoRepo: todos, smartListRepo: &fakeSmartLists{}}
+
+			err := s.DeleteProject(project.ID.Hex(), userID.Hex(), tt.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteProject(%q) error = %v, want %v", tt.mode, err, tt.wantErr)
+			}
+			if _, err := projects.GetByID(project.ID, userID); (err != nil) != tt.wantGone {
+				t.Errorf("DeleteProject(%q) deleted = %v, want %v", tt.mode, err != nil, tt.wantGone)
+			}
+			if tt.wantMoved {
+				inbox, err := projects.FindInbox(userID)
+				if err != nil {
+					t.Fatalf("no inbox created for the moved todos: %v", err)
+				}
+				if todos.movedTo == nil || *todos.movedTo != inbox.ID {
+					t.Errorf("todos moved to %v, want the inbox %v", todos.movedTo, inbox.ID)
+				}
+			} else if todos.movedTo != nil {
+				t.Errorf("todos moved to %v, want them left alone", todos.movedTo)
+			}
+			if wantCascade := tt.mode == ProjectDeleteCascade; todos.cascaded != wantCascade || (wantCascade && todos.deletedOf != project.ID) {
+				t.Errorf("todos cascaded = %v for %v, want %v", todos.cascaded, todos.deletedOf, wantCascade)
+			}
+		})
+	}
+}
+
+func TestProjectServiceProtectsInbox(t *testing.T) {
+	userID := primitive.NewObjectID()
+	projects := &fakeProjects{}
+	s := &projectService{projectRepo: projects, todoRepo: &fakeProjectTodos{}, smartListRepo: &fakeSmartLists{}}
+
+	inbox, err := ensureInbox(projects, userID)
+	if err != nil {
+		t.Fatalf("ensureInbox() error = %v", err)
+	}
+	again, err := ensureInbox(projects, userID)
+	if err != nil || again.ID != inbox.ID || len(projects.projects) != 1 {
+		t.Fatalf("ensureInbox() created a second inbox: %v, %v", again, err)
+	}
+
+	if err := s.DeleteProject(inbox.ID.Hex(), userID.Hex(), ProjectDeleteCascade); !errors.Is(err, ErrInboxImmutable) {
+		t.Errorf("DeleteProject(inbox) = %v, want ErrInboxImmutable", err)
+	}
+	renamed := *inbox
+	renamed.Name = "Someday"
+	if err := s.UpdateProject(&renamed); !errors.Is(err, ErrInboxImmutable) {
+		t.Errorf("UpdateProject(inbox) = %v, want ErrInboxImmutable", err)
+	}
+	blank := models.Project{Name: "  ", UserID: userID}
+	if err := s.CreateProject(&blank); !errors.Is(err, ErrInvalidProjectName) {
+		t.Errorf("CreateProject(blank) = %v, want ErrInvalidProjectName", err)
+	}
+	sneaky := models.Project{Name: "Second inbox", Inbox: true, UserID: userID}
+	if err := s.CreateProject(&sneaky); err != nil || sneaky.Inbox {
+		t.Errorf("CreateProject(inbox: true) = %v, inbox %v; want a regular project", err, sneaky.Inbox)
+	}
+}
+
+func TestAssignProject(t *testing.T) {
+	userID := primitive.NewObjectID()
+	own := models.Project{ID: primitive.NewObjectID(), Name: "Garden", UserID: userID}
+	other := models.Project{ID: primitive.NewObjectID(), Name: "Theirs", UserID: primitive.NewObjectID()}
+	projects := &fakeProjects{projects: []models.Project{own, other}}
+	s := &todoService{projectRepo: projects}
+
+	todo := &models.Todo{UserID: userID}
+	if err := s.assignProject(todo); err != nil {
+		t.Fatalf("assignProject(no project) error = %v", err)
+	}
+	inbox, _ := projects.FindInbox(userID)
+	if todo.ProjectID == nil || inbox == nil || *todo.ProjectID != inbox.ID {
+		t.Errorf("assignProject(no project) = %v, want the inbox", todo.ProjectID)
+	}
+
+	todo = &models.Todo{UserID: userID, ProjectID: &own.ID}
+	if err := s.assignProject(todo); err != nil || *todo.ProjectID != own.ID {
+		t.Errorf("assignProject(own) = %v, %v; want %v", todo.ProjectID, err, own.ID)
+	}
+	todo = &models.Todo{UserID: userID, ProjectID: &other.ID}
+	if err := s.assignProject(todo); !errors.Is(err, ErrUnknownProject) {
+		t.Errorf("assignProject(other user's project) = %v, want ErrUnknownProject", err)
+	}
+}
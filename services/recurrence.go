@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+
+	"github.com/teambition/rrule-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidRRule is returned when a recurrence rule cannot be parsed.
+var ErrInvalidRRule = apperrors.Validation("invalid_rrule", "invalid rrule")
+
+// ErrInvalidRepeatFrom is returned when an unknown recurrence anchor is given.
+var ErrInvalidRepeatFrom = apperrors.Validation("invalid_repeat_from", "invalid repeat_from, expected due or completion")
+
+// ErrRecurrenceNeedsDueDate is returned when a todo repeats from its due date but has none.
+var ErrRecurrenceNeedsDueDate = apperrors.Validation("recurrence_needs_due_date", "todos repeating from their due date need a due_at")
+
+// validateRecurrence checks the todo's recurrence rule and stores it in canonical form.
+func validateRecurrence(todo *models.Todo) error {
+	if todo.RRule == "" {
+		todo.RepeatFrom = ""
+		return nil
+	}
+	opt, err := parseRRule(todo.RRule)
+	if err != nil {
+		return err
+	}
+	todo.RRule = opt.RRuleString()
+
+	if todo.RepeatFrom == "" {
+		todo.RepeatFrom = models.RepeatFromDue
+	}
+	switch todo.RepeatFrom {
+	case models.RepeatFromDue:
+		if todo.DueAt == nil {
+			return ErrRecurrenceNeedsDueDate
+		}
+	case models.RepeatFromCompletion:
+	default:
+		return ErrInvalidRepeatFrom
+	}
+	return nil
+}
+
+// parseRRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO". The start
+// of the series always comes from the todo, so DTSTART is not accepted.
+func parseRRule(rule string) (*rrule.ROption, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if strings.Contains(rule, "\n") || strings.Contains(rule, "DTSTART") {
+		return nil, fmt.Errorf("%w: DTSTART is not supported, the series starts at the todo's due date", ErrInvalidRRule)
+	}
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+	if _, err := rrule.NewRRule(*opt); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+	return opt, nil
+}
+
+// nextOccurrence builds the todo that follows a completed recurring todo, or
+// returns nil when the series has ended. Labels, priority, description,
+// project and checklist are carried over; the checklist is reset.
+//
+// Occurrences are computed in the todo's timezone so a todo due at 09:00
+// stays due at 09:00 local time across daylight saving transitions.
+func nextOccurrence(todo *models.Todo, completedAt time.Time) (*models.Todo, error) {
+	opt, err := parseRRule(todo.RRule)
+	if err != nil {
+		return nil, err
+	}
+	if opt.Count == 1 {
+		return nil, nil
+	}
+	loc, err := LoadLocation(todo.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	// The series is anchored either at the current due date or at the
+	// completion day, keeping the due date's time of day when there is one.
+	var start time.Time
+	if todo.RepeatFrom == models.RepeatFromCompletion {
+		start = completedAt.In(loc)
+		if todo.DueAt != nil {
+			due := todo.DueAt.In(loc)
+			start = time.Date(start.Year(), start.Month(), start.Day(), due.Hour(), due.Minute(), due.Second(), 0, loc)
+		}
+	} else {
+		if todo.DueAt == nil {
+			return nil, ErrRecurrenceNeedsDueDate
+		}
+		start = todo.DueAt.In(loc)
+	}
+
+	opt.Dtstart = start
+	rule, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRRule, err)
+	}
+	nextDue := rule.After(start, false)
+	if nextDue.IsZero() {
+		return nil, nil
+	}
+
+	// Each occurrence consumes one repetition of a COUNT-limited rule.
+	if opt.Count > 1 {
+		opt.Count--
+	}
+	next := &models.Todo{
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      models.TodoStatusOpen,
+		Priority:    todo.Priority,
+		Labels:      todo.Labels,
+		ProjectID:   todo.ProjectID,
+		DueAt:       &nextDue,
+		Timezone:    todo.Timezone,
+		RRule:       opt.RRuleString(),
+		RepeatFrom:  todo.RepeatFrom,
+		UserID:      todo.UserID,
+	}
+	if todo.StartAt != nil && todo.DueAt != nil {
+		nextStart := nextDue.Add(todo.StartAt.Sub(*todo.DueAt))
+		next.StartAt = &nextStart
+	}
+	for _, item := range todo.Checklist {
+		item.ID = primitive.NewObjectID()
+		item.Done = false
+		next.Checklist = append(next.Checklist, item)
+	}
+	// Relative reminders follow the new due date; absolute ones move with it.
+	for _, reminder := range todo.Reminders {
+		reminder.ID = primitive.NewObjectID()
+		reminder.SentAt, reminder.Attempts, reminder.RetryAt, reminder.FailedAt = nil, 0, nil, nil
+		if reminder.At != nil && todo.DueAt != nil {
+			at := reminder.At.Add(nextDue.Sub(*todo.DueAt))
+			reminder.At = &at
+		} else if reminder.At != nil {
+			continue
+		}
+		reminder.FireAt = reminderFireAt(reminder, next.DueAt)
+		next.Reminders = append(next.Reminders, reminder)
+	}
+	return next, nil
+}
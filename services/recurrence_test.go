@@ -0,0 +1,174 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestNextOccurrenceKeepsWallClockAcrossDST(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		rrule    string
+		due      [3]int // year, month, day of the current due date at 09:00
+		wantDay  [3]int // year, month, day of the next due date at 09:00
+	}{
+		{"daily spring forward Berlin", "Europe/Berlin", "FREQ=DAILY", [3]int{2026, 3, 28}, [3]int{2026, 3, 29}},
+		{"daily fall back Berlin", "Europe/Berlin", "FREQ=DAILY", [3]int{2026, 10, 24}, [3]int{2026, 10, 25}},
+		{"weekly spring forward New York", "America/New_York", "FREQ=WEEKLY", [3]int{2026, 3, 5}, [3]int{2026, 3, 12}},
+		{"weekly fall back New York", "America/New_York", "FREQ=WEEKLY", [3]int{2026, 10, 29}, [3]int{2026, 11, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := mustLoadLocation(t, tt.timezone)
+			due := time.Date(tt.due[0], time.Month(tt.due[1]), tt.due[2], 9, 0, 0, 0, loc)
+			todo := &models.Todo{RRule: tt.rrule, Timezone: tt.timezone, DueAt: &due, RepeatFrom: models.RepeatFromDue}
+
+			next, err := nextOccurrence(todo, due)
+			if err != nil {
+				t.Fatalf("nextOccurrence: %v", err)
+			}
+			if next == nil {
+				t.Fatal("nextOccurrence returned no occurrence")
+			}
+			want := time.Date(tt.wantDay[0], time.Month(tt.wantDay[1]), tt.wantDay[2], 9, 0, 0, 0, loc)
+			if !next.DueAt.Equal(want) {
+				t.Errorf("due = %v, want %v", next.DueAt.In(loc), want)
+			}
+			if got := next.DueAt.In(loc); got.Hour() != 9 || got.Minute() != 0 {
+				t.Errorf("wall clock = %s, want 09:00", got.Format("15:04"))
+			}
+			if offset := next.DueAt.Sub(due); offset == 24*time.Hour || offset == 7*24*time.Hour {
+				t.Errorf("due moved by exactly %v, so the DST change was ignored", offset)
+			}
+		})
+	}
+}
+
+func TestNextOccurrenceRepeatFrom(t *testing.T) {
+	loc := mustLoadLocation(t, "Europe/Berlin")
+	due := time.Date(2026, 6, 1, 9, 0, 0, 0, loc)
+	completedAt := time.Date(2026, 6, 4, 15, 30, 0, 0, loc)
+
+	tests := []struct {
+		repeatFrom string
+		want       time.Time
+	}{
+		{models.RepeatFromDue, time.Date(2026, 6, 2, 9, 0, 0, 0, loc)},
+		{models.RepeatFromCompletion, time.Date(2026, 6, 5, 9, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repeatFrom, func(t *testing.T) {
+			todo := &models.Todo{RRule: "FREQ=DAILY", Timezone: "Europe/Berlin", DueAt: &due, RepeatFrom: tt.repeatFrom}
+			next, err := nextOccurrence(todo, completedAt)
+			if err != nil {
+				t.Fatalf("nextOccurrence: %v", err)
+			}
+			if next == nil || !next.DueAt.Equal(tt.want) {
+				t.Fatalf("next = %+v, want due %v", next, tt.want)
+			}
+			if next.RepeatFrom != tt.repeatFrom {
+				t.Errorf("repeat_from = %q, want %q", next.RepeatFrom, tt.repeatFrom)
+			}
+		})
+	}
+}
+
+func TestNextOccurrenceTermination(t *testing.T) {
+	loc := mustLoadLocation(t, "UTC")
+	tests := []struct {
+		name      string
+		rrule     string
+		due       time.Time
+		wantEnd   bool
+		wantRRule string
+	}{
+		{"count decrements", "FREQ=DAILY;COUNT=3", time.Date(2026, 6, 1, 9, 0, 0, 0, loc), false, "FREQ=DAILY;COUNT=2"},
+		{"count reaches last", "FREQ=DAILY;COUNT=2", time.Date(2026, 6, 1, 9, 0, 0, 0, loc), false, "FREQ=DAILY;COUNT=1"},
+		{"count exhausted", "FREQ=DAILY;COUNT=1", time.Date(2026, 6, 1, 9, 0, 0, 0, loc), true, ""},
+		{"before until", "FREQ=DAILY;UNTIL=20260602T235959Z", time.Date(2026, 6, 1, 9, 0, 0, 0, loc), false, "FREQ=DAILY;UNTIL=20260602T235959Z"},
+		{"at until", "FREQ=DAILY;UNTIL=20260602T235959Z", time.Date(2026, 6, 2, 9, 0, 0, 0, loc), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &models.Todo{RRule: tt.rrule, Timezone: "UTC", DueAt: &tt.due, RepeatFrom: models.RepeatFromDue}
+			next, err := nextOccurrence(todo, tt.due)
+			if err != nil {
+				t.Fatalf("nextOccurrence: %v", err)
+			}
+			if tt.wantEnd {
+				if next != nil {
+					t.Fatalf("series should have ended, got due %v", next.DueAt)
+				}
+				return
+			}
+			if next == nil {
+				t.Fatal("series ended early")
+			}
+			if next.RRule != tt.wantRRule {
+				t.Errorf("rrule = %q, want %q", next.RRule, tt.wantRRule)
+			}
+			// The rule must stay valid for the occurrence after this one.
+			if _, err := parseRRule(next.RRule); err != nil {
+				t.Errorf("next rrule %q does not parse: %v", next.RRule, err)
+			}
+		})
+	}
+}
+
+func TestNextOccurrenceCarriesOverFields(t *testing.T) {
+	due := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	projectID := primitive.NewObjectID()
+	todo := &models.Todo{
+		Title:       "Water plants",
+		Description: "Balcony and kitchen",
+		Status:      models.TodoStatusDone,
+		Priority:    models.PriorityHigh,
+		Labels:      []string{"home", "weekly"},
+		ProjectID:   &projectID,
+		DueAt:       &due,
+		Timezone:    "UTC",
+		RRule:       "FREQ=WEEKLY",
+		RepeatFrom:  models.RepeatFromDue,
+		UserID:      primitive.NewObjectID(),
+		Checklist:   []models.ChecklistItem{{ID: primitive.NewObjectID(), Text: "Balcony", Done: true}},
+	}
+	next, err := nextOccurrence(todo, due)
+	if err != nil {
+		t.Fatalf("nextOccurrence: %v", err)
+	}
+	if next.Title != todo.Title || next.Description != todo.Description {
+		t.Errorf("title/description = %q/%q, want %q/%q", next.Title, next.Description, todo.Title, todo.Description)
+	}
+	if next.Priority != todo.Priority {
+		t.Errorf("priority = %v, want %v", next.Priority, todo.Priority)
+	}
+	if !reflect.DeepEqual(next.Labels, todo.Labels) {
+		t.Errorf("labels = %v, want %v", next.Labels, todo.Labels)
+	}
+	if next.ProjectID == nil || *next.ProjectID != projectID || next.UserID != todo.UserID {
+		t.Error("project or user not carried over")
+	}
+	if next.Status != models.TodoStatusOpen {
+		t.Errorf("status = %q, want open", next.Status)
+	}
+	if len(next.Checklist) != 1 || next.Checklist[0].Done || next.Checklist[0].ID == todo.Checklist[0].ID {
+		t.Errorf("checklist = %+v, want one reset item with a new id", next.Checklist)
+	}
+	if want := due.AddDate(0, 0, 7); !next.DueAt.Equal(want) {
+		t.Errorf("due = %v, want %v", next.DueAt, want)
+	}
+}
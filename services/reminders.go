@@ -0,0 +1,57 @@
+package services
+
+import (
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidReminder is returned when a reminder is neither absolute nor a non-negative offset.
+var ErrInvalidReminder = apperrors.Validation("invalid_reminder", "a reminder needs either at or a non-negative offset_minutes")
+
+// ErrReminderNeedsDueDate is returned when a reminder is relative to a todo without a due date.
+var ErrReminderNeedsDueDate = apperrors.Validation("reminder_needs_due_date", "reminders with offset_minutes need a due_at")
+
+// normalizeReminders validates the todo's reminders and computes when each
+// fires. Reminders keep their delivery state (sent, retried or failed) as
+// long as their fire time is unchanged, so editing a todo does not resend
+// them, while rescheduling one starts its delivery afresh.
+func normalizeReminders(todo *models.Todo, previous []models.Reminder) error {
+	sent := make(map[primitive.ObjectID]models.Reminder, len(previous))
+	for _, r := range previous {
+		sent[r.ID] = r
+	}
+	for i := range todo.Reminders {
+		reminder := &todo.Reminders[i]
+		if (reminder.At == nil) == (reminder.OffsetMinutes == nil) {
+			return ErrInvalidReminder
+		}
+		if reminder.OffsetMinutes != nil {
+			if *reminder.OffsetMinutes < 0 {
+				return ErrInvalidReminder
+			}
+			if todo.DueAt == nil {
+				return ErrReminderNeedsDueDate
+			}
+		}
+		reminder.FireAt = reminderFireAt(*reminder, todo.DueAt)
+		if reminder.ID.IsZero() {
+			reminder.ID = primitive.NewObjectID()
+		}
+		reminder.SentAt, reminder.Attempts, reminder.RetryAt, reminder.FailedAt = nil, 0, nil, nil
+		if old, ok := sent[reminder.ID]; ok && old.FireAt.Equal(reminder.FireAt) {
+			reminder.SentAt, reminder.Attempts, reminder.RetryAt, reminder.FailedAt = old.SentAt, old.Attempts, old.RetryAt, old.FailedAt
+		}
+	}
+	return nil
+}
+
+// reminderFireAt returns when a reminder fires for a todo due at dueAt.
+func reminderFireAt(reminder models.Reminder, dueAt *time.Time) time.Time {
+	if reminder.At != nil {
+		return *reminder.At
+	}
+	return dueAt.Add(-time.Duration(*reminder.OffsetMinutes) * time.Minute)
+}
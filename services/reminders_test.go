@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNormalizeReminders(t *testing.T) {
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := time.Date(2026, 4, 30, 18, 0, 0, 0, time.UTC)
+	offset, negative := 30, -5
+	tests := []struct {
+		name    string
+		dueAt   *time.Time
+		in      models.Reminder
+		want    time.Time
+		wantErr error
+	}{
+		{name: "absolute", in: models.Reminder{At: &at}, want: at},
+		{name: "relative", dueAt: &due, in: models.Reminder{OffsetMinutes: &offset}, want: due.Add(-30 * time.Minute)},
+		{name: "both", dueAt: &due, in: models.Reminder{At: &at, OffsetMinutes: &offset}, wantErr: ErrInvalidReminder},
+		{name: "neither", in: models.Reminder{}, wantErr: ErrInvalidReminder},
+		{name: "negative offset", dueAt: &due, in: models.Reminder{OffsetMinutes: &negative}, wantErr: ErrInvalidReminder},
+		{name: "relative without due date", in: models.Reminder{OffsetMinutes: &offset}, wantErr: ErrReminderNeedsDueDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &models.Todo{DueAt: tt.dueAt, Reminders: []models.Reminder{tt.in}}
+			err := normalizeReminders(todo, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeReminders() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := todo.Reminders[0]
+			if !got.FireAt.Equal(tt.want) || got.ID.IsZero() {
+				t.Errorf("normalizeReminders() = fire_at %v, id %v; want %v and a new id", got.FireAt, got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRemindersKeepsDeliveryState(t *testing.T) {
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	sentAt := due.Add(-time.Hour)
+	offset := 60
+	previous := []models.Reminder{{
+		ID:            primitive.NewObjectID(),
+		OffsetMinutes: &offset,
+		FireAt:        due.Add(-time.Hour),
+		SentAt:        &sentAt,
+		Attempts:      2,
+	}}
+
+	// Editing the todo without moving the reminder keeps it sent.
+	todo := &models.Todo{DueAt: &due, Reminders: []models.Reminder{{ID: previous[0].ID, OffsetMinutes: &offset}}}
+	if err := normalizeReminders(todo, previous); err != nil {
+		t.Fatalf("normalizeReminders() error = %v", err)
+	}
+	if got := todo.Reminders[0]; got.SentAt == nil || got.Attempts != 2 {
+		t.Errorf("unchanged reminder = sent_at %v, attempts %d; want the previous delivery state", got.SentAt, got.Attempts)
+	}
+
+	// Moving the due date reschedules the reminder and starts delivery afresh.
+	later := due.Add(24 * time.Hour)
+	failedAt := due
+	previous[0].FailedAt = &failedAt
+	todo = &models.Todo{DueAt: &later, Reminders: []models.Reminder{{ID: previous[0].ID, OffsetMinutes: &offset, SentAt: &sentAt, Attempts: 9}}}
+	if err := normalizeReminders(todo, previous); err != nil {
+		t.Fatalf("normalizeReminders() error = %v", err)
+	}
+	if got := todo.Reminders[0]; got.SentAt != nil || got.FailedAt != nil || got.Attempts != 0 {
+		t.Errorf("rescheduled reminder = sent_at %v, failed_at %v, attempts %d; want a fresh delivery", got.SentAt, got.FailedAt, got.Attempts)
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"sync"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+)
+
+// RevocationService revokes access tokens before they expire and answers
+// whether a token is still usable. Lookups are cached in memory: a revoked
+// token stays cached until it expires, while "not revoked" answers and
+// per-user cutoffs are cached for TokenConfig.RevocationCacheTTL, which
+// bounds how long a revocation made by another instance goes unnoticed.
+type RevocationService interface {
+	// RevokeToken revokes the access token with the given jti.
+	RevokeToken(jti string, userID string, expiresAt time.Time) error
+	// RevokeUser revokes every access token of the user issued until now.
+	RevokeUser(userID string) error
+	// IsRevoked reports whether a token of userID with the given jti and
+	// issue time has been revoked.
+	IsRevoked(jti string, userID string, issuedAt time.Time) (bool, error)
+}
+
+type cachedRevocation struct {
+	revoked bool
+	until   time.Time
+}
+
+type cachedCutoff struct {
+	revokedBefore time.Time
+	until         time.Time
+}
+
+// maxRevocationCacheEntries bounds each cache; expired entries are dropped
+// when it is reached.
+const maxRevocationCacheEntries = 10000
+
+type revocationService struct {
+	repo        repository.RevocationRepository
+	tokenConfig TokenConfig
+
+	mu      sync.Mutex
+	tokens  map[string]cachedRevocation
+	cutoffs map[string]cachedCutoff
+}
+
+// NewRevocationService returns a new instance of RevocationService.
+func NewRevocationService(repo repository.RevocationRepository, tokenConfig TokenConfig) RevocationService {
+	return &revocationService{
+		repo:        repo,
+		tokenConfig: tokenConfig,
+		tokens:      make(map[string]cachedRevocation),
+		cutoffs:     make(map[string]cachedCutoff),
+	}
+}
+
+func (s *revocationService) RevokeToken(jti string, userID string, expiresAt time.Time) error {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.RevokeToken(&models.RevokedToken{ID: jti, UserID: userObjID, ExpiresAt: expiresAt}); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheToken(jti, cachedRevocation{revoked: true, until: expiresAt})
+	return nil
+}
+
+func (s *revocationService) RevokeUser(userID string) error {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return err
+	}
+	// Issue times are carried with millisecond precision.
+	now := time.Now().Truncate(time.Millisecond)
+	revocation := &models.UserTokenRevocation{
+		UserID:        userObjID,
+		RevokedBefore: now,
+		// Tokens issued before now have all expired by then.
+		ExpiresAt: now.Add(s.tokenConfig.AccessTokenTTL),
+	}
+	if err := s.repo.RevokeUserTokens(revocation); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheCutoff(userID, cachedCutoff{revokedBefore: now, until: now.Add(s.tokenConfig.RevocationCacheTTL)})
+	return nil
+}
+
+func (s *revocationService) IsRevoked(jti string, userID string, issuedAt time.Time) (bool, error) {
+	revokedBefore, err := s.userCutoff(userID)
+	if err != nil {
+		return false, err
+	}
+	if !revokedBefore.IsZero() && !issuedAt.After(revokedBefore) {
+		return true, nil
+	}
+	return s.tokenRevoked(jti)
+}
+
+func (s *revocationService) userCutoff(userID string) (time.Time, error) {
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.cutoffs[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.until) {
+		return cached.revokedBefore, nil
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	revokedBefore, err := s.repo.UserTokensRevokedBefore(userObjID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheCutoff(userID, cachedCutoff{revokedBefore: revokedBefore, until: now.Add(s.tokenConfig.RevocationCacheTTL)})
+	return revokedBefore, nil
+}
+
+func (s *revocationService) tokenRevoked(jti string) (bool, error) {
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.tokens[jti]
+	s.mu.Unlock()
+	if ok && now.Before(cached.until) {
+		return cached.revoked, nil
+	}
+	revoked, err := s.repo.IsTokenRevoked(jti)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A revoked token stays revoked; by the time the access token lifetime
+	// has passed it has expired anyway.
+	until := now.Add(s.tokenConfig.RevocationCacheTTL)
+	if revoked {
+		until = now.Add(s.tokenConfig.AccessTokenTTL)
+	}
+	s.cacheToken(jti, cachedRevocation{revoked: revoked, until: until})
+	return revoked, nil
+}
+
+// cacheToken stores a token entry. The caller must hold s.mu.
+func (s *revocationService) cacheToken(jti string, entry cachedRevocation) {
+	if len(s.tokens) >= maxRevocationCacheEntries {
+		now := time.Now()
+		for k, v := range s.tokens {
+			if !now.Before(v.until) {
+				delete(s.tokens, k)
+			}
+		}
+	}
+	if len(s.tokens) < maxRevocationCacheEntries {
+		s.tokens[jti] = entry
+	}
+}
+
+// cacheCutoff stores a user cutoff entry. The caller must hold s.mu.
+func (s *revocationService) cacheCutoff(userID string, entry cachedCutoff) {
+	if len(s.cutoffs) >= maxRevocationCacheEntries {
+		now := time.Now()
+		for k, v := range s.cutoffs {
+			if !now.Before(v.until) {
+				delete(s.cutoffs, k)
+			}
+		}
+	}
+	if len(s.cutoffs) < maxRevocationCacheEntries {
+		s.cutoffs[userID] = entry
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeRevocations keeps revocations in memory and counts lookups, standing
+// in for the collection shared by every instance.
+type fakeRevocations struct {
+	repository.RevocationRepository
+	tokens        map[string]bool
+	cutoffs       map[primitive.ObjectID]time.Time
+	tokenLookups  int
+	cutoffLookups int
+}
+
+func newFakeRevocations() *fakeRevocations {
+	return &fakeRevocations{tokens: map[string]bool{}, cutoffs: map[primitive.ObjectID]time.Time{}}
+}
+
+func (r *fakeRevocations) RevokeToken(token *models.RevokedToken) error {
+	r.tokens[token.ID] = true
+	return nil
+}
+
+func (r *fakeRevocations) IsTokenRevoked(jti string) (bool, error) {
+	r.tokenLookups++
+	return r.tokens[jti], nil
+}
+
+func (r *fakeRevocations) RevokeUserTokens(revocation *models.UserTokenRevocation) error {
+	if revocation.RevokedBefore.After(r.cutoffs[revocation.UserID]) {
+		r.cutoffs[revocation.UserID] = revocation.RevokedBefore
+	}
+	return nil
+}
+
+func (r *fakeRevocations) UserTokensRevokedBefore(userID primitive.ObjectID) (time.Time, error) {
+	r.cutoffLookups++
+	return r.cutoffs[userID], nil
+}
+
+func TestRevokeTokenRevokesOnlyThatToken(t *testing.T) {
+	repo := newFakeRevocations()
+	s := NewRevocationService(repo, DefaultTokenConfig)
+	userID := primitive.NewObjectID().Hex()
+	now := time.Now()
+
+	if err := s.RevokeToken("a", userID, now.Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+	for jti, want := range map[string]bool{"a": true, "b": false} {
+		if revoked, err := s.IsRevoked(jti, userID, now); err != nil || revoked != want {
+			t.Errorf("IsRevoked(%s) = %v, %v; want %v", jti, revoked, err, want)
+		}
+	}
+	if repo.tokenLookups != 1 {
+		t.Errorf("looked up revocations %d times, want the revoked token answered from the cache", repo.tokenLookups)
+	}
+}
+
+func TestRevokeUserUsesIssueTime(t *testing.T) {
+	s := NewRevocationService(newFakeRevocations(), DefaultTokenConfig)
+	userID := primitive.NewObjectID().Hex()
+	before := time.Now().Add(-time.Second).Truncate(time.Millisecond)
+
+	if err := s.RevokeUser(userID); err != nil {
+		t.Fatalf("RevokeUser() error = %v", err)
+	}
+	after := time.Now().Add(time.Millisecond).Truncate(time.Millisecond)
+	if revoked, err := s.IsRevoked("old", userID, before); err != nil || !revoked {
+		t.Errorf("IsRevoked(issued before) = %v, %v; want revoked", revoked, err)
+	}
+	if revoked, err := s.IsRevoked("new", userID, after); err != nil || revoked {
+		t.Errorf("IsRevoked(issued after) = %v, %v; want not revoked", revoked, err)
+	}
+	if revoked, err := s.IsRevoked("other", primitive.NewObjectID().Hex(), before); err != nil || revoked {
+		t.Errorf("IsRevoked(other user) = %v, %v; want not revoked", revoked, err)
+	}
+}
+
+func TestRevocationCacheBoundsStaleness(t *testing.T) {
+	repo := newFakeRevocations()
+	cfg := DefaultTokenConfig
+	cfg.RevocationCacheTTL = 50 * time.Millisecond
+	// Two instances share the repository but cache separately.
+	s, other := NewRevocationService(repo, cfg), NewRevocationService(repo, cfg)
+	userID := primitive.NewObjectID()
+	issuedAt := time.Now().Add(-time.Second)
+
+	if revoked, _ := s.IsRevoked("t", userID.Hex(), issuedAt); revoked {
+		t.Fatal("token revoked before any revocation")
+	}
+	lookups := repo.cutoffLookups
+	if err := other.RevokeUser(userID.Hex()); err != nil {
+		t.Fatalf("RevokeUser() error = %v", err)
+	}
+	if revoked, _ := s.IsRevoked("t", userID.Hex(), issuedAt); revoked || repo.cutoffLookups != lookups {
+		t.Errorf("IsRevoked() within the cache TTL = %v after %d new lookups, want the cached answer", revoked, repo.cutoffLookups-lookups)
+	}
+	time.Sleep(cfg.RevocationCacheTTL)
+	if revoked, _ := s.IsRevoked("t", userID.Hex(), issuedAt); !revoked {
+		t.Error("IsRevoked() after the cache TTL did not see the other instance's revocation")
+	}
+	if revoked, _ := other.IsRevoked("t", userID.Hex(), issuedAt); !revoked {
+		t.Error("the revoking instance did not see its own revocation at once")
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// purposeKey derives the signing key for one kind of token from the JWT
+// secret, so that a token issued for one purpose is never accepted for another.
+func purposeKey(purpose string) []byte {
+	mac := hmac.New(sha256.New, JWTSecret())
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// signToken returns a stateless token carrying claims: their BSON encoding
+// followed by an HMAC-SHA256 signature, both base64url encoded.
+func signToken(purpose string, claims interface{}) (string, error) {
+	payload, err := bson.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, purposeKey(purpose))
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// parseSignedToken verifies a token produced by signToken for the same
+// purpose and decodes its claims. It reports whether the token is valid;
+// checking expiry is left to the caller.
+func parseSignedToken(purpose, token string, claims interface{}) bool {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, purposeKey(purpose))
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return false
+	}
+	return bson.Unmarshal(payload, claims) == nil
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"todo-list-api/query"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidSmartListName is returned when a smart list is given an empty name.
+var ErrInvalidSmartListName = apperrors.Validation("invalid_smart_list_name", "smart list name must not be empty")
+
+// ErrBuiltInSmartList is returned when trying to change or delete a built-in smart list.
+var ErrBuiltInSmartList = apperrors.Forbidden("built_in_smart_list", "built-in smart lists cannot be changed or deleted")
+
+// ErrInvalidOrder is returned when a sort direction other than asc or desc is saved.
+var ErrInvalidOrder = apperrors.Validation("invalid_order", "invalid order, expected asc or desc")
+
+// builtInSmartLists are the virtual lists every user has. Their filters are
+// resolved when opened, so "today" always means the current day.
+var builtInSmartLists = []models.SmartList{
+	{
+		Key:  models.SmartListToday,
+		Name: "Today",
+		Filter: models.SmartListFilter{
+			Query: "due<=today AND status!=done AND status!=cancelled",
+			Sort:  models.SortByDueAt,
+		},
+	},
+	{
+		Key:  models.SmartListUpcoming,
+		Name: "Upcoming",
+		Filter: models.SmartListFilter{
+			Query: "due>today AND status!=done AND status!=cancelled",
+			Sort:  models.SortByDueAt,
+		},
+	},
+	{
+		Key:  models.SmartListOverdue,
+		Name: "Overdue",
+		Filter: models.SmartListFilter{
+			Due:  models.DueOverdue,
+			Sort: models.SortByDueAt,
+		},
+	},
+}
+
+// IsBuiltInSmartList reports whether key names a built-in smart list.
+func IsBuiltInSmartList(key string) bool {
+	for _, list := range builtInSmartLists {
+		if list.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SmartListService is the business logic layer for managing smart lists.
+type SmartListService interface {
+	CreateSmartList(list *models.SmartList) error
+	UpdateSmartList(list *models.SmartList) error
+	DeleteSmartList(id string, userID string) error
+	GetSmartLists(userID string) ([]models.SmartList, error)
+	GetSmartListByID(id string, userID string) (*models.SmartList, error)
+}
+
+type smartListService struct {
+	smartListRepo repository.SmartListRepository
+	projectRepo   repository.ProjectRepository
+	userRepo      repository.UserRepository
+}
+
+// NewSmartListService returns a new instance of SmartListService.
+func NewSmartListService(smartListRepo repository.SmartListRepository, projectRepo repository.ProjectRepository, userRepo repository.UserRepository) SmartListService {
+	return &smartListService{smartListRepo, projectRepo, userRepo}
+}
+
+func (s *smartListService) CreateSmartList(list *models.SmartList) error {
+	if err := s.validateSmartList(list); err != nil {
+		return err
+	}
+	return s.smartListRepo.Create(list)
+}
+
+func (s *smartListService) UpdateSmartList(list *models.SmartList) error {
+	if err := s.validateSmartList(list); err != nil {
+		return err
+	}
+	existing, err := s.smartListRepo.GetByID(list.ID, list.UserID)
+	if err != nil {
+		return err
+	}
+	list.CreatedAt = existing.CreatedAt
+	return s.smartListRepo.Update(list)
+}
+
+func (s *smartListService) DeleteSmartList(id string, userID string) error {
+	if IsBuiltInSmartList(id) {
+		return ErrBuiltInSmartList
+	}
+	listID, err := ParseID(id, ErrSmartListNotFound)
+	if err != nil {
+		return err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return err
+	}
+	return s.smartListRepo.Delete(listID, userObjID)
+}
+
+// GetSmartLists returns the built-in lists followed by the user's own lists in creation order.
+func (s *smartListService) GetSmartLists(userID string) ([]models.SmartList, error) {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := s.smartListRepo.GetSmartLists(userObjID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(userObjID)
+	if err != nil {
+		return nil, err
+	}
+	lists := make([]models.SmartList, 0, len(builtInSmartLists)+len(custom))
+	for _, list := range builtInSmartLists {
+		lists = append(lists, builtInSmartList(list, user))
+	}
+	return append(lists, custom...), nil
+}
+
+// GetSmartListByID returns one of the user's smart lists, or a built-in list when id is its key.
+func (s *smartListService) GetSmartListByID(id string, userID string) (*models.SmartList, error) {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range builtInSmartLists {
+		if list.Key == id {
+			user, err := s.userRepo.FindByID(userObjID)
+			if err != nil {
+				return nil, err
+			}
+			list = builtInSmartList(list, user)
+			return &list, nil
+		}
+	}
+	listID, err := ParseID(id, ErrSmartListNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return s.smartListRepo.GetByID(listID, userObjID)
+}
+
+// builtInSmartList returns the user's copy of a built-in list. Its days
+// follow the timezone the user registered with.
+func builtInSmartList(list models.SmartList, user *models.User) models.SmartList {
+	list.UserID = user.ID
+	list.Filter.Timezone = user.Timezone
+	return list
+}
+
+// rewriteSmartListQueries passes every comparison in the filter expressions
+// of the user's smart lists to fn, as query.Rewrite does, and saves the
+// expressions it changed. Expressions that no longer parse are left alone.
+func rewriteSmartListQueries(smartListRepo repository.SmartListRepository, userID primitive.ObjectID, fn func(*query.Comparison) bool) error {
+	lists, err := smartListRepo.GetSmartLists(userID)
+	if err != nil {
+		return err
+	}
+	for _, list := range lists {
+		if list.Filter.Query == "" {
+			continue
+		}
+		rewritten, changed, err := query.Rewrite(list.Filter.Query, fn)
+		if err != nil || !changed {
+			continue
+		}
+		if err := smartListRepo.SetQuery(list.ID, userID, list.Filter.Query, rewritten); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSmartList checks the name and that the saved filter can be executed.
+func (s *smartListService) validateSmartList(list *models.SmartList) error {
+	list.Key = ""
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" {
+		return ErrInvalidSmartListName
+	}
+	f := &list.Filter
+	if f.Order != "" && f.Order != "asc" && f.Order != "desc" {
+		return ErrInvalidOrder
+	}
+	filter := f.TodoFilter()
+	if err := prepareFilter(&filter); err != nil {
+		return err
+	}
+	if f.Query != "" {
+		loc, err := LoadLocation(f.Timezone)
+		if err != nil {
+			return err
+		}
+		if _, err := query.Compile(f.Query, query.Options{Location: loc}); err != nil {
+			return err
+		}
+	}
+	if f.Search = strings.TrimSpace(f.Search); f.Search != "" {
+		if _, err := parseSearchQuery(f.Search); err != nil {
+			return err
+		}
+	}
+	if f.ProjectID != nil {
+		if _, err := s.projectRepo.GetByID(*f.ProjectID, list.UserID); err != nil {
+			if errors.Is(err, repository.ErrProjectNotFound) {
+				return ErrUnknownProject
+			}
+			return err
+		}
+	}
+	return nil
+}
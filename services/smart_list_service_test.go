@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"todo-list-api/models"
+	"todo-list-api/query"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeSmartLists keeps smart lists in memory and applies the cascades the
+// way the MongoDB updates do.
+type fakeSmartLists struct {
+	repository.SmartListRepository
+	lists []models.SmartList
+}
+
+func (r *fakeSmartLists) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.SmartList, error) {
+	for _, list := range r.lists {
+		if list.ID == id && list.UserID == userID {
+			return &list, nil
+		}
+	}
+	return nil, repository.ErrSmartListNotFound
+}
+
+func (r *fakeSmartLists) GetSmartLists(userID primitive.ObjectID) ([]models.SmartList, error) {
+	var lists []models.SmartList
+	for _, list := range r.lists {
+		if list.UserID == userID {
+			lists = append(lists, list)
+		}
+	}
+	return lists, nil
+}
+
+func (r *fakeSmartLists) RenameLabel(userID primitive.ObjectID, oldName, newName string) error {
+	for i := range r.lists {
+		for j, name := range r.lists[i].Filter.Labels {
+			if r.lists[i].UserID == userID && name == oldName {
+				r.lists[i].Filter.Labels[j] = newName
+			}
+		}
+	}
+	return nil
+}
+
+func (r *fakeSmartLists) RemoveLabel(userID primitive.ObjectID, name string) error {
+	for i := range r.lists {
+		if r.lists[i].UserID != userID {
+			continue
+		}
+		var kept []string
+		for _, label := range r.lists[i].Filter.Labels {
+			if label != name {
+				kept = append(kept, label)
+			}
+		}
+		r.lists[i].Filter.Labels = kept
+	}
+	return nil
+}
+
+func (r *fakeSmartLists) ReplaceProject(userID primitive.ObjectID, oldID primitive.ObjectID, newID *primitive.ObjectID) error {
+	for i := range r.lists {
+		if f := &r.lists[i].Filter; r.lists[i].UserID == userID && f.ProjectID != nil && *f.ProjectID == oldID {
+			f.ProjectID = newID
+		}
+	}
+	return nil
+}
+
+func (r *fakeSmartLists) SetQuery(id primitive.ObjectID, userID primitive.ObjectID, oldQuery, newQuery string) error {
+	for i := range r.lists {
+		if r.lists[i].ID == id && r.lists[i].UserID == userID && r.lists[i].Filter.Query == oldQuery {
+			r.lists[i].Filter.Query = newQuery
+		}
+	}
+	return nil
+}
+
+// fakeSmartListUsers returns a single user.
+type fakeSmartListUsers struct {
+	repository.UserRepository
+	user models.User
+}
+
+func (r *fakeSmartListUsers) FindByID(id primitive.ObjectID) (*models.User, error) {
+	if id != r.user.ID {
+		return nil, repository.ErrUserNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func TestBuiltInSmartListsUseUserTimezone(t *testing.T) {
+	user := models.User{ID: primitive.NewObjectID(), Timezone: "Asia/Tokyo"}
+	s := &smartListService{smartListRepo: &fakeSmartLists{}, userRepo: &fakeSmartListUsers{user: user}}
+
+	lists, err := s.GetSmartLists(user.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetSmartLists() error = %v", err)
+	}
+	if len(lists) != len(builtInSmartLists) {
+		t.Fatalf("GetSmartLists() returned %d lists, want the %d built-in ones", len(lists), len(builtInSmartLists))
+	}
+	for _, list := range lists {
+		if list.Filter.Timezone != user.Timezone || list.UserID != user.ID {
+			t.Errorf("built-in list %q has timezone %q for user %v, want %q for %v", list.Key, list.Filter.Timezone, list.UserID, user.Timezone, user.ID)
+		}
+	}
+	today, err := s.GetSmartListByID(models.SmartListToday, user.ID.Hex())
+	if err != nil || today.Filter.Timezone != user.Timezone {
+		t.Errorf("GetSmartListByID(today) = %+v, %v; want timezone %q", today, err, user.Timezone)
+	}
+	if builtInSmartLists[0].Filter.Timezone != "" {
+		t.Errorf("resolving a user's list changed the shared built-in list: %+v", builtInSmartLists[0])
+	}
+}
+
+func TestLabelChangesRewriteSmartLists(t *testing.T) {
+	userID := primitive.NewObjectID()
+	work := models.Label{ID: primitive.NewObjectID(), Name: "work", UserID: userID}
+	home := models.Label{ID: primitive.NewObjectID(), Name: "home", UserID: userID}
+	lists := &fakeSmartLists{lists: []models.SmartList{
+		{ID: primitive.NewObjectID(), UserID: userID, Filter: models.SmartListFilter{Labels: []string{"work", "home"}, Query: "label:work OR label:home"}},
+		{ID: primitive.NewObjectID(), UserID: userID, Filter: models.SmartListFilter{Query: "NOT label:home AND priority>=high"}},
+		{ID: primitive.NewObjectID(), UserID: userID, Filter: models.SmartListFilter{Query: "label:home"}},
+	}}
+	s := &labelService{labelRepo: &fakeLabels{labels: []models.Label{work, home}}, todoRepo: &fakeLabelTodos{}, smartListRepo: lists}
+
+	renamed := work
+	renamed.Name = "day job"
+	if err := s.UpdateLabel(&renamed); err != nil {
+		t.Fatalf("UpdateLabel(rename) error = %v", err)
+	}
+	if err := s.DeleteLabel(home.ID.Hex(), userID.Hex()); err != nil {
+		t.Fatalf("DeleteLabel() error = %v", err)
+	}
+
+	want := []models.SmartListFilter{
+		{Labels: []string{"day job"}, Query: `label:"day job"`},
+		{Query: "priority>=high"},
+		{},
+	}
+	for i, list := range lists.lists {
+		if !reflect.DeepEqual(list.Filter, want[i]) {
+			t.Errorf("smart list %d filter = %+v, want %+v", i, list.Filter, want[i])
+		}
+	}
+}
+
+func TestProjectDeletionUpdatesSmartLists(t *testing.T) {
+	for _, mode := range []string{ProjectDeleteMove, ProjectDeleteCascade} {
+		t.Run(mode, func(t *testing.T) {
+			userID := primitive.NewObjectID()
+			inbox := models.Project{ID: primitive.NewObjectID(), Inbox: true, UserID: userID}
+			garden := models.Project{ID: primitive.NewObjectID(), Name: "Garden", UserID: userID}
+			other := primitive.NewObjectID()
+			lists := &fakeSmartLists{lists: []models.SmartList{
+				{ID: primitive.NewObjectID(), UserID: userID, Filter: models.SmartListFilter{ProjectID: &garden.ID, Query: "project:" + garden.ID.Hex() + " OR project:" + other.Hex()}},
+				{ID: primitive.NewObjectID(), UserID: userID, Filter: models.SmartListFilter{ProjectID: &other}},
+			}}
+			s := &projectService{projectRepo: &fakeProjects{projects: []models.Project{inbox, garden}}, todoRepo: &fakeProjectTodos{}, smartListRepo: lists}
+
+			if err := s.DeleteProject(garden.ID.Hex(), userID.Hex(), mode); err != nil {
+				t.Fatalf("DeleteProject() error = %v", err)
+			}
+			want := models.SmartListFilter{Query: "project:" + other.Hex()}
+			if mode == ProjectDeleteMove {
+				want = models.SmartListFilter{ProjectID: &inbox.ID, Query: "project:" + inbox.ID.Hex() + " OR project:" + other.Hex()}
+			}
+			if got := lists.lists[0].Filter; !reflect.DeepEqual(got, want) {
+				t.Errorf("filter on the deleted project = %+v, want %+v", got, want)
+			}
+			if got := lists.lists[1].Filter.ProjectID; got == nil || *got != other {
+				t.Errorf("filter on another project = %v, want %v kept", got, other)
+			}
+		})
+	}
+}
+
+func TestValidateSmartList(t *testing.T) {
+	userID := primitive.NewObjectID()
+	project := models.Project{ID: primitive.NewObjectID(), UserID: userID}
+	unknown := primitive.NewObjectID()
+	s := &smartListService{projectRepo: &fakeProjects{projects: []models.Project{project}}}
+
+	var queryErr *query.Error
+	tests := []struct {
+		name   string
+		list   models.SmartList
+		wantOK func(error) bool
+	}{
+		{"valid", models.SmartList{Name: " Work ", Filter: models.SmartListFilter{Query: "label:work", ProjectID: &project.ID, Order: "desc"}}, func(err error) bool { return err == nil }},
+		{"blank name", models.SmartList{Name: " "}, func(err error) bool { return errors.Is(err, ErrInvalidSmartListName) }},
+		{"bad order", models.SmartList{Name: "x", Filter: models.SmartListFilter{Order: "up"}}, func(err error) bool { return errors.Is(err, ErrInvalidOrder) }},
+		{"bad query", models.SmartList{Name: "x", Filter: models.SmartListFilter{Query: "label:"}}, func(err error) bool { return errors.As(err, &queryErr) }},
+		{"bad timezone", models.SmartList{Name: "x", Filter: models.SmartListFilter{Query: "due:today", Timezone: "Mars/Base"}}, func(err error) bool { return errors.Is(err, ErrInvalidTimezone) }},
+		{"unknown project", models.SmartList{Name: "x", Filter: models.SmartListFilter{ProjectID: &unknown}}, func(err error) bool { return errors.Is(err, ErrUnknownProject) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := tt.list
+			list.UserID = userID
+			if err := s.validateSmartList(&list); !tt.wantOK(err) {
+				t.Errorf("validateSmartList() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidChecklistItem is returned when a checklist item has no text.
+var ErrInvalidChecklistItem = apperrors.Validation("invalid_checklist_item", "checklist item text must not be empty")
+
+// ErrChecklistItemNotFound is returned when a checklist item does not exist on the todo.
+var ErrChecklistItemNotFound = apperrors.NotFound("checklist_item_not_found", "checklist item not found")
+
+// ErrInvalidChecklistOrder is returned when a reorder request does not list every item exactly once.
+var ErrInvalidChecklistOrder = apperrors.Validation("invalid_checklist_order", "item_ids must list every checklist item exactly once")
+
+// ChecklistItemUpdate holds the optional changes to a checklist item.
+type ChecklistItemUpdate struct {
+	Text *string
+	Done *bool
+}
+
+// AddChecklistItem inserts an item into the todo's checklist at item.Position,
+// or at the end when the position is out of range.
+func (s *todoService) AddChecklistItem(id string, userID string, item models.ChecklistItem) (*models.Todo, error) {
+	item.Text = strings.TrimSpace(item.Text)
+	if item.Text == "" {
+		return nil, ErrInvalidChecklistItem
+	}
+	item.ID = primitive.NewObjectID()
+	return s.modifyChecklist(id, userID, func(items []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		pos := item.Position
+		if pos < 0 || pos > len(items) {
+			pos = len(items)
+		}
+		items = append(items, models.ChecklistItem{})
+		copy(items[pos+1:], items[pos:])
+		items[pos] = item
+		return items, nil
+	})
+}
+
+// UpdateChecklistItem changes the text and/or done flag of a checklist item.
+func (s *todoService) UpdateChecklistItem(id string, userID string, itemID string, update ChecklistItemUpdate) (*models.Todo, error) {
+	if update.Text != nil {
+		text := strings.TrimSpace(*update.Text)
+		if text == "" {
+			return nil, ErrInvalidChecklistItem
+		}
+		update.Text = &text
+	}
+	return s.modifyChecklist(id, userID, func(items []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		i, err := findChecklistItem(items, itemID)
+		if err != nil {
+			return nil, err
+		}
+		if update.Text != nil {
+			items[i].Text = *update.Text
+		}
+		if update.Done != nil {
+			items[i].Done = *update.Done
+		}
+		return items, nil
+	})
+}
+
+// ToggleChecklistItem flips the done flag of a checklist item.
+func (s *todoService) ToggleChecklistItem(id string, userID string, itemID string) (*models.Todo, error) {
+	return s.modifyChecklist(id, userID, func(items []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		i, err := findChecklistItem(items, itemID)
+		if err != nil {
+			return nil, err
+		}
+		items[i].Done = !items[i].Done
+		return items, nil
+	})
+}
+
+// ReorderChecklist puts the checklist items in the order of itemIDs, which
+// must contain every item exactly once.
+func (s *todoService) ReorderChecklist(id string, userID string, itemIDs []string) (*models.Todo, error) {
+	return s.modifyChecklist(id, userID, func(items []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		if len(itemIDs) != len(items) {
+			return nil, ErrInvalidChecklistOrder
+		}
+		ordered := make([]models.ChecklistItem, 0, len(items))
+		seen := make(map[string]bool, len(itemIDs))
+		for _, itemID := range itemIDs {
+			i, err := findChecklistItem(items, itemID)
+			if err != nil || seen[itemID] {
+				return nil, ErrInvalidChecklistOrder
+			}
+			seen[itemID] = true
+			ordered = append(ordered, items[i])
+		}
+		return ordered, nil
+	})
+}
+
+// RemoveChecklistItem deletes an item from the todo's checklist.
+func (s *todoService) RemoveChecklistItem(id string, userID string, itemID string) (*models.Todo, error) {
+	return s.modifyChecklist(id, userID, func(items []models.ChecklistItem) ([]models.ChecklistItem, error) {
+		i, err := findChecklistItem(items, itemID)
+		if err != nil {
+			return nil, err
+		}
+		return append(items[:i], items[i+1:]...), nil
+	})
+}
+
+// modifyChecklist loads the user's todo, applies change to its checklist,
+// renumbers the item positions and saves the result.
+func (s *todoService) modifyChecklist(id string, userID string, change func([]models.ChecklistItem) ([]models.ChecklistItem, error)) (*models.Todo, error) {
+	todoID, err := ParseID(id, ErrTodoNotFound)
+	if err != nil {
+		return nil, err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.todoRepo.GetByID(todoID, userObjID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := change(todo.Checklist)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Position = i
+	}
+	updated, err := s.todoRepo.SetChecklist(todoID, userObjID, items)
+	if err != nil {
+		return nil, err
+	}
+	updated.UpdateProgress()
+	return updated, nil
+}
+
+// normalizeChecklist validates a checklist supplied with a whole todo,
+// assigning IDs to new items and renumbering positions in their given order.
+func normalizeChecklist(todo *models.Todo) error {
+	sort.SliceStable(todo.Checklist, func(i, j int) bool {
+		return todo.Checklist[i].Position < todo.Checklist[j].Position
+	})
+	for i := range todo.Checklist {
+		item := &todo.Checklist[i]
+		item.Text = strings.TrimSpace(item.Text)
+		if item.Text == "" {
+			return ErrInvalidChecklistItem
+		}
+		if item.ID.IsZero() {
+			item.ID = primitive.NewObjectID()
+		}
+		item.Position = i
+	}
+	return nil
+}
+
+func findChecklistItem(items []models.ChecklistItem, itemID string) (int, error) {
+	for i, item := range items {
+		if item.ID.Hex() == itemID {
+			return i, nil
+		}
+	}
+	return -1, ErrChecklistItemNotFound
+}
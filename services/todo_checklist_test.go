@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"todo-list-api/dto"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeChecklistTodos keeps a single todo in memory.
+type fakeChecklistTodos struct {
+	repository.TodoRepository
+	todo models.Todo
+}
+
+func (r *fakeChecklistTodos) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Todo, error) {
+	if id != r.todo.ID || userID != r.todo.UserID {
+		return nil, repository.ErrTodoNotFound
+	}
+	todo := r.todo
+	todo.Checklist = append([]models.ChecklistItem(nil), r.todo.Checklist...)
+	return &todo, nil
+}
+
+func (r *fakeChecklistTodos) SetChecklist(id primitive.ObjectID, userID primitive.ObjectID, items []models.ChecklistItem) (*models.Todo, error) {
+	if id != r.todo.ID || userID != r.todo.UserID {
+		return nil, repository.ErrTodoNotFound
+	}
+	r.todo.Checklist = items
+	todo := r.todo
+	return &todo, nil
+}
+
+func newChecklistFixture(texts ...string) (*todoService, *fakeChecklistTodos) {
+	todo := models.Todo{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+	for i, text := range texts {
+		todo.Checklist = append(todo.Checklist, models.ChecklistItem{ID: primitive.NewObjectID(), Text: text, Position: i})
+	}
+	todos := &fakeChecklistTodos{todo: todo}
+	return &todoService{todoRepo: todos}, todos
+}
+
+func checklistTexts(todo *models.Todo) []string {
+	var texts []string
+	for i, item := range todo.Checklist {
+		if item.Position != i {
+			return []string{"position mismatch"}
+		}
+		texts = append(texts, item.Text)
+	}
+	return texts
+}
+
+func TestChecklistOperations(t *testing.T) {
+	s, todos := newChecklistFixture("eggs", "milk", "bread")
+	id, userID := todos.todo.ID.Hex(), todos.todo.UserID.Hex()
+	eggs, milk, bread := todos.todo.Checklist[0].ID.Hex(), todos.todo.Checklist[1].ID.Hex(), todos.todo.Checklist[2].ID.Hex()
+
+	todo, err := s.AddChecklistItem(id, userID, models.ChecklistItem{Text: " flour ", Position: 1})
+	if err != nil {
+		t.Fatalf("AddChecklistItem() error = %v", err)
+	}
+	if got, want := checklistTexts(todo), []string{"eggs", "flour", "milk", "bread"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after add: %v, want %v", got, want)
+	}
+	todo, _ = s.AddChecklistItem(id, userID, models.ChecklistItem{Text: "salt", Position: 99})
+	if got, want := checklistTexts(todo), []string{"eggs", "flour", "milk", "bread", "salt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after add out of range: %v, want %v", got, want)
+	}
+
+	todo, err = s.ToggleChecklistItem(id, userID, milk)
+	if err != nil {
+		t.Fatalf("ToggleChecklistItem() error = %v", err)
+	}
+	if todo.Progress == nil || *todo.Progress != (models.Progress{Done: 1, Total: 5}) {
+		t.Errorf("progress after toggle = %+v, want 1/5", todo.Progress)
+	}
+
+	done, text := false, "rye bread"
+	todo, err = s.UpdateChecklistItem(id, userID, milk, ChecklistItemUpdate{Done: &done})
+	if err != nil || todo.Progress.Done != 0 {
+		t.Errorf("UpdateChecklistItem(done=false) = %+v, %v", todo.Progress, err)
+	}
+	todo, _ = s.UpdateChecklistItem(id, userID, bread, ChecklistItemUpdate{Text: &text})
+	if got := todo.Checklist[3].Text; got != text {
+		t.Errorf("UpdateChecklistItem(text) = %q, want %q", got, text)
+	}
+
+	todo, err = s.RemoveChecklistItem(id, userID, eggs)
+	if err != nil {
+		t.Fatalf("RemoveChecklistItem() error = %v", err)
+	}
+	ids := []string{}
+	for i := len(todo.Checklist) - 1; i >= 0; i-- {
+		ids = append(ids, todo.Checklist[i].ID.Hex())
+	}
+	todo, err = s.ReorderChecklist(id, userID, ids)
+	if err != nil {
+		t.Fatalf("ReorderChecklist() error = %v", err)
+	}
+	if got, want := checklistTexts(todo), []string{"salt", "rye bread", "milk", "flour"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after reorder: %v, want %v", got, want)
+	}
+}
+
+func TestChecklistErrors(t *testing.T) {
+	s, todos := newChecklistFixture("eggs", "milk")
+	id, userID := todos.todo.ID.Hex(), todos.todo.UserID.Hex()
+	eggs, milk := todos.todo.Checklist[0].ID.Hex(), todos.todo.Checklist[1].ID.Hex()
+	blank := "  "
+
+	tests := []struct {
+		name string
+		call func() (*models.Todo, error)
+		want error
+	}{
+		{"add blank", func() (*models.Todo, error) {
+			return s.AddChecklistItem(id, userID, models.ChecklistItem{Text: " "})
+		}, ErrInvalidChecklistItem},
+		{"update blank", func() (*models.Todo, error) {
+			return s.UpdateChecklistItem(id, userID, eggs, ChecklistItemUpdate{Text: &blank})
+		}, ErrInvalidChecklistItem},
+		{"toggle unknown", func() (*models.Todo, error) {
+			return s.ToggleChecklistItem(id, userID, primitive.NewObjectID().Hex())
+		}, ErrChecklistItemNotFound},
+		{"remove unknown", func() (*models.Todo, error) {
+			return s.RemoveChecklistItem(id, userID, "nope")
+		}, ErrChecklistItemNotFound},
+		{"reorder missing item", func() (*models.Todo, error) {
+			return s.ReorderChecklist(id, userID, []string{eggs})
+		}, ErrInvalidChecklistOrder},
+		{"reorder duplicate item", func() (*models.Todo, error) {
+			return s.ReorderChecklist(id, userID, []string{eggs, eggs})
+		}, ErrInvalidChecklistOrder},
+		{"reorder unknown item", func() (*models.Todo, error) {
+			return s.ReorderChecklist(id, userID, []string{eggs, "nope"})
+		}, ErrInvalidChecklistOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if got := []string{todos.todo.Checklist[0].ID.Hex(), todos.todo.Checklist[1].ID.Hex()}; !reflect.DeepEqual(got, []string{eggs, milk}) {
+		t.Errorf("failed operations changed the checklist: %v", got)
+	}
+}
+
+func TestNormalizeChecklist(t *testing.T) {
+	kept := primitive.NewObjectID()
+	todo := &models.Todo{Checklist: []models.ChecklistItem{
+		{Text: "third", Position: 7},
+		{ID: kept, Text: " first ", Position: 0},
+		{Text: "second", Position: 3},
+	}}
+	if err := normalizeChecklist(todo); err != nil {
+		t.Fatalf("normalizeChecklist() error = %v", err)
+	}
+	if got, want := checklistTexts(todo), []string{"first", "second", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeChecklist() = %v, want %v", got, want)
+	}
+	if todo.Checklist[0].ID != kept || todo.Checklist[1].ID.IsZero() || todo.Checklist[2].ID.IsZero() {
+		t.Errorf("normalizeChecklist() ids = %v, want existing kept and new ones assigned", todo.Checklist)
+	}
+
+	todo = &models.Todo{Checklist: []models.ChecklistItem{{Text: "ok"}, {Text: " "}}}
+	if err := normalizeChecklist(todo); !errors.Is(err, ErrInvalidChecklistItem) {
+		t.Errorf("normalizeChecklist(blank item) = %v, want ErrInvalidChecklistItem", err)
+	}
+}
+
+func TestTodoServiceHidesOtherUsersTodos(t *testing.T) {
+	s, todos := newChecklistFixture("eggs")
+	id, owner := todos.todo.ID.Hex(), todos.todo.UserID.Hex()
+	other := primitive.NewObjectID().Hex()
+	item := todos.todo.Checklist[0].ID.Hex()
+
+	if _, err := s.GetTodoByID(id, owner); err != nil {
+		t.Fatalf("GetTodoByID(owner) error = %v", err)
+	}
+	calls := map[string]func() (*models.Todo, error){
+		"GetTodoByID":         func() (*models.Todo, error) { return s.GetTodoByID(id, other) },
+		"AddChecklistItem":    func() (*models.Todo, error) { return s.AddChecklistItem(id, other, models.ChecklistItem{Text: "milk"}) },
+		"ToggleChecklistItem": func() (*models.Todo, error) { return s.ToggleChecklistItem(id, other, item) },
+		"RemoveChecklistItem": func() (*models.Todo, error) { return s.RemoveChecklistItem(id, other, item) },
+	}
+	for name, call := range calls {
+		if todo, err := call(); !errors.Is(err, repository.ErrTodoNotFound) {
+			t.Errorf("%s by another user = %v, %v; want not found", name, todo, err)
+		}
+	}
+	if todo, err := s.UpdateTodo(id, other, &dto.TodoRequest{Title: "Mine now"}); !errors.Is(err, repository.ErrTodoNotFound) {
+		t.Errorf("UpdateTodo by another user = %v, %v; want not found", todo, err)
+	}
+	if todos.todo.Checklist[0].Done || len(todos.todo.Checklist) != 1 {
+		t.Errorf("another user changed the checklist: %+v", todos.todo.Checklist)
+	}
+}
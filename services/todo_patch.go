@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"todo-list-api/apperrors"
+	"todo-list-api/dto"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+	"todo-list-api/validation"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Patch document formats accepted by PatchTodo, identified by their media types.
+const (
+	// PatchTypeMerge is a JSON Merge Patch (RFC 7396).
+	PatchTypeMerge = "application/merge-patch+json"
+	// PatchTypeJSON is a JSON Patch (RFC 6902).
+	PatchTypeJSON = "application/json-patch+json"
+)
+
+// ErrUnsupportedPatchType is returned when a patch is not in a supported format.
+var ErrUnsupportedPatchType = apperrors.UnsupportedMediaType("unsupported_patch_type", "unsupported patch type, expected application/merge-patch+json or application/json-patch+json")
+
+// ErrInvalidPatch is returned when a patch is malformed or cannot be applied to the todo.
+var ErrInvalidPatch = apperrors.Validation("invalid_patch", "invalid patch")
+
+// ErrPatchTestFailed is returned when a JSON Patch test operation does not match the todo.
+var ErrPatchTestFailed = apperrors.Conflict("patch_test_failed", "patch test operation failed")
+
+// ErrReadOnlyField is returned when a patch changes a field managed by the server.
+var ErrReadOnlyField = apperrors.Validation("read_only_field", "field is read-only")
+
+// readOnlyTodoFields are the JSON fields of a todo that patches must not change.
+var readOnlyTodoFields = map[string]bool{
+	"id":                 true,
+	"user_id":            true,
+	"created_at":         true,
+	"updated_at":         true,
+	"completed_at":       true,
+	"next_occurrence_id": true,
+	"progress":           true,
+}
+
+// derivedTodoFields lists, per patched field, the stored fields that are
+// recomputed from it and must be written along with it.
+var derivedTodoFields = map[string][]string{
+	"status":      {"completed_at"},
+	"due_at":      {"reminders"},
+	"rrule":       {"repeat_from"},
+	"repeat_from": {"rrule"},
+}
+
+// maxPatchAttempts bounds how often a patch is reapplied when the todo
+// changes between reading and writing it.
+const maxPatchAttempts = 3
+
+// PatchTodo applies a JSON Merge Patch or JSON Patch to one of the user's
+// todos, as represented in responses. The patched todo is validated like a
+// full update, but only the fields the patch changed are written. If another
+// request updates the todo in the meantime, the patch is reapplied to the new
+// version, so neither change is lost.
+func (s *todoService) PatchTodo(id string, userID string, patchType string, patch []byte) (*models.Todo, error) {
+	for attempt := 1; ; attempt++ {
+		todo, err := s.patchTodo(id, userID, patchType, patch)
+		if !errors.Is(err, repository.ErrTodoModified) || attempt == maxPatchAttempts {
+			return todo, err
+		}
+	}
+}
+
+// patchTodo makes one attempt at PatchTodo. It fails with
+// repository.ErrTodoModified if the todo changed since it was read.
+func (s *todoService) patchTodo(id string, userID string, patchType string, patch []byte) (*models.Todo, error) {
+	existing, err := s.GetTodoByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := json.Marshal(dto.NewTodoResponse(existing))
+	if err != nil {
+		return nil, err
+	}
+	patched, err := applyPatch(patchType, original, patch)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := changedTodoFields(original, patched)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return existing, nil
+	}
+
+	var doc dto.TodoResponse
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	req := doc.Request()
+	if err := validation.Struct(&req); err != nil {
+		return nil, err
+	}
+	todo := *existing
+	req.ApplyTo(&todo)
+	if err := s.prepareTodo(&todo, existing.Reminders); err != nil {
+		return nil, err
+	}
+	if err := s.todoRepo.Patch(&todo, withDerivedFields(fields), existing.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleNextOccurrence(&todo); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// applyPatch applies patch to the JSON document doc according to patchType.
+func applyPatch(patchType string, doc, patch []byte) ([]byte, error) {
+	switch patchType {
+	case PatchTypeMerge:
+		patched, err := jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return patched, nil
+	case PatchTypeJSON:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		patched, err := ops.Apply(doc)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, fmt.Errorf("%w: %v", ErrPatchTestFailed, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return patched, nil
+	default:
+		return nil, ErrUnsupportedPatchType
+	}
+}
+
+// changedTodoFields returns the top-level fields that differ between the
+// original and patched todo documents, rejecting changes to read-only fields.
+func changedTodoFields(original, patched []byte) ([]string, error) {
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return nil, fmt.Errorf("%w: patched document must be an object", ErrInvalidPatch)
+	}
+	var fields []string
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if readOnlyTodoFields[field] {
+			return nil, fmt.Errorf("%w: %s", ErrReadOnlyField, field)
+		}
+	}
+	return fields, nil
+}
+
+// withDerivedFields adds the stored fields recomputed from the changed fields.
+func withDerivedFields(fields []string) []string {
+	all := append([]string(nil), fields...)
+	for _, field := range fields {
+		all = append(all, derivedTodoFields[field]...)
+	}
+	return all
+}
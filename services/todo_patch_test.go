@@ -0,0 +1,223 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"todo-list-api/dto"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestApplyPatch(t *testing.T) {
+	doc := []byte(`{"title":"Buy milk","priority":"low","labels":["errands"]}`)
+	tests := []struct {
+		name      string
+		patchType string
+		patch     string
+		want      string
+		wantErr   error
+	}{
+		{"merge", PatchTypeMerge, `{"title":"Buy oat milk","labels":null}`, `{"title":"Buy oat milk","priority":"low"}`, nil},
+		{"json patch", PatchTypeJSON, `[{"op":"replace","path":"/priority","value":"high"},{"op":"add","path":"/labels/-","value":"home"}]`, `{"title":"Buy milk","priority":"high","labels":["errands","home"]}`, nil},
+		{"passing test", PatchTypeJSON, `[{"op":"test","path":"/title","value":"Buy milk"}]`, string(doc), nil},
+		{"failing test", PatchTypeJSON, `[{"op":"test","path":"/title","value":"Buy bread"}]`, "", ErrPatchTestFailed},
+		{"bad pointer", PatchTypeJSON, `[{"op":"remove","path":"/missing"}]`, "", ErrInvalidPatch},
+		{"json patch not an array", PatchTypeJSON, `{"title":"x"}`, "", ErrInvalidPatch},
+		{"merge not json", PatchTypeMerge, `{`, "", ErrInvalidPatch},
+		{"plain json", "application/json", `{"title":"x"}`, "", ErrUnsupportedPatchType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyPatch(tt.patchType, doc, []byte(tt.patch))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyPatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !jsonEqual(t, got, []byte(tt.want)) {
+				t.Errorf("applyPatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangedTodoFields(t *testing.T) {
+	original := []byte(`{"id":"1","title":"Buy milk","status":"open","due_at":"2026-05-01T09:00:00Z"}`)
+	tests := []struct {
+		name    string
+		patched string
+		want    []string
+		wantErr error
+	}{
+		{"unchanged", `{"id":"1","title":"Buy milk","status":"open","due_at":"2026-05-01T09:00:00Z"}`, nil, nil},
+		{"changed and removed", `{"id":"1","title":"Buy bread","status":"open"}`, []string{"due_at", "title"}, nil},
+		{"added", `{"id":"1","title":"Buy milk","status":"open","due_at":"2026-05-01T09:00:00Z","rrule":"FREQ=DAILY"}`, []string{"rrule"}, nil},
+		{"read-only", `{"id":"2","title":"Buy milk","status":"open","due_at":"2026-05-01T09:00:00Z"}`, nil, ErrReadOnlyField},
+		{"not an object", `["id"]`, nil, ErrInvalidPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changedTodoFields(original, []byte(tt.patched))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("changedTodoFields() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedTodoFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got, want := withDerivedFields([]string{"status", "title"}), []string{"status", "title", "completed_at"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("withDerivedFields() = %v, want %v", got, want)
+	}
+}
+
+// fakePatchTodos stores one todo. Before each of the first `interfere`
+// writes, it lets another request change the todo first.
+type fakePatchTodos struct {
+	repository.TodoRepository
+	todo      models.Todo
+	interfere int
+	writes    int
+}
+
+func (r *fakePatchTodos) GetByID(id primitive.ObjectID, userID primitive.ObjectID) (*models.Todo, error) {
+	if id != r.todo.ID || userID != r.todo.UserID {
+		return nil, repository.ErrTodoNotFound
+	}
+	todo := r.todo
+	return &todo, nil
+}
+
+func (r *fakePatchTodos) Patch(todo *models.Todo, fields []string, unmodifiedSince time.Time) error {
+	if r.interfere > 0 {
+		r.interfere--
+		r.todo.Description += " (edited elsewhere)"
+		r.todo.UpdatedAt = r.todo.UpdatedAt.Add(time.Second)
+	}
+	if !r.todo.UpdatedAt.Equal(unmodifiedSince) {
+		return repository.ErrTodoModified
+	}
+	r.writes++
+	for _, field := range fields {
+		switch field {
+		case "title":
+			r.todo.Title = todo.Title
+		case "description":
+			r.todo.Description = todo.Description
+		}
+	}
+	r.todo.UpdatedAt = r.todo.UpdatedAt.Add(time.Second)
+	return nil
+}
+
+func (r *fakePatchTodos) Update(todo *models.Todo, unmodifiedSince time.Time) error {
+	if r.interfere > 0 {
+		r.interfere--
+		r.todo.Description += " (edited elsewhere)"
+		r.todo.UpdatedAt = r.todo.UpdatedAt.Add(time.Second)
+	}
+	if !r.todo.UpdatedAt.Equal(unmodifiedSince) {
+		return repository.ErrTodoModified
+	}
+	r.writes++
+	r.todo = *todo
+	r.todo.UpdatedAt = unmodifiedSince.Add(time.Second)
+	return nil
+}
+
+func TestUpdateTodoReplacesTodo(t *testing.T) {
+	userID := primitive.NewObjectID()
+	inbox := models.Project{ID: primitive.NewObjectID(), Inbox: true, UserID: userID}
+	garden := models.Project{ID: primitive.NewObjectID(), UserID: userID}
+	completed := time.Date(2026, 4, 30, 18, 0, 0, 0, time.UTC)
+	todos := &fakePatchTodos{interfere: 1, todo: models.Todo{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Title:       "Plant tulips",
+		Status:      models.TodoStatusDone,
+		CompletedAt: &completed,
+		ProjectID:   &garden.ID,
+		Checklist:   []models.ChecklistItem{{ID: primitive.NewObjectID(), Text: "Buy bulbs"}},
+		CreatedAt:   time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+	}}
+	s := &todoService{todoRepo: todos, projectRepo: &fakeProjects{projects: []models.Project{inbox, garden}}}
+
+	todo, err := s.UpdateTodo(todos.todo.ID.Hex(), userID.Hex(), &dto.TodoRequest{Title: "Plant daffodils"})
+	if err != nil {
+		t.Fatalf("UpdateTodo() error = %v", err)
+	}
+	if todos.writes != 1 {
+		t.Errorf("UpdateTodo() wrote %d times, want once after retrying the conflict", todos.writes)
+	}
+	stored := todos.todo
+	if stored.Title != "Plant daffodils" || stored.Description != "" || stored.Status != models.TodoStatusOpen || stored.CompletedAt != nil {
+		t.Errorf("stored todo = %+v, want the request's title with other fields reset", stored)
+	}
+	if stored.ProjectID == nil || *stored.ProjectID != inbox.ID || stored.Checklist != nil {
+		t.Errorf("stored project %v and checklist %v, want the inbox and no checklist", stored.ProjectID, stored.Checklist)
+	}
+	if stored.ID != todo.ID || stored.UserID != userID || !stored.CreatedAt.Equal(todo.CreatedAt) {
+		t.Errorf("UpdateTodo() changed server-managed fields: %+v", stored)
+	}
+}
+
+func TestPatchTodoReappliesAfterConcurrentUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		interfere int
+		wantErr   error
+	}{
+		{"no conflict", 0, nil},
+		{"one conflict", 1, nil},
+		{"keeps conflicting", maxPatchAttempts, repository.ErrTodoModified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := primitive.NewObjectID()
+			inbox := models.Project{ID: primitive.NewObjectID(), Inbox: true, UserID: userID}
+			todos := &fakePatchTodos{interfere: tt.interfere, todo: models.Todo{
+				ID:          primitive.NewObjectID(),
+				UserID:      userID,
+				Title:       "Buy milk",
+				Description: "Semi-skimmed",
+				Status:      models.TodoStatusOpen,
+				ProjectID:   &inbox.ID,
+				UpdatedAt:   time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+			}}
+			s := &todoService{todoRepo: todos, projectRepo: &fakeProjects{projects: []models.Project{inbox}}}
+
+			todo, err := s.PatchTodo(todos.todo.ID.Hex(), userID.Hex(), PatchTypeMerge, []byte(`{"title":"Buy oat milk"}`))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PatchTodo() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if todos.writes != 0 {
+					t.Errorf("PatchTodo() wrote %d times despite the conflict", todos.writes)
+				}
+				return
+			}
+			if todos.writes != 1 || todos.todo.Title != "Buy oat milk" {
+				t.Errorf("stored title = %q after %d writes, want the patch applied once", todos.todo.Title, todos.writes)
+			}
+			if todo.Description != todos.todo.Description {
+				t.Errorf("PatchTodo() description = %q, want the concurrent change %q kept", todo.Description, todos.todo.Description)
+			}
+		})
+	}
+}
+
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var x, y interface{}
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatalf("unmarshal %s: %v", a, err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatalf("unmarshal %s: %v", b, err)
+	}
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+	"unicode"
+)
+
+// ErrEmptySearch is returned when a search query has no terms.
+var ErrEmptySearch = apperrors.Validation("empty_search", "search query must not be empty")
+
+// ErrInvalidSearch is returned when a search query cannot be parsed.
+var ErrInvalidSearch = apperrors.Validation("invalid_search", "invalid search query")
+
+// snippetContext is the number of characters kept on each side of the first
+// match when a description is shortened to a snippet.
+const snippetContext = 60
+
+// SearchTodos returns a page of the user's todos matching a full-text query,
+// ranked by relevance, together with highlighted snippets of the matches.
+func (s *todoService) SearchTodos(userID string, query string, filter models.TodoFilter, page, limit int64) ([]models.TodoSearchResult, int64, error) {
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	terms, err := parseSearchQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := prepareFilter(&filter); err != nil {
+		return nil, 0, err
+	}
+	results, total, err := s.todoRepo.Search(userObjID, models.TodoSearch{Terms: terms, Filter: filter}, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range results {
+		results[i].UpdateProgress()
+		results[i].Highlights = highlightTodo(&results[i].Todo, terms)
+	}
+	return results, total, nil
+}
+
+// parseSearchQuery splits a search query into terms. Words are separated by
+// whitespace, "quoted phrases" match as a whole, a leading - excludes a term
+// and a title: or description: prefix restricts it to that field.
+func parseSearchQuery(query string) ([]models.SearchTerm, error) {
+	var terms []models.SearchTerm
+	rest := strings.TrimSpace(query)
+	for rest != "" {
+		var term models.SearchTerm
+		if strings.HasPrefix(rest, "-") {
+			term.Negated = true
+			rest = rest[1:]
+		}
+		for _, field := range []string{models.SearchFieldTitle, models.SearchFieldDescription} {
+			if len(rest) > len(field) && strings.EqualFold(rest[:len(field)+1], field+":") {
+				term.Field = field
+				rest = rest[len(field)+1:]
+				break
+			}
+		}
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated quoted phrase", ErrInvalidSearch)
+			}
+			term.Text = strings.TrimSpace(rest[1 : end+1])
+			term.Phrase = true
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			term.Text = strings.ReplaceAll(rest[:end], `"`, "")
+			rest = rest[end:]
+		}
+		if term.Text != "" {
+			terms = append(terms, term)
+		}
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+	if len(terms) == 0 {
+		return nil, ErrEmptySearch
+	}
+	return terms, nil
+}
+
+// highlightTodo builds snippets for the title and description of todo that
+// contain any of the non-negated search terms.
+func highlightTodo(todo *models.Todo, terms []models.SearchTerm) map[string]string {
+	highlights := map[string]string{}
+	if snippet, ok := highlight(todo.Title, termsFor(terms, models.SearchFieldTitle), 0); ok {
+		highlights[models.SearchFieldTitle] = snippet
+	}
+	if snippet, ok := highlight(todo.Description, termsFor(terms, models.SearchFieldDescription), snippetContext); ok {
+		highlights[models.SearchFieldDescription] = snippet
+	}
+	if len(highlights) == 0 {
+		return nil
+	}
+	return highlights
+}
+
+// termsFor returns the lower-cased texts of the non-negated terms that apply to field.
+func termsFor(terms []models.SearchTerm, field string) [][]rune {
+	var texts [][]rune
+	for _, term := range terms {
+		if !term.Negated && (term.Field == "" || term.Field == field) {
+			texts = append(texts, []rune(strings.ToLower(term.Text)))
+		}
+	}
+	return texts
+}
+
+// highlight HTML-escapes text and wraps every case-insensitive occurrence of
+// the terms in <mark>. With a positive context the result is cut down to that
+// many characters around the first match. It reports false when nothing matches.
+func highlight(text string, terms [][]rune, context int) (string, bool) {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		if len(term) == 0 {
+			continue
+		}
+		for i := 0; i+len(term) <= len(lower); i++ {
+			if string(lower[i:i+len(term)]) != string(term) {
+				continue
+			}
+			for j := i; j < i+len(term); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	start, end := 0, len(runes)
+	if context > 0 {
+		start = max(0, first-context)
+		end = min(len(runes), first+context)
+		for end < len(runes) && marked[end] {
+			end++
+		}
+	}
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; i++ {
+		if marked[i] && (i == start || !marked[i-1]) {
+			b.WriteString("<mark>")
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		if marked[i] && (i == end-1 || !marked[i+1]) {
+			b.WriteString("</mark>")
+		}
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
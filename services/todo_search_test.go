@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"todo-list-api/models"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	title, description := models.SearchFieldTitle, models.SearchFieldDescription
+	tests := []struct {
+		query string
+		want  []models.SearchTerm
+	}{
+		{"milk", []models.SearchTerm{{Text: "milk"}}},
+		{"  buy   milk ", []models.SearchTerm{{Text: "buy"}, {Text: "milk"}}},
+		{`"buy milk" eggs`, []models.SearchTerm{{Text: "buy milk", Phrase: true}, {Text: "eggs"}}},
+		{`" buy milk "`, []models.SearchTerm{{Text: "buy milk", Phrase: true}}},
+		{"milk -eggs", []models.SearchTerm{{Text: "milk"}, {Text: "eggs", Negated: true}}},
+		{`-"oat milk"`, []models.SearchTerm{{Text: "oat milk", Phrase: true, Negated: true}}},
+		{"title:milk", []models.SearchTerm{{Text: "milk", Field: title}}},
+		{"Description:eggs", []models.SearchTerm{{Text: "eggs", Field: description}}},
+		{`-title:"oat milk"`, []models.SearchTerm{{Text: "oat milk", Phrase: true, Negated: true, Field: title}}},
+		{"status:open", []models.SearchTerm{{Text: "status:open"}}},
+		{`mi"lk`, []models.SearchTerm{{Text: "milk"}}},
+		{`milk "" -`, []models.SearchTerm{{Text: "milk"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := parseSearchQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parseSearchQuery(%q) error = %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  error
+	}{
+		{"", ErrEmptySearch},
+		{"   ", ErrEmptySearch},
+		{`""`, ErrEmptySearch},
+		{"- -", ErrEmptySearch},
+		{"title:", ErrEmptySearch},
+		{`"buy milk`, ErrInvalidSearch},
+		{`eggs title:"oat milk`, ErrInvalidSearch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := parseSearchQuery(tt.query)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("parseSearchQuery(%q) = %+v, %v; want %v", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
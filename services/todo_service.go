@@ -1,62 +1,392 @@
 package services
 
 import (
+	"errors"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/dto"
 	"todo-list-api/models"
 	"todo-list-api/repository"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrInvalidStatus is returned when a to-do item is given an unknown status.
+var ErrInvalidStatus = apperrors.Validation("invalid_status", "invalid status")
+
+// ErrStartAfterDue is returned when a to-do item starts after it is due.
+var ErrStartAfterDue = apperrors.Validation("start_after_due", "start_at must not be after due_at")
+
+// ErrInvalidPriority is returned when a to-do item is given an unknown priority.
+var ErrInvalidPriority = apperrors.Validation("invalid_priority", "invalid priority")
+
+// ErrInvalidSort is returned when listing is requested in an unsupported order.
+var ErrInvalidSort = apperrors.Validation("invalid_sort", "invalid sort field, expected priority, due_at, created_at, updated_at or title")
+
+// ErrInvalidLabelMatch is returned when an unknown label matching mode is requested.
+var ErrInvalidLabelMatch = apperrors.Validation("invalid_label_match", "invalid label_match, expected any or all")
+
+// ErrInvalidDueFilter is returned when an unknown due date window is requested.
+var ErrInvalidDueFilter = apperrors.Validation("invalid_due_filter", "invalid due filter, expected overdue, today, week or none")
+
 // TodoService is the business logic layer for managing Todo items.
 type TodoService interface {
 	CreateTodo(todo *models.Todo) error
-	UpdateTodo(todo *models.Todo) error
+	UpdateTodo(id string, userID string, req *dto.TodoRequest) (*models.Todo, error)
+	PatchTodo(id string, userID string, patchType string, patch []byte) (*models.Todo, error)
 	DeleteTodo(id string, userID string) error
-	GetTodos(userID string, page, limit int64) ([]models.Todo, int64, error)
-	GetTodoByID(id string) (*models.Todo, error)
+	GetTodos(userID string, filter models.TodoFilter, page models.PageRequest) (*models.TodoPage, error)
+	GetTodoByID(id string, userID string) (*models.Todo, error)
+	SearchTodos(userID string, query string, filter models.TodoFilter, page, limit int64) ([]models.TodoSearchResult, int64, error)
+	CompleteTodo(id string, userID string) (*models.Todo, error)
+	ReopenTodo(id string, userID string) (*models.Todo, error)
+	AddChecklistItem(id string, userID string, item models.ChecklistItem) (*models.Todo, error)
+	UpdateChecklistItem(id string, userID string, itemID string, update ChecklistItemUpdate) (*models.Todo, error)
+	ToggleChecklistItem(id string, userID string, itemID string) (*models.Todo, error)
+	ReorderChecklist(id string, userID string, itemIDs []string) (*models.Todo, error)
+	RemoveChecklistItem(id string, userID string, itemID string) (*models.Todo, error)
 }
 
 type todoService struct {
-	todoRepo repository.TodoRepository
+	todoRepo    repository.TodoRepository
+	labelRepo   repository.LabelRepository
+	projectRepo repository.ProjectRepository
 }
 
 // NewTodoService returns a new instance of TodoService.
-func NewTodoService(todoRepo repository.TodoRepository) TodoService {
-	return &todoService{todoRepo}
+func NewTodoService(todoRepo repository.TodoRepository, labelRepo repository.LabelRepository, projectRepo repository.ProjectRepository) TodoService {
+	return &todoService{todoRepo, labelRepo, projectRepo}
 }
 
 func (s *todoService) CreateTodo(todo *models.Todo) error {
+	if todo.Status == "" {
+		todo.Status = models.TodoStatusOpen
+	}
+	if err := s.prepareTodo(todo, nil); err != nil {
+		return err
+	}
 	return s.todoRepo.Create(todo)
 }
 
-func (s *todoService) UpdateTodo(todo *models.Todo) error {
-	return s.todoRepo.Update(todo)
+// UpdateTodo replaces the client-controlled fields of one of the user's todos
+// with the request's. If another request updates the todo in the meantime,
+// the replacement is retried on the new version.
+func (s *todoService) UpdateTodo(id string, userID string, req *dto.TodoRequest) (*models.Todo, error) {
+	for attempt := 1; ; attempt++ {
+		todo, err := s.updateTodo(id, userID, req)
+		if !errors.Is(err, repository.ErrTodoModified) || attempt == maxPatchAttempts {
+			return todo, err
+		}
+	}
+}
+
+// updateTodo makes one attempt at UpdateTodo. It fails with
+// repository.ErrTodoModified if the todo changed since it was read.
+func (s *todoService) updateTodo(id string, userID string, req *dto.TodoRequest) (*models.Todo, error) {
+	existing, err := s.GetTodoByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	todo := *existing
+	req.ApplyTo(&todo)
+	if err := s.prepareTodo(&todo, existing.Reminders); err != nil {
+		return nil, err
+	}
+	if err := s.todoRepo.Update(&todo, existing.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleNextOccurrence(&todo); err != nil {
+		return nil, err
+	}
+	return &todo, nil
 }
 
 func (s *todoService) DeleteTodo(id string, userID string) error {
-	todoID, err := primitive.ObjectIDFromHex(id)
+	todoID, err := ParseID(id, ErrTodoNotFound)
 	if err != nil {
 		return err
 	}
-	userObjID, err := primitive.ObjectIDFromHex(userID)
+	userObjID, err := ParseUserID(userID)
 	if err != nil {
 		return err
 	}
 	return s.todoRepo.Delete(todoID, userObjID)
 }
 
-func (s *todoService) GetTodos(userID string, page, limit int64) ([]models.Todo, int64, error) {
-	userObjID, err := primitive.ObjectIDFromHex(userID)
+// GetTodos returns a page of the user's todos, either by page number or,
+// in keyset mode, by signed cursor.
+func (s *todoService) GetTodos(userID string, filter models.TodoFilter, page models.PageRequest) (*models.TodoPage, error) {
+	userObjID, err := ParseUserID(userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+	if err := prepareFilter(&filter); err != nil {
+		return nil, err
+	}
+	result := &models.TodoPage{}
+	if page.Keyset {
+		var after *models.TodoCursor
+		if page.Cursor != "" {
+			if after, err = decodeCursor(page.Cursor); err != nil {
+				return nil, err
+			}
+			sort := filter.Sort
+			if sort.Field == "" {
+				sort.Field = models.SortByCreatedAt
+			}
+			if after.Sort != sort {
+				return nil, ErrInvalidCursor
+			}
+		}
+		todos, next, prev, err := s.todoRepo.GetTodosAfter(userObjID, filter, after, page.Limit)
+		if err != nil {
+			return nil, err
+		}
+		result.Todos = todos
+		if result.NextCursor, err = encodeCursor(next); err != nil {
+			return nil, err
+		}
+		if result.PrevCursor, err = encodeCursor(prev); err != nil {
+			return nil, err
+		}
+	} else {
+		todos, err := s.todoRepo.GetTodos(userObjID, filter, page.Page, page.Limit)
+		if err != nil {
+			return nil, err
+		}
+		result.Todos = todos
 	}
-	return s.todoRepo.GetTodos(userObjID, page, limit)
+	if page.Count {
+		total, err := s.todoRepo.CountTodos(userObjID, filter)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+	for i := range result.Todos {
+		result.Todos[i].UpdateProgress()
+	}
+	return result, nil
 }
 
-func (s *todoService) GetTodoByID(id string) (*models.Todo, error) {
-	todoID, err := primitive.ObjectIDFromHex(id)
+// GetTodoByID returns one of the user's todos; todos of other users are not found.
+func (s *todoService) GetTodoByID(id string, userID string) (*models.Todo, error) {
+	todoID, err := ParseID(id, ErrTodoNotFound)
 	if err != nil {
 		return nil, err
 	}
-	return s.todoRepo.GetByID(todoID)
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.todoRepo.GetByID(todoID, userObjID)
+	if err != nil {
+		return nil, err
+	}
+	todo.UpdateProgress()
+	return todo, nil
+}
+
+// CompleteTodo marks a to-do item as done and records when it was completed.
+// Completing a recurring todo also creates its next occurrence.
+func (s *todoService) CompleteTodo(id string, userID string) (*models.Todo, error) {
+	now := time.Now()
+	todo, err := s.setStatus(id, userID, models.TodoStatusDone, &now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.scheduleNextOccurrence(todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// ReopenTodo moves a to-do item back to the open state and clears its completion time.
+func (s *todoService) ReopenTodo(id string, userID string) (*models.Todo, error) {
+	return s.setStatus(id, userID, models.TodoStatusOpen, nil)
+}
+
+func (s *todoService) setStatus(id string, userID string, status string, completedAt *time.Time) (*models.Todo, error) {
+	todoID, err := ParseID(id, ErrTodoNotFound)
+	if err != nil {
+		return nil, err
+	}
+	userObjID, err := ParseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.todoRepo.SetStatus(todoID, userObjID, status, completedAt)
+	if err != nil {
+		return nil, err
+	}
+	todo.UpdateProgress()
+	return todo, nil
+}
+
+// scheduleNextOccurrence creates the next occurrence of a completed recurring
+// todo. Each todo spawns at most one occurrence, so completing it again after
+// reopening does not create duplicates.
+func (s *todoService) scheduleNextOccurrence(todo *models.Todo) error {
+	if todo.Status != models.TodoStatusDone || todo.RRule == "" || todo.NextOccurrenceID != nil {
+		return nil
+	}
+	completedAt := time.Now()
+	if todo.CompletedAt != nil {
+		completedAt = *todo.CompletedAt
+	}
+	next, err := nextOccurrence(todo, completedAt)
+	if err != nil || next == nil {
+		return err
+	}
+	next.UpdateProgress()
+	if err := s.todoRepo.Create(next); err != nil {
+		return err
+	}
+	if err := s.todoRepo.SetNextOccurrence(todo.ID, todo.UserID, next.ID); err != nil {
+		return err
+	}
+	todo.NextOccurrenceID = &next.ID
+	return nil
+}
+
+// prepareTodo validates a todo about to be stored and normalizes the fields
+// derived from others. previous holds the stored reminders of an existing todo.
+func (s *todoService) prepareTodo(todo *models.Todo, previous []models.Reminder) error {
+	if err := applyStatus(todo); err != nil {
+		return err
+	}
+	if err := normalizeReminders(todo, previous); err != nil {
+		return err
+	}
+	if !todo.Priority.IsValid() {
+		return ErrInvalidPriority
+	}
+	if err := validateSchedule(todo); err != nil {
+		return err
+	}
+	if err := s.validateLabels(todo); err != nil {
+		return err
+	}
+	if err := s.assignProject(todo); err != nil {
+		return err
+	}
+	if err := normalizeChecklist(todo); err != nil {
+		return err
+	}
+	if err := validateRecurrence(todo); err != nil {
+		return err
+	}
+	todo.UpdateProgress()
+	return nil
+}
+
+// validateLabels removes duplicate labels and ensures every label exists in the user's catalogue.
+func (s *todoService) validateLabels(todo *models.Todo) error {
+	if len(todo.Labels) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(todo.Labels))
+	names := make([]string, 0, len(todo.Labels))
+	for _, name := range todo.Labels {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	labels, err := s.labelRepo.FindByNames(todo.UserID, names)
+	if err != nil {
+		return err
+	}
+	if len(labels) != len(names) {
+		return ErrUnknownLabel
+	}
+	todo.Labels = names
+	return nil
+}
+
+// assignProject puts todos without a project into the user's inbox and
+// ensures any given project belongs to the user.
+func (s *todoService) assignProject(todo *models.Todo) error {
+	if todo.ProjectID == nil || todo.ProjectID.IsZero() {
+		inbox, err := ensureInbox(s.projectRepo, todo.UserID)
+		if err != nil {
+			return err
+		}
+		todo.ProjectID = &inbox.ID
+		return nil
+	}
+	if _, err := s.projectRepo.GetByID(*todo.ProjectID, todo.UserID); err != nil {
+		if errors.Is(err, repository.ErrProjectNotFound) {
+			return ErrUnknownProject
+		}
+		return err
+	}
+	return nil
+}
+
+// applyStatus validates the todo's status and keeps CompletedAt consistent with it.
+func applyStatus(todo *models.Todo) error {
+	if !models.IsValidTodoStatus(todo.Status) {
+		return ErrInvalidStatus
+	}
+	if todo.Status != models.TodoStatusDone {
+		todo.CompletedAt = nil
+	} else if todo.CompletedAt == nil {
+		now := time.Now()
+		todo.CompletedAt = &now
+	}
+	return nil
+}
+
+// validateSchedule checks the todo's timezone and that it does not start after it is due.
+func validateSchedule(todo *models.Todo) error {
+	if _, err := LoadLocation(todo.Timezone); err != nil {
+		return err
+	}
+	if todo.StartAt != nil && todo.DueAt != nil && todo.StartAt.After(*todo.DueAt) {
+		return ErrStartAfterDue
+	}
+	return nil
+}
+
+// prepareFilter validates the list filter and resolves its due date window.
+func prepareFilter(filter *models.TodoFilter) error {
+	for _, status := range filter.Statuses {
+		if !models.IsValidTodoStatus(status) {
+			return ErrInvalidStatus
+		}
+	}
+	if filter.Sort.Field != "" && !models.IsValidTodoSortField(filter.Sort.Field) {
+		return ErrInvalidSort
+	}
+	if filter.LabelMatch != "" && filter.LabelMatch != models.LabelMatchAny && filter.LabelMatch != models.LabelMatchAll {
+		return ErrInvalidLabelMatch
+	}
+	return resolveDueFilter(filter, time.Now())
+}
+
+// resolveDueFilter turns a predefined due date window into concrete due date
+// bounds, computing day and week boundaries in the filter's timezone.
+func resolveDueFilter(filter *models.TodoFilter, now time.Time) error {
+	loc, err := LoadLocation(filter.Timezone)
+	if err != nil {
+		return err
+	}
+	switch filter.Due {
+	case "":
+	case models.DueOverdue:
+		filter.DueBefore = &now
+		filter.Pending = true
+	case models.DueToday:
+		start := startOfDay(now, loc)
+		end := start.AddDate(0, 0, 1)
+		filter.DueAfter, filter.DueBefore = &start, &end
+	case models.DueThisWeek:
+		start := startOfWeek(now, loc)
+		end := start.AddDate(0, 0, 7)
+		filter.DueAfter, filter.DueBefore = &start, &end
+	case models.DueNone:
+		filter.NoDueDate = true
+	default:
+		return ErrInvalidDueFilter
+	}
+	return nil
 }
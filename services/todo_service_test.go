@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-list-api/models"
+)
+
+func TestApplyStatus(t *testing.T) {
+	completed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		status        string
+		completedAt   *time.Time
+		wantErr       error
+		wantCompleted bool
+		keepCompleted bool
+	}{
+		{name: "open", status: models.TodoStatusOpen},
+		{name: "in progress", status: models.TodoStatusInProgress},
+		{name: "cancelled", status: models.TodoStatusCancelled},
+		{name: "open clears completion", status: models.TodoStatusOpen, completedAt: &completed},
+		{name: "done sets completion", status: models.TodoStatusDone, wantCompleted: true},
+		{name: "done keeps completion", status: models.TodoStatusDone, completedAt: &completed, wantCompleted: true, keepCompleted: true},
+		{name: "unknown", status: "finished", wantErr: ErrInvalidStatus},
+		{name: "empty", status: "", wantErr: ErrInvalidStatus},
+		{name: "wrong case", status: "Done", wantErr: ErrInvalidStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &models.Todo{Status: tt.status, CompletedAt: tt.completedAt}
+			err := applyStatus(todo)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyStatus(%q) error = %v, want %v", tt.status, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := todo.CompletedAt != nil; got != tt.wantCompleted {
+				t.Errorf("applyStatus(%q) CompletedAt = %v, want set %v", tt.status, todo.CompletedAt, tt.wantCompleted)
+			}
+			if tt.keepCompleted && !todo.CompletedAt.Equal(completed) {
+				t.Errorf("applyStatus(%q) CompletedAt = %v, want %v", tt.status, todo.CompletedAt, completed)
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	early := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+	tests := []struct {
+		name string
+		todo models.Todo
+		want error
+	}{
+		{"no dates", models.Todo{}, nil},
+		{"start before due", models.Todo{StartAt: &early, DueAt: &late}, nil},
+		{"start equals due", models.Todo{StartAt: &early, DueAt: &early}, nil},
+		{"start after due", models.Todo{StartAt: &late, DueAt: &early}, ErrStartAfterDue},
+		{"known timezone", models.Todo{Timezone: "UTC"}, nil},
+		{"unknown timezone", models.Todo{Timezone: "Mars/Olympus_Mons"}, ErrInvalidTimezone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSchedule(&tt.todo); !errors.Is(err, tt.want) {
+				t.Errorf("validateSchedule() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDueFilter(t *testing.T) {
+	// Wednesday 23:30 UTC is already Thursday in Berlin.
+	now := time.Date(2026, 3, 4, 23, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name      string
+		filter    models.TodoFilter
+		after     *time.Time
+		before    *time.Time
+		pending   bool
+		noDueDate bool
+	}{
+		{name: "no window", filter: models.TodoFilter{}},
+		{name: "overdue", filter: models.TodoFilter{Due: models.DueOverdue}, before: &now, pending: true},
+		{name: "today", filter: models.TodoFilter{Due: models.DueToday}, after: timePtr(day(4)), before: timePtr(day(5))},
+		{name: "week", filter: models.TodoFilter{Due: models.DueThisWeek}, after: timePtr(day(2)), before: timePtr(day(9))},
+		{name: "none", filter: models.TodoFilter{Due: models.DueNone}, noDueDate: true},
+		{
+			name:   "today in timezone",
+			filter: models.TodoFilter{Due: models.DueToday, Timezone: "Europe/Berlin"},
+			after:  timePtr(time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC)),
+			before: timePtr(time.Date(2026, 3, 5, 23, 0, 0, 0, time.UTC)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			if err := resolveDueFilter(&filter, now); err != nil {
+				t.Fatalf("resolveDueFilter() error = %v", err)
+			}
+			if !equalTime(filter.DueAfter, tt.after) || !equalTime(filter.DueBefore, tt.before) {
+				t.Errorf("resolveDueFilter() window = [%v, %v), want [%v, %v)", filter.DueAfter, filter.DueBefore, tt.after, tt.before)
+			}
+			if filter.Pending != tt.pending || filter.NoDueDate != tt.noDueDate {
+				t.Errorf("resolveDueFilter() pending = %v, no due date = %v; want %v, %v", filter.Pending, filter.NoDueDate, tt.pending, tt.noDueDate)
+			}
+		})
+	}
+
+	if err := resolveDueFilter(&models.TodoFilter{Due: "later"}, now); !errors.Is(err, ErrInvalidDueFilter) {
+		t.Errorf("resolveDueFilter(later) = %v, want ErrInvalidDueFilter", err)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"todo-list-api/apperrors"
+	"todo-list-api/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidRefreshToken is returned when a refresh token is unknown, expired or revoked.
+var ErrInvalidRefreshToken = apperrors.Unauthorized("invalid_refresh_token", "invalid refresh token")
+
+// ErrRefreshTokenReused is returned when a refresh token that was already
+// exchanged is presented again. The token may have been stolen, so every
+// token issued from the same login is revoked.
+var ErrRefreshTokenReused = apperrors.Unauthorized("refresh_token_reused", "refresh token was already used; please log in again")
+
+// TokenConfig holds the lifetimes of issued tokens.
+type TokenConfig struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// RevocationCacheTTL is how long an instance may keep using a cached
+	// "not revoked" answer for an access token.
+	RevocationCacheTTL time.Duration
+	PasswordResetTTL   time.Duration
+	// PasswordResetURL is the page that password reset emails link to; the
+	// token is added as the "token" query parameter.
+	PasswordResetURL     string
+	EmailVerificationTTL time.Duration
+	// EmailVerificationURL is the page that verification emails link to;
+	// the token is added as the "token" query parameter.
+	EmailVerificationURL string
+	// MFAChallengeTTL is how long a user with MFA has to enter a code after
+	// entering their password.
+	MFAChallengeTTL time.Duration
+	// MFAIssuer names the service in authenticator apps.
+	MFAIssuer string
+}
+
+// DefaultTokenConfig issues access tokens valid for 15 minutes, refresh
+// tokens valid for 30 days, password reset tokens valid for an hour and email
+// verification tokens valid for a day and MFA challenges valid for 5 minutes,
+// and caches revocation lookups for 30 seconds.
+var DefaultTokenConfig = TokenConfig{
+	AccessTokenTTL:       15 * time.Minute,
+	RefreshTokenTTL:      30 * 24 * time.Hour,
+	RevocationCacheTTL:   30 * time.Second,
+	PasswordResetTTL:     time.Hour,
+	PasswordResetURL:     "http://localhost:8080/reset-password",
+	EmailVerificationTTL: 24 * time.Hour,
+	EmailVerificationURL: "http://localhost:8080/verify-email",
+	MFAChallengeTTL:      5 * time.Minute,
+	MFAIssuer:            "Todo List API",
+}
+
+// TokenConfigFromEnv reads ACCESS_TOKEN_TTL, REFRESH_TOKEN_TTL,
+// REVOCATION_CACHE_TTL, PASSWORD_RESET_TTL, PASSWORD_RESET_URL,
+// EMAIL_VERIFICATION_TTL, EMAIL_VERIFICATION_URL, MFA_CHALLENGE_TTL and
+// MFA_ISSUER, falling back to DefaultTokenConfig for unset values.
+func TokenConfigFromEnv() (TokenConfig, error) {
+	cfg := DefaultTokenConfig
+	for _, v := range []struct {
+		name string
+		ttl  *time.Duration
+	}{
+		{"ACCESS_TOKEN_TTL", &cfg.AccessTokenTTL},
+		{"REFRESH_TOKEN_TTL", &cfg.RefreshTokenTTL},
+		{"REVOCATION_CACHE_TTL", &cfg.RevocationCacheTTL},
+		{"PASSWORD_RESET_TTL", &cfg.PasswordResetTTL},
+		{"EMAIL_VERIFICATION_TTL", &cfg.EmailVerificationTTL},
+		{"MFA_CHALLENGE_TTL", &cfg.MFAChallengeTTL},
+	} {
+		s := os.Getenv(v.name)
+		if s == "" {
+			continue
+		}
+		ttl, err := time.ParseDuration(s)
+		if err != nil || ttl <= 0 {
+			return cfg, fmt.Errorf("invalid %s %q", v.name, s)
+		}
+		*v.ttl = ttl
+	}
+	if v := os.Getenv("PASSWORD_RESET_URL"); v != "" {
+		cfg.PasswordResetURL = v
+	}
+	if v := os.Getenv("EMAIL_VERIFICATION_URL"); v != "" {
+		cfg.EmailVerificationURL = v
+	}
+	if v := os.Getenv("MFA_ISSUER"); v != "" {
+		cfg.MFAIssuer = v
+	}
+	return cfg, nil
+}
+
+// JWTSecret returns the key used to sign and verify access tokens, read from
+// JWT_SECRET. It is empty when the variable is unset; the server refuses to
+// start in that case.
+func JWTSecret() []byte {
+	return []byte(os.Getenv("JWT_SECRET"))
+}
+
+// generateAccessToken creates a JWT for userID that expires after ttl. The
+// jti claim identifies the token for revocation; iat has millisecond
+// precision so that tokens issued right after a user-wide revocation are
+// not caught by it. scope lists the granted scopes separated by spaces, as
+// in OAuth 2.0.
+func generateAccessToken(userID string, scopes []string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":     primitive.NewObjectID().Hex(),
+		"user_id": userID,
+		"scope":   strings.Join(scopes, " "),
+		"iat":     float64(now.UnixMilli()) / 1000,
+		"exp":     now.Add(ttl).Unix(),
+	})
+	return token.SignedString(JWTSecret())
+}
+
+// newRefreshToken returns a random opaque refresh token and the record to
+// store for it.
+func newRefreshToken(userID, familyID primitive.ObjectID, ttl time.Duration) (string, *models.RefreshToken, error) {
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return raw, &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// newOpaqueToken returns 256 random bits, base64url encoded.
+func newOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns the hex SHA-256 of an opaque token. Tokens are random,
+// so a fast unsalted hash is enough to keep stored values useless if leaked.
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
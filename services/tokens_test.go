@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-list-api/models"
+	"todo-list-api/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeRefreshTokens keeps refresh tokens in memory.
+type fakeRefreshTokens struct {
+	repository.RefreshTokenRepository
+	tokens []models.RefreshToken
+}
+
+func (r *fakeRefreshTokens) Create(token *models.RefreshToken) error {
+	token.ID = primitive.NewObjectID()
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+func (r *fakeRefreshTokens) FindByHash(hash string) (*models.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, repository.ErrRefreshTokenNotFound
+}
+
+func (r *fakeRefreshTokens) Rotate(id primitive.ObjectID) (bool, error) {
+	for i := range r.tokens {
+		if t := &r.tokens[i]; t.ID == id && t.RotatedAt == nil && t.RevokedAt == nil {
+			now := time.Now()
+			t.RotatedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRefreshTokens) RevokeFamily(familyID primitive.ObjectID) error {
+	now := time.Now()
+	for i := range r.tokens {
+		if t := &r.tokens[i]; t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokens) RevokeUser(userID primitive.ObjectID) error {
+	now := time.Now()
+	for i := range r.tokens {
+		if t := &r.tokens[i]; t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// fakeTokenUsers finds an enabled user with any ID.
+type fakeTokenUsers struct {
+	repository.UserRepository
+}
+
+func (fakeTokenUsers) FindByID(id primitive.ObjectID) (*models.User, error) {
+	return &models.User{ID: id}, nil
+}
+
+func newTokenTestService(t *testing.T) (*authService, *fakeRefreshTokens) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	tokens := &fakeRefreshTokens{}
+	return &authService{userRepo: fakeTokenUsers{}, refreshTokenRepo: tokens, tokenConfig: DefaultTokenConfig}, tokens
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	s, tokens := newTokenTestService(t)
+	first, err := s.issueTokens(&models.User{ID: primitive.NewObjectID()}, primitive.NewObjectID())
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+	if tokens.tokens[0].TokenHash == first.RefreshToken || tokens.tokens[0].TokenHash != hashToken(first.RefreshToken) {
+		t.Errorf("stored refresh token %q, want only its hash", tokens.tokens[0].TokenHash)
+	}
+
+	second, err := s.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == "" || second.ExpiresIn != DefaultTokenConfig.AccessTokenTTL {
+		t.Errorf("Refresh() = %+v, want a new token pair", second)
+	}
+	if tokens.tokens[0].RotatedAt == nil || tokens.tokens[1].FamilyID != tokens.tokens[0].FamilyID {
+		t.Errorf("tokens after refresh = %+v, want the first rotated and the second in its family", tokens.tokens)
+	}
+	if _, err := s.Refresh(second.RefreshToken); err != nil {
+		t.Errorf("Refresh(rotated token) error = %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	s, tokens := newTokenTestService(t)
+	other, err := s.issueTokens(&models.User{ID: primitive.NewObjectID()}, primitive.NewObjectID())
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+	first, err := s.issueTokens(&models.User{ID: primitive.NewObjectID()}, primitive.NewObjectID())
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+	second, err := s.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if _, err := s.Refresh(first.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Refresh(used token) error = %v, want ErrRefreshTokenReused", err)
+	}
+	if _, err := s.Refresh(second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh(token from revoked family) error = %v, want ErrInvalidRefreshToken", err)
+	}
+	if tokens.tokens[0].RevokedAt != nil {
+		t.Error("reuse revoked another login's family")
+	}
+	if _, err := s.Refresh(other.RefreshToken); err != nil {
+		t.Errorf("Refresh(other family) error = %v", err)
+	}
+}
+
+func TestRefreshRejectsUnknownAndExpiredTokens(t *testing.T) {
+	s, tokens := newTokenTestService(t)
+	pair, err := s.issueTokens(&models.User{ID: primitive.NewObjectID()}, primitive.NewObjectID())
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+	tokens.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	for name, token := range map[string]string{"expired": pair.RefreshToken, "unknown": "not-a-token"} {
+		if _, err := s.Refresh(token); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("Refresh(%s) error = %v, want ErrInvalidRefreshToken", name, err)
+		}
+	}
+	if tokens.tokens[0].RotatedAt != nil {
+		t.Error("an expired token was rotated")
+	}
+}
+
+func TestTokenConfigFromEnv(t *testing.T) {
+	t.Setenv("ACCESS_TOKEN_TTL", "5m")
+	t.Setenv("REFRESH_TOKEN_TTL", "")
+	cfg, err := TokenConfigFromEnv()
+	if err != nil || cfg.AccessTokenTTL != 5*time.Minute || cfg.RefreshTokenTTL != DefaultTokenConfig.RefreshTokenTTL {
+		t.Errorf("TokenConfigFromEnv() = %+v, %v", cfg, err)
+	}
+	t.Setenv("REFRESH_TOKEN_TTL", "-1h")
+	if _, err := TokenConfigFromEnv(); err == nil {
+		t.Error("TokenConfigFromEnv(negative TTL) succeeded")
+	}
+}
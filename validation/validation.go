@@ -0,0 +1,61 @@
+// Package validation configures the request validator used by Gin's binding
+// tags and turns validation failures into field-level error messages.
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var registerOnce sync.Once
+
+// Register adds the custom validators and reports fields by their JSON names.
+// It is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+		// The tag names are fixed, so registration cannot fail.
+		_ = v.RegisterValidation("notblank", notBlank)
+		_ = v.RegisterValidation("timezone", timezone)
+	})
+}
+
+// Struct validates v against its binding tags, as Gin does for request bodies.
+func Struct(v interface{}) error {
+	Register()
+	return binding.Validator.ValidateStruct(v)
+}
+
+// notBlank rejects strings made only of whitespace.
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimFunc(fl.Field().String(), unicode.IsSpace) != ""
+}
+
+// timezone accepts IANA timezone names such as Europe/Berlin.
+func timezone(fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+	if name == "" {
+		return true
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
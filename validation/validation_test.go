@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"todo-list-api/dto"
+	"todo-list-api/models"
+)
+
+func TestStructReportsFieldErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want Errors
+	}{
+		{"valid todo", &dto.TodoRequest{Title: "Buy milk", Labels: []string{"errands"}, Timezone: "UTC"}, nil},
+		{"missing title", &dto.TodoRequest{}, Errors{"title": "is required"}},
+		{"blank title", &dto.TodoRequest{Title: " \t"}, Errors{"title": "must not be blank"}},
+		{"title at the limit", &dto.TodoRequest{Title: strings.Repeat("a", 200)}, nil},
+		{"title too long", &dto.TodoRequest{Title: strings.Repeat("a", 201)}, Errors{"title": "must be at most 200 characters long"}},
+		{"too many labels", &dto.TodoRequest{Title: "x", Labels: make([]string, 21)}, Errors{"labels": "must contain at most 20 items"}},
+		{"label too long", &dto.TodoRequest{Title: "x", Labels: []string{"ok", strings.Repeat("l", 51)}}, Errors{"labels[1]": "must be at most 50 characters long"}},
+		{"blank checklist item", &dto.TodoRequest{Title: "x", Checklist: []dto.ChecklistItemRequest{{Text: "eggs"}, {Text: " "}}}, Errors{"checklist[1].text": "must not be blank"}},
+		{"too many checklist items", &dto.TodoRequest{Title: "x", Checklist: make([]dto.ChecklistItemRequest, 101)}, Errors{"checklist": "must contain at most 100 items"}},
+		{"unknown status and timezone", &dto.TodoRequest{Title: "x", Status: "later", Timezone: "Mars/Base"}, Errors{
+			"status":   "must be one of open, in_progress, done, cancelled",
+			"timezone": "must be an IANA timezone such as Europe/Berlin",
+		}},
+		{"short password", &dto.RegisterRequest{Email: "john@doe.com", Password: "secret"}, Errors{"password": "must be at least 8 characters long"}},
+		{"bad email", &dto.RegisterRequest{Email: "john", Password: "password"}, Errors{"email": "must be a valid email address"}},
+		{"smart list sort", &dto.SmartListRequest{Name: "Work", Filter: models.SmartListFilter{Sort: "random"}}, Errors{"filter.sort": "must be one of priority, due_at, created_at, updated_at, title"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.v)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Struct() error = %v, want nil", err)
+				}
+				return
+			}
+			got, ok := FieldErrors(err)
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldErrors(Struct()) = %v, %v; want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldErrorsFromJSON(t *testing.T) {
+	var todo dto.TodoRequest
+	err := json.Unmarshal([]byte(`{"title":42}`), &todo)
+	if got, ok := FieldErrors(err); !ok || !reflect.DeepEqual(got, Errors{"title": "must be a string"}) {
+		t.Errorf("FieldErrors(type error) = %v, %v", got, ok)
+	}
+	if _, ok := FieldErrors(json.Unmarshal([]byte(`{`), &todo)); ok {
+		t.Error("FieldErrors(syntax error) reported field errors")
+	}
+	if got := (Errors{"b": "is invalid", "a": "is required"}).Error(); got != "validation failed: a is required; b is invalid" {
+		t.Errorf("Errors.Error() = %q", got)
+	}
+}